@@ -18,6 +18,10 @@ var dailyCmd = &cobra.Command{
 If text is provided as arguments, it will be automatically appended to the Daily Note.
 This makes for a powerful, lightning-fast quick-capture tool from your terminal.
 
+The folder, file name format and template come from the vault's Obsidian
+settings (Daily notes core plugin or the Periodic Notes plugin) unless
+overridden with --folder and --format.
+
 Examples:
   obx daily
   obx daily "Just had a great idea for the new project"`,
@@ -26,8 +30,13 @@ Examples:
 		v := vault.New(vaultPath)
 		ctx := context.Background()
 
+		folder, _ := cmd.Flags().GetString("folder")
+		format, _ := cmd.Flags().GetString("format")
+
 		// 1. Get or create the daily note
 		res, _, err := v.DailyNoteHandler(ctx, nil, vault.DailyNoteArgs{
+			Folder:          folder,
+			Format:          format,
 			CreateIfMissing: true,
 		})
 		if err != nil {
@@ -85,5 +94,7 @@ Examples:
 }
 
 func init() {
+	dailyCmd.Flags().StringP("folder", "d", "", "Folder for daily notes (default: from Obsidian settings, else daily)")
+	dailyCmd.Flags().StringP("format", "f", "", "Go date layout for the file name, e.g. 2006-01-02 (default: from Obsidian settings)")
 	rootCmd.AddCommand(dailyCmd)
 }
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/obx/internal/vault"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Work with the vault's link graph",
+	Long:  `Export the graph of notes, links, embeds and tags for analysis in other tools.`,
+}
+
+var graphExportCmd = &cobra.Command{
+	Use:   "export [vault_path]",
+	Short: "Export the note and tag graph",
+	Long: `Export the vault's notes and tags as a graph.
+
+Notes carry their folder, tags, word count and modification time. Edges are
+links, embeds, and tag edges from a note to each of its tags.
+
+Formats:
+  json     node-link JSON (networkx, d3-force)
+  graphml  GraphML (Gephi, yEd, Cytoscape)
+  gexf     GEXF 1.3 (Gephi)
+  dot      Graphviz DOT
+
+Examples:
+  obx graph export --format graphml -o vault.graphml
+  obx graph export --format dot --directory projects | dot -Tsvg > projects.svg`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		vaultPath := getVaultPath(args)
+		v := vault.New(vaultPath)
+
+		format, _ := cmd.Flags().GetString("format")
+		directory, _ := cmd.Flags().GetString("directory")
+		output, _ := cmd.Flags().GetString("output")
+
+		res, _, err := v.ExportGraphHandler(context.Background(), nil, vault.ExportGraphArgs{
+			Format:    format,
+			Directory: directory,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Graph export failed: %v\n", err)
+			os.Exit(1)
+		}
+		text := res.Content[0].(*mcp.TextContent).Text
+
+		if output == "" {
+			fmt.Print(text)
+			return
+		}
+		if err := os.WriteFile(output, []byte(text), 0o600); err != nil {
+			fmt.Fprintf(os.Stderr, "Graph export failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", output)
+	},
+}
+
+func init() {
+	graphCmd.AddCommand(graphExportCmd)
+	rootCmd.AddCommand(graphCmd)
+	graphExportCmd.Flags().StringP("format", "f", "json", "Output format: json, graphml, gexf or dot")
+	graphExportCmd.Flags().StringP("output", "o", "", "File to write (default stdout)")
+	graphExportCmd.Flags().StringP("directory", "d", "", "Only include notes under this vault folder")
+}
@@ -17,15 +17,23 @@ var searchCmd = &cobra.Command{
 	Long: `Search the Obsidian vault for text matching the given query.
 
 By default, prints the results in a human-readable list. 
+With --ranked, notes are ranked by BM25 relevance using a persisted
+full-text index stored under .obx/index in the vault.
+With --query, the query uses Obsidian search syntax, for example:
+  obx search --query 'tag:#project path:work/ "exact phrase" -draft (foo OR bar)'
 If the --json flag is provided, it returns structured, compact JSON 
 perfect for piping to jq or other tools.`,
-	Args: cobra.MinimumNArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if q, _ := cmd.Flags().GetString("query"); q != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		vaultPath := getVaultPath(nil)
 		v := vault.New(vaultPath)
 		ctx := context.Background()
 
-		query := args[0]
 		asJSON, _ := cmd.Flags().GetBool("json")
 
 		mode := "detailed"
@@ -33,11 +41,7 @@ perfect for piping to jq or other tools.`,
 			mode = "compact"
 		}
 
-		res, _, err := v.SearchVaultHandler(ctx, nil, vault.SearchArgs{
-			Query: query,
-			Mode:  mode,
-		})
-
+		res, err := runSearch(ctx, cmd, v, args, mode)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Search failed: %v\n", err)
 			os.Exit(1)
@@ -72,7 +76,40 @@ perfect for piping to jq or other tools.`,
 	},
 }
 
+// runSearch dispatches to the plain, ranked or Obsidian-syntax search handler
+// depending on the flags.
+func runSearch(ctx context.Context, cmd *cobra.Command, v *vault.Vault, args []string, mode string) (*mcp.CallToolResult, error) {
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	if query, _ := cmd.Flags().GetString("query"); query != "" {
+		res, _, err := v.SearchQueryHandler(ctx, nil, vault.SearchQueryArgs{
+			Query: query,
+			Limit: limit,
+			Mode:  mode,
+		})
+		return res, err
+	}
+
+	if ranked, _ := cmd.Flags().GetBool("ranked"); ranked {
+		res, _, err := v.SearchRankedHandler(ctx, nil, vault.SearchRankedArgs{
+			Query: args[0],
+			Limit: limit,
+			Mode:  mode,
+		})
+		return res, err
+	}
+
+	res, _, err := v.SearchVaultHandler(ctx, nil, vault.SearchArgs{
+		Query: args[0],
+		Mode:  mode,
+	})
+	return res, err
+}
+
 func init() {
 	rootCmd.AddCommand(searchCmd)
 	searchCmd.Flags().Bool("json", false, "Output results in JSON format")
+	searchCmd.Flags().Bool("ranked", false, "Rank notes by relevance (BM25) instead of listing matching lines")
+	searchCmd.Flags().IntP("limit", "l", 20, "Maximum results to return (with --ranked or --query)")
+	searchCmd.Flags().StringP("query", "q", "", "Search using Obsidian query syntax (tag:, path:, \"phrase\", -term, OR, line:(...), section:(...), task-todo:(...))")
 }
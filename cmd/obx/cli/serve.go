@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/zach-snell/obx/internal/config"
 	mcpserver "github.com/zach-snell/obx/internal/server"
+	"github.com/zach-snell/obx/internal/vault"
 )
 
 var serveCmd = &cobra.Command{
@@ -45,7 +46,22 @@ if --http is provided.`,
 		}
 
 		// Create and configure MCP server
-		s := mcpserver.New(vaultPath, disabledTools, allowSwitching, allowedVaults)
+		v := vault.New(vaultPath)
+		if allowedVaults != nil {
+			v.SetAllowedVaults(allowedVaults)
+		}
+		if ignore, _ := cmd.Flags().GetStringSlice("ignore"); len(ignore) > 0 {
+			v.SetIgnorePatterns(ignore)
+		}
+		if importPaths, _ := cmd.Flags().GetStringSlice("import-path"); len(importPaths) > 0 {
+			v.SetImportPaths(importPaths)
+		}
+		if embeddingsURL, _ := cmd.Flags().GetString("embeddings-url"); embeddingsURL != "" {
+			model, _ := cmd.Flags().GetString("embeddings-model")
+			v.SetEmbedder(vault.NewHTTPEmbedder(embeddingsURL, model, os.Getenv("OBX_EMBEDDINGS_API_KEY")))
+		}
+		s := mcpserver.NewForVault(v, disabledTools, allowSwitching)
+		watchInterval, _ := cmd.Flags().GetDuration("watch-interval")
 
 		// Determine transport
 		addr, _ := cmd.Flags().GetString("http")
@@ -54,9 +70,9 @@ if --http is provided.`,
 		}
 
 		if addr != "" {
-			serveHTTP(s, vaultPath, addr)
+			serveHTTP(s, v, addr, watchInterval)
 		} else {
-			serveStdio(s, vaultPath)
+			serveStdio(s, v, watchInterval)
 		}
 	},
 }
@@ -67,20 +83,39 @@ func init() {
 	serveCmd.Flags().StringSlice("disabled-tools", []string{}, "Comma-separated list of unified tools to disable (e.g., manage-folders,bulk-operations)")
 	serveCmd.Flags().Bool("allow-vault-switching", false, "Expose the manage-vaults MCP tool to allow agents to switch the active vault")
 	serveCmd.Flags().StringSlice("allowed-vaults", []string{}, "Optional comma-separated list of vault aliases an agent is allowed to switch to. If empty but switching is enabled, all vaults are allowed.")
+	serveCmd.Flags().StringSlice("ignore", []string{}, "Additional gitignore-style patterns to exclude from vault scans, on top of .obxignore and Obsidian's excluded files (e.g., 'archive/,*.excalidraw.md')")
+	serveCmd.Flags().StringSlice("import-path", []string{}, "Comma-separated directories outside the vault that manage-attachments may import files from (import is disabled when empty)")
+	serveCmd.Flags().String("embeddings-url", "", "OpenAI-compatible embeddings API for semantic search (e.g., http://localhost:11434/v1); uses the built-in offline embedder when empty. The API key is read from OBX_EMBEDDINGS_API_KEY")
+	serveCmd.Flags().String("embeddings-model", "text-embedding-3-small", "Embedding model to request from --embeddings-url")
+	serveCmd.Flags().Duration("watch-interval", vault.DefaultWatchInterval, "How often to poll the vault for external changes (0 disables the watcher)")
+}
+
+// startWatcher begins polling the vault for changes made outside the server
+// (Obsidian, git, sync tools). It returns a stop function, which is a no-op when
+// watching is disabled.
+func startWatcher(v *vault.Vault, interval time.Duration) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+	w := v.StartWatcher(context.Background(), interval)
+	return w.Stop
 }
 
-func serveStdio(s *mcp.Server, vaultPath string) {
+func serveStdio(s *mcp.Server, v *vault.Vault, watchInterval time.Duration) {
 	fmt.Fprintf(os.Stderr, "Starting Obsidian MCP Server (stdio)...\n")
-	fmt.Fprintf(os.Stderr, "Vault: %s\n", vaultPath)
+	fmt.Fprintf(os.Stderr, "Vault: %s\n", v.GetPath())
 
-	if err := s.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
+	stopWatcher := startWatcher(v, watchInterval)
+	err := s.Run(context.Background(), &mcp.StdioTransport{})
+	stopWatcher()
+	if err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
 
-func serveHTTP(s *mcp.Server, vaultPath, addr string) {
+func serveHTTP(s *mcp.Server, v *vault.Vault, addr string, watchInterval time.Duration) {
 	fmt.Fprintf(os.Stderr, "Starting Obsidian MCP Server (HTTP Streamable)...\n")
-	fmt.Fprintf(os.Stderr, "Vault: %s\n", vaultPath)
+	fmt.Fprintf(os.Stderr, "Vault: %s\n", v.GetPath())
 	fmt.Fprintf(os.Stderr, "Listening on %s\n", addr)
 
 	handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
@@ -96,7 +131,10 @@ func serveHTTP(s *mcp.Server, vaultPath, addr string) {
 		ReadHeaderTimeout: 30 * time.Second,
 	}
 
-	if err := srv.ListenAndServe(); err != nil {
+	stopWatcher := startWatcher(v, watchInterval)
+	err := srv.ListenAndServe()
+	stopWatcher()
+	if err != nil {
 		log.Fatalf("HTTP server error: %v", err)
 	}
 }
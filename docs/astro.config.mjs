@@ -35,6 +35,7 @@ export default defineConfig({
 						{ label: 'obx doctor', slug: 'cli/doctor' },
 						{ label: 'obx daily', slug: 'cli/daily' },
 						{ label: 'obx vault', slug: 'cli/vault' },
+						{ label: 'obx graph', slug: 'cli/graph' },
 					],
 				},
 				{
@@ -50,6 +51,7 @@ export default defineConfig({
 						{ label: 'manage-tasks', slug: 'mcp/manage-tasks' },
 						{ label: 'analyze-vault', slug: 'mcp/analyze-vault' },
 						{ label: 'manage-canvas', slug: 'mcp/manage-canvas' },
+						{ label: 'manage-attachments', slug: 'mcp/manage-attachments' },
 						{ label: 'manage-mocs', slug: 'mcp/manage-mocs' },
 						{ label: 'read-batch', slug: 'mcp/read-batch' },
 						{ label: 'manage-links', slug: 'mcp/manage-links' },
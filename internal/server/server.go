@@ -11,7 +11,12 @@ func New(vaultPath string, disabledTools []string, allowVaultSwitching bool, all
 	if allowedVaults != nil {
 		v.SetAllowedVaults(allowedVaults)
 	}
+	return NewForVault(v, disabledTools, allowVaultSwitching)
+}
 
+// NewForVault creates a new MCP server bound to an existing vault, so callers
+// can share it with background work such as the filesystem watcher
+func NewForVault(v *vault.Vault, disabledTools []string, allowVaultSwitching bool) *mcp.Server {
 	s := mcp.NewServer(
 		&mcp.Implementation{
 			Name:    "Obsidian Vault MCP",
@@ -60,7 +65,7 @@ func registerTools(s *mcp.Server, v *vault.Vault, disabledTools []string, allowV
 	if !isToolDisabled("search-vault", disabledTools) {
 		mcp.AddTool(s, &mcp.Tool{
 			Name:        "search-vault",
-			Description: "Unified search tool spanning text query, advanced block search, regex, dates, tags, inline-fields, and frontmatter queries",
+			Description: "Unified search tool spanning text query, ranked and semantic (meaning-based) search, similar notes, advanced block search, regex, dates, tags, inline-fields, and frontmatter queries",
 		}, v.SearchVaultMultiplexHandler)
 	}
 
@@ -88,14 +93,14 @@ func registerTools(s *mcp.Server, v *vault.Vault, disabledTools []string, allowV
 	if !isToolDisabled("manage-tasks", disabledTools) {
 		mcp.AddTool(s, &mcp.Tool{
 			Name:        "manage-tasks",
-			Description: "Unified tool for finding, toggling, and completing checkbox tasks across the vault",
+			Description: "Unified tool for finding, querying (Obsidian Tasks query language), toggling, and completing checkbox tasks across the vault",
 		}, v.ManageTasksMultiplexHandler)
 	}
 
 	if !isToolDisabled("analyze-vault", disabledTools) {
 		mcp.AddTool(s, &mcp.Tool{
 			Name:        "analyze-vault",
-			Description: "Unified analytical tool to get vault stats, detect broken links, orphans, stubs, and outdated notes",
+			Description: "Unified analytical tool to get vault stats, detect broken links, orphans, stubs, and outdated notes, find hub notes, clusters and paths between notes, and export the link graph",
 		}, v.AnalyzeVaultMultiplexHandler)
 	}
 
@@ -113,6 +118,13 @@ func registerTools(s *mcp.Server, v *vault.Vault, disabledTools []string, allowV
 		}, v.ManageMocsMultiplexHandler)
 	}
 
+	if !isToolDisabled("manage-attachments", disabledTools) {
+		mcp.AddTool(s, &mcp.Tool{
+			Name:        "manage-attachments",
+			Description: "Unified tool for images, PDFs and other attachments: list, find unused files and broken embeds, move with link updates, and import",
+		}, v.ManageAttachmentsMultiplexHandler)
+	}
+
 	if !isToolDisabled("read-batch", disabledTools) {
 		mcp.AddTool(s, &mcp.Tool{
 			Name:        "read-batch",
@@ -123,7 +135,7 @@ func registerTools(s *mcp.Server, v *vault.Vault, disabledTools []string, allowV
 	if !isToolDisabled("manage-links", disabledTools) {
 		mcp.AddTool(s, &mcp.Tool{
 			Name:        "manage-links",
-			Description: "Unified tool covering backlinks, forward-links, and AI link suggestions for notes",
+			Description: "Unified tool covering backlinks, forward-links, local graphs, and AI link suggestions for notes",
 		}, v.ManageLinksMultiplexHandler)
 	}
 
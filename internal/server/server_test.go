@@ -46,8 +46,8 @@ func TestServerRegistration(t *testing.T) {
 
 	t.Logf("Found %d tools registered", len(result.Tools))
 
-	if len(result.Tools) != 14 {
-		t.Errorf("Expected 14 tools due to disable parameter, got %d", len(result.Tools))
+	if len(result.Tools) != 15 {
+		t.Errorf("Expected 15 tools due to disable parameter, got %d", len(result.Tools))
 	}
 
 	if toolMap["search-vault"] {
@@ -63,6 +63,7 @@ func TestServerRegistration(t *testing.T) {
 		"manage-tasks",
 		"analyze-vault",
 		"manage-canvas",
+		"manage-attachments",
 		"manage-mocs",
 		"read-batch",
 		"manage-links",
@@ -116,8 +117,8 @@ func TestManageVaultsRegistration(t *testing.T) {
 		toolMap[tool.Name] = true
 	}
 
-	if len(result.Tools) != 16 {
-		t.Errorf("Expected 16 tools with vault switching enabled, got %d", len(result.Tools))
+	if len(result.Tools) != 17 {
+		t.Errorf("Expected 17 tools with vault switching enabled, got %d", len(result.Tools))
 	}
 
 	if !toolMap["manage-vaults"] {
@@ -40,35 +40,21 @@ func (v *Vault) FindStubsHandler(ctx context.Context, req *mcp.CallToolRequest,
 		return nil, nil, fmt.Errorf("search path must be within vault")
 	}
 
-	var stubs []stubInfo
-
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		body := RemoveFrontmatter(string(content))
-		wordCount := len(strings.Fields(body))
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan vault: %v", err)
+	}
 
+	var stubs []stubInfo
+	for _, note := range notes {
+		wordCount := len(strings.Fields(note.Body))
 		if wordCount <= maxWords {
-			relPath, _ := filepath.Rel(v.GetPath(), path)
 			stubs = append(stubs, stubInfo{
-				path:      relPath,
+				path:      note.RelPath,
 				wordCount: wordCount,
-				modTime:   info.ModTime(),
+				modTime:   note.ModTime,
 			})
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to scan vault: %v", err)
 	}
 
 	if len(stubs) == 0 {
@@ -135,7 +121,7 @@ func (v *Vault) FindOutdatedHandler(ctx context.Context, req *mcp.CallToolReques
 	cutoff := time.Now().AddDate(0, 0, -days)
 	var outdated []outdatedInfo
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+	err := v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
 			return nil
 		}
@@ -215,27 +201,21 @@ func (v *Vault) UnlinkedMentionsHandler(ctx context.Context, req *mcp.CallToolRe
 	noteName := strings.TrimSuffix(filepath.Base(targetPath), ".md")
 	noteNameLower := strings.ToLower(noteName)
 
-	var mentions []unlinkedMention
-
-	err := filepath.Walk(v.GetPath(), func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
+	notes, err := v.indexedNotes(v.GetPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan vault: %v", err)
+	}
+	targetRel, _ := filepath.Rel(v.GetPath(), fullPath)
 
+	var mentions []unlinkedMention
+	for _, note := range notes {
 		// Skip the target note itself
-		if path == fullPath {
-			return nil
+		if note.RelPath == targetRel {
+			continue
 		}
+		relPath := note.RelPath
 
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		relPath, _ := filepath.Rel(v.GetPath(), path)
-		lines := strings.Split(string(content), "\n")
-
-		for i, line := range lines {
+		for i, line := range note.Lines {
 			lineLower := strings.ToLower(line)
 
 			// Check if the note name appears in this line
@@ -261,12 +241,6 @@ func (v *Vault) UnlinkedMentionsHandler(ctx context.Context, req *mcp.CallToolRe
 				context:  strings.TrimSpace(ctxLine),
 			})
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to scan vault: %v", err)
 	}
 
 	if len(mentions) == 0 {
@@ -302,6 +276,10 @@ func (v *Vault) UnlinkedMentionsHandler(ctx context.Context, req *mcp.CallToolRe
 	}, nil, nil
 }
 
+// minSuggestSimilarity is the similarity above which a note with related
+// content is suggested as a link.
+const minSuggestSimilarity = 0.3
+
 // linkSuggestion represents a suggested link
 type linkSuggestion struct {
 	targetNote string
@@ -327,7 +305,7 @@ func (v *Vault) SuggestLinksHandler(ctx context.Context, req *mcp.CallToolReques
 		return nil, nil, fmt.Errorf("path must be within vault")
 	}
 
-	content, err := os.ReadFile(fullPath)
+	source, err := v.indexedNote(notePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil, fmt.Errorf("note not found: %s", notePath)
@@ -335,33 +313,29 @@ func (v *Vault) SuggestLinksHandler(ctx context.Context, req *mcp.CallToolReques
 		return nil, nil, fmt.Errorf("failed to read note: %v", err)
 	}
 
-	body := RemoveFrontmatter(string(content))
-	bodyLower := strings.ToLower(body)
-	existingLinks := ExtractWikilinks(body)
-	existingSet := make(map[string]bool)
-	for _, l := range existingLinks {
-		existingSet[strings.ToLower(l)] = true
-	}
+	bodyLower := strings.ToLower(source.Body)
+	existingSet := linkedNoteNames(ExtractLinks(source.Body))
 
 	suggestions := make(map[string]*linkSuggestion)
 
 	// Scan all notes and find potential links
-	err = filepath.Walk(v.GetPath(), func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
+	notes, err := v.indexedNotes(v.GetPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan vault: %v", err)
+	}
 
-		if path == fullPath {
-			return nil
+	for _, note := range notes {
+		if note.RelPath == source.RelPath {
+			continue
 		}
 
-		relPath, _ := filepath.Rel(v.GetPath(), path)
-		otherName := strings.TrimSuffix(filepath.Base(path), ".md")
+		relPath := note.RelPath
+		otherName := strings.TrimSuffix(filepath.Base(relPath), ".md")
 		otherNameLower := strings.ToLower(otherName)
 
 		// Skip if already linked
 		if existingSet[otherNameLower] {
-			return nil
+			continue
 		}
 
 		// Check if the other note's name appears in our content
@@ -373,12 +347,36 @@ func (v *Vault) SuggestLinksHandler(ctx context.Context, req *mcp.CallToolReques
 				strength:   count * 10,
 			}
 		}
+	}
 
-		return nil
-	})
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to scan vault: %v", err)
+	// Notes about the same thing in different words. Building the vector
+	// index embeds the whole vault, so that only happens when asked for;
+	// otherwise an existing index is used, and mention-based suggestions are
+	// returned even if it cannot be updated. An explicit request reports the
+	// embedder's error instead.
+	var related []vectorHit
+	if args.Semantic || v.hasVectorIndex() {
+		related, err = v.relatedNotes(ctx, source.RelPath, "")
+		if err != nil && args.Semantic {
+			return nil, nil, fmt.Errorf("semantic suggestions failed: %v", err)
+		}
+	}
+	for _, hit := range related {
+		otherName := strings.TrimSuffix(filepath.Base(hit.path), ".md")
+		if hit.score < minSuggestSimilarity || existingSet[strings.ToLower(otherName)] {
+			continue
+		}
+		reason := fmt.Sprintf("related content (similarity %.2f)", hit.score)
+		if s, ok := suggestions[hit.path]; ok {
+			s.reason += ", " + reason
+			s.strength += int(hit.score * 50)
+			continue
+		}
+		suggestions[hit.path] = &linkSuggestion{
+			targetNote: hit.path,
+			reason:     reason,
+			strength:   int(hit.score * 50),
+		}
 	}
 
 	if len(suggestions) == 0 {
@@ -0,0 +1,519 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// attachment is a non-note file in the vault: an image, PDF, audio file or
+// anything else that is not markdown or a canvas.
+type attachment struct {
+	RelPath string
+	Size    int64
+}
+
+// SetImportPaths sets the directories outside the vault that attachments may
+// be imported from. Import is disabled while the list is empty.
+func (v *Vault) SetImportPaths(paths []string) {
+	cleaned := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if abs, err := filepath.Abs(filepath.Clean(p)); err == nil {
+			cleaned = append(cleaned, abs)
+		}
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.importPaths = cleaned
+}
+
+// isAttachmentFile reports whether a vault file counts as an attachment.
+// Dotfiles such as .obxignore are configuration, not attachments.
+func isAttachmentFile(relPath string) bool {
+	base := filepath.Base(relPath)
+	if strings.HasPrefix(base, ".") {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(base))
+	return ext != "" && ext != ".md" && ext != ".canvas"
+}
+
+// parseExtensions turns "png, .JPG" into a set of lowercased extensions
+// with the leading dot. An empty string matches everything.
+func parseExtensions(list string) map[string]bool {
+	exts := make(map[string]bool)
+	for _, ext := range strings.Split(list, ",") {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts[ext] = true
+	}
+	return exts
+}
+
+// listAttachments returns the attachments under searchPath, sorted by path.
+func (v *Vault) listAttachments(searchPath, extensions string) ([]attachment, error) {
+	exts := parseExtensions(extensions)
+	var attachments []attachment
+	err := v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		relPath, _ := filepath.Rel(v.GetPath(), path)
+		if !isAttachmentFile(relPath) {
+			return nil
+		}
+		if len(exts) > 0 && !exts[strings.ToLower(filepath.Ext(relPath))] {
+			return nil
+		}
+		attachments = append(attachments, attachment{RelPath: relPath, Size: info.Size()})
+		return nil
+	})
+	sort.Slice(attachments, func(i, j int) bool { return attachments[i].RelPath < attachments[j].RelPath })
+	return attachments, err
+}
+
+// attachmentSearchPath validates the optional folder filter.
+func (v *Vault) attachmentSearchPath(dir string) (string, error) {
+	searchPath := v.GetPath()
+	if dir != "" {
+		searchPath = filepath.Join(v.GetPath(), dir)
+	}
+	if !v.isPathSafe(searchPath) {
+		return "", fmt.Errorf("search path must be within vault")
+	}
+	return searchPath, nil
+}
+
+// ListAttachmentsHandler lists attachments, optionally filtered by folder
+// and extension
+func (v *Vault) ListAttachmentsHandler(ctx context.Context, req *mcp.CallToolRequest, args ListAttachmentsArgs) (*mcp.CallToolResult, any, error) {
+	searchPath, err := v.attachmentSearchPath(args.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachments, err := v.listAttachments(searchPath, args.Extension)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list attachments: %v", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatAttachmentList("Attachments", "No attachments found", attachments)},
+		},
+	}, nil, nil
+}
+
+// attachmentUsage counts the links to each vault file from notes, embeds and
+// plain links alike, plus file nodes in canvases.
+func (v *Vault) attachmentUsage(resolver *linkResolver) map[string]int {
+	usage := make(map[string]int)
+	for relPath, note := range resolver.notes {
+		for _, link := range parseNoteLinks(note.Content) {
+			if res := resolver.resolve(link.Target, relPath); res.Target != "" {
+				usage[res.Target]++
+			}
+		}
+	}
+
+	for _, relPath := range resolver.paths {
+		if !strings.HasSuffix(strings.ToLower(relPath), ".canvas") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(v.GetPath(), relPath))
+		if err != nil {
+			continue
+		}
+		var canvas Canvas
+		if json.Unmarshal(data, &canvas) != nil {
+			continue
+		}
+		for _, node := range canvas.Nodes {
+			if node.Type == "file" && node.File != "" {
+				if target, _ := resolver.resolvePath(node.File, relPath); target != "" {
+					usage[target]++
+				}
+			}
+		}
+	}
+	return usage
+}
+
+// moveCanvasFileNodes points canvas file nodes that resolve to oldPath at
+// newPath, editing the JSON in place so the rest of each canvas keeps its
+// formatting. It returns the canvases that changed.
+func (v *Vault) moveCanvasFileNodes(resolver *linkResolver, oldPath, newPath string, dryRun bool) []string {
+	newJSON, _ := json.Marshal(filepath.ToSlash(newPath))
+	var changed []string
+	for _, relPath := range resolver.paths {
+		if !strings.HasSuffix(strings.ToLower(relPath), ".canvas") {
+			continue
+		}
+		fullPath := filepath.Join(v.GetPath(), relPath)
+		data, err := os.ReadFile(fullPath)
+		if err != nil {
+			continue
+		}
+		var canvas Canvas
+		if json.Unmarshal(data, &canvas) != nil {
+			continue
+		}
+
+		content := string(data)
+		for _, node := range canvas.Nodes {
+			if node.Type != "file" || node.File == "" {
+				continue
+			}
+			if target, _ := resolver.resolvePath(node.File, relPath); target != oldPath {
+				continue
+			}
+			oldJSON, _ := json.Marshal(node.File)
+			re := regexp.MustCompile(`("file"\s*:\s*)` + regexp.QuoteMeta(string(oldJSON)))
+			content = re.ReplaceAllString(content, "${1}"+strings.ReplaceAll(string(newJSON), "$", "$$"))
+		}
+		if content == string(data) {
+			continue
+		}
+		if !dryRun {
+			if err := os.WriteFile(fullPath, []byte(content), 0o600); err != nil {
+				continue
+			}
+		}
+		changed = append(changed, relPath)
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// UnusedAttachmentsHandler finds attachments that no note or canvas links to
+func (v *Vault) UnusedAttachmentsHandler(ctx context.Context, req *mcp.CallToolRequest, args ListAttachmentsArgs) (*mcp.CallToolResult, any, error) {
+	searchPath, err := v.attachmentSearchPath(args.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachments, err := v.listAttachments(searchPath, args.Extension)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list attachments: %v", err)
+	}
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan vault: %v", err)
+	}
+
+	usage := v.attachmentUsage(resolver)
+	var unused []attachment
+	for _, a := range attachments {
+		if usage[a.RelPath] == 0 {
+			unused = append(unused, a)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatAttachmentList("Unused Attachments", "No unused attachments found", unused)},
+		},
+	}, nil, nil
+}
+
+// BrokenAttachmentsHandler finds embeds of images and other files that do
+// not exist
+func (v *Vault) BrokenAttachmentsHandler(ctx context.Context, req *mcp.CallToolRequest, args ListAttachmentsArgs) (*mcp.CallToolResult, any, error) {
+	searchPath, err := v.attachmentSearchPath(args.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan vault: %v", err)
+	}
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan for broken embeds: %v", err)
+	}
+
+	exts := parseExtensions(args.Extension)
+	var broken []brokenLink
+	for _, note := range notes {
+		if len(note.Embeds) == 0 {
+			continue
+		}
+		for i, line := range note.Lines {
+			for _, link := range parseNoteLinks(line) {
+				if !link.Embed {
+					continue
+				}
+				res := resolver.resolve(link.Target, note.RelPath)
+				if res.Target != "" || !isAttachmentLink(res) {
+					continue
+				}
+				if len(exts) > 0 && !exts[strings.ToLower(filepath.Ext(res.Ref.Path))] {
+					continue
+				}
+				broken = append(broken, brokenLink{source: note.RelPath, text: line[link.Start:link.End], line: i + 1})
+			}
+		}
+	}
+
+	text := "No broken attachment embeds found! Every embedded file exists."
+	if len(broken) > 0 {
+		text = formatLinkGroups("Broken Attachment Embeds", broken)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// MoveAttachmentHandler moves or renames an attachment and rewrites every
+// link and embed that points at it
+func (v *Vault) MoveAttachmentHandler(ctx context.Context, req *mcp.CallToolRequest, args MoveAttachmentArgs) (*mcp.CallToolResult, any, error) {
+	sourcePath := filepath.Clean(args.Source)
+	destPath := args.Destination
+	if destPath == "" {
+		return nil, nil, fmt.Errorf("destination is required")
+	}
+	if !isAttachmentFile(sourcePath) {
+		return nil, nil, fmt.Errorf("%s is not an attachment; use manage-notes to move notes", sourcePath)
+	}
+
+	sourceFullPath := filepath.Join(v.GetPath(), sourcePath)
+	destFullPath := filepath.Join(v.GetPath(), destPath)
+	if !v.isPathSafe(sourceFullPath) || !v.isPathSafe(destFullPath) {
+		return nil, nil, fmt.Errorf("paths must be within vault")
+	}
+
+	// A destination folder keeps the file name
+	if info, err := os.Stat(destFullPath); (err == nil && info.IsDir()) || strings.HasSuffix(destPath, "/") {
+		destFullPath = filepath.Join(destFullPath, filepath.Base(sourcePath))
+	}
+	destPath, _ = filepath.Rel(v.GetPath(), destFullPath)
+	if !isAttachmentFile(destPath) {
+		return nil, nil, fmt.Errorf("destination %s is not an attachment path; keep an attachment extension", destPath)
+	}
+	ignore := v.vaultIgnore()
+	for _, p := range []string{sourcePath, destPath} {
+		if ignore.ignored(filepath.ToSlash(p)) {
+			return nil, nil, fmt.Errorf("%s is in an ignored path", p)
+		}
+	}
+
+	if _, err := os.Stat(sourceFullPath); os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("attachment not found: %s", sourcePath)
+	}
+	if _, err := os.Stat(destFullPath); err == nil {
+		return nil, nil, fmt.Errorf("destination already exists: %s", destPath)
+	}
+
+	// Resolve links against the vault before anything moves
+	rewriter, err := v.newLinkRewriter()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan links: %v", err)
+	}
+
+	if !args.DryRun {
+		if err := os.MkdirAll(filepath.Dir(destFullPath), 0o755); err != nil {
+			return nil, nil, fmt.Errorf("failed to create directory: %v", err)
+		}
+		if err := os.Rename(sourceFullPath, destFullPath); err != nil {
+			return nil, nil, fmt.Errorf("failed to move attachment: %v", err)
+		}
+	}
+
+	updatedFiles := rewriter.apply(map[string]string{sourcePath: destPath}, args.DryRun)
+	canvases := v.moveCanvasFileNodes(rewriter.resolver, sourcePath, destPath, args.DryRun)
+
+	result := fmt.Sprintf("Moved %s → %s\nUpdated links in %d files", sourcePath, destPath, updatedFiles)
+	if args.DryRun {
+		result = fmt.Sprintf("Dry run: would move %s → %s\nWould update links in %d files", sourcePath, destPath, updatedFiles)
+	}
+	if len(canvases) > 0 {
+		verb := "Updated"
+		if args.DryRun {
+			verb = "Would update"
+		}
+		result += fmt.Sprintf("\n%s file nodes in %d canvases: %s", verb, len(canvases), strings.Join(canvases, ", "))
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil, nil
+}
+
+// importAllowed resolves symlinks in source and reports whether the result
+// lies in one of the configured import directories. Callers must only use
+// the returned path, so a link swapped after the check cannot point
+// elsewhere.
+func (v *Vault) importAllowed(source string) (string, bool, error) {
+	v.mu.RLock()
+	allowed := v.importPaths
+	v.mu.RUnlock()
+	if len(allowed) == 0 {
+		return "", false, fmt.Errorf("attachment import is disabled; start the server with --import-path to allow it")
+	}
+
+	resolved, err := filepath.EvalSymlinks(source)
+	if err != nil {
+		return "", false, fmt.Errorf("source not found: %s", source)
+	}
+	for _, dir := range allowed {
+		if resolvedDir, err := filepath.EvalSymlinks(dir); err == nil && isPathWithinBase(resolvedDir, resolved) {
+			return resolved, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// ImportAttachmentHandler copies a file from an allowed import directory into
+// the vault's attachment folder
+func (v *Vault) ImportAttachmentHandler(ctx context.Context, req *mcp.CallToolRequest, args ImportAttachmentArgs) (*mcp.CallToolResult, any, error) {
+	if args.Source == "" {
+		return nil, nil, fmt.Errorf("source is required")
+	}
+	source, err := filepath.Abs(args.Source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid source path: %v", err)
+	}
+	resolved, ok, err := v.importAllowed(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("source must be within an allowed import path")
+	}
+	info, err := os.Stat(resolved)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, nil, fmt.Errorf("source is not a regular file: %s", args.Source)
+	}
+
+	name := args.Name
+	if name == "" {
+		name = filepath.Base(source)
+	}
+	if strings.ContainsAny(name, `/\`) || !isAttachmentFile(name) {
+		return nil, nil, fmt.Errorf("invalid attachment name: %s", name)
+	}
+	if args.Note != "" && !v.isPathSafe(filepath.Join(v.GetPath(), args.Note)) {
+		return nil, nil, fmt.Errorf("note path must be within vault")
+	}
+
+	settings := v.obsidianSettings()
+	relPath := v.uniqueVaultPath(settings.attachmentPath(name, args.Note))
+	fullPath := filepath.Join(v.GetPath(), relPath)
+	if !v.isPathSafe(fullPath) {
+		return nil, nil, fmt.Errorf("attachment folder must be within vault")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create directory: %v", err)
+	}
+	if err := copyFile(resolved, fullPath); err != nil {
+		return nil, nil, fmt.Errorf("failed to import attachment: %v", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Imported %s → %s\nEmbed with: !%s", args.Source, relPath, settings.linkTo(args.Note, relPath))},
+		},
+	}, nil, nil
+}
+
+// uniqueVaultPath returns relPath, or "name 1.ext", "name 2.ext" and so on
+// when the file already exists, as Obsidian does for pasted attachments.
+func (v *Vault) uniqueVaultPath(relPath string) string {
+	ext := filepath.Ext(relPath)
+	stem := strings.TrimSuffix(relPath, ext)
+	candidate := relPath
+	for i := 1; ; i++ {
+		if _, err := os.Stat(filepath.Join(v.GetPath(), candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s %d%s", stem, i, ext)
+	}
+}
+
+// copyFile copies src to a new file at dst, failing if dst exists.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// formatAttachmentList formats attachments as markdown with their sizes
+// and a count per extension.
+func formatAttachmentList(title, empty string, attachments []attachment) string {
+	if len(attachments) == 0 {
+		return empty
+	}
+
+	var total int64
+	byExt := make(map[string]int)
+	for _, a := range attachments {
+		total += a.Size
+		byExt[strings.ToLower(filepath.Ext(a.RelPath))]++
+	}
+	exts := make([]string, 0, len(byExt))
+	for ext := range byExt {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s (%d files, %s)\n\n", title, len(attachments), formatFileSize(total))
+	for i, ext := range exts {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%s: %d", strings.TrimPrefix(ext, "."), byExt[ext])
+	}
+	sb.WriteString("\n\n")
+	for _, a := range attachments {
+		fmt.Fprintf(&sb, "- %s (%s)\n", a.RelPath, formatFileSize(a.Size))
+	}
+	return sb.String()
+}
+
+// formatFileSize formats a byte count as B, KB, MB or GB.
+func formatFileSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, suffix := float64(size)/unit, "KB"
+	for _, next := range []string{"MB", "GB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
@@ -0,0 +1,186 @@
+package vault
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func setupAttachmentVault(t *testing.T) (*Vault, string) {
+	t.Helper()
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "assets/used.png", "png")
+	writeTestFile(t, dir, "assets/linked.pdf", "pdf")
+	writeTestFile(t, dir, "assets/orphan.jpg", "jpg")
+	writeTestFile(t, dir, "assets/board.png", "png")
+	writeTestFile(t, dir, "notes/a.md", "![[used.png]]\n[spec](../assets/linked.pdf)\n![[gone.png]]\n![[Missing note]]\n")
+	writeTestFile(t, dir, "board.canvas", `{"nodes":[{"id":"1","type":"file","file":"assets/board.png","x":0,"y":0,"width":100,"height":100}],"edges":[]}`)
+	writeTestFile(t, dir, ".obxignore", "")
+	return v, dir
+}
+
+func TestAttachmentReports(t *testing.T) {
+	v, _ := setupAttachmentVault(t)
+	ctx := context.Background()
+
+	result, _, err := v.ListAttachmentsHandler(ctx, nil, ListAttachmentsArgs{Extension: "png, .JPG"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "# Attachments (3 files, 9 B)") || !strings.Contains(text, "jpg: 1, png: 2") || strings.Contains(text, "linked.pdf") || strings.Contains(text, ".obxignore") {
+		t.Errorf("unexpected list:\n%s", text)
+	}
+
+	result, _, err = v.UnusedAttachmentsHandler(ctx, nil, ListAttachmentsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text = result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "(1 files") || !strings.Contains(text, filepath.Join("assets", "orphan.jpg")) {
+		t.Errorf("unexpected unused attachments:\n%s", text)
+	}
+
+	result, _, err = v.BrokenAttachmentsHandler(ctx, nil, ListAttachmentsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text = result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "- L3: ![[gone.png]]") || strings.Contains(text, "Missing note") {
+		t.Errorf("unexpected broken embeds:\n%s", text)
+	}
+}
+
+func TestMoveAttachmentRewritesLinks(t *testing.T) {
+	v, dir := setupAttachmentVault(t)
+	ctx := context.Background()
+
+	result, _, err := v.MoveAttachmentHandler(ctx, nil, MoveAttachmentArgs{Source: "assets/linked.pdf", Destination: "docs/", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "Would update links in 1 files") {
+		t.Errorf("unexpected dry run output:\n%s", text)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "assets", "linked.pdf")); err != nil {
+		t.Errorf("dry run moved the file: %v", err)
+	}
+
+	if _, _, err := v.MoveAttachmentHandler(ctx, nil, MoveAttachmentArgs{Source: "assets/linked.pdf", Destination: "docs/"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := v.MoveAttachmentHandler(ctx, nil, MoveAttachmentArgs{Source: "assets/used.png", Destination: "assets/diagram.png"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "![[diagram.png]]\n[spec](../docs/linked.pdf)\n![[gone.png]]\n![[Missing note]]\n"
+	if got := readTestFile(t, dir, "notes/a.md"); got != want {
+		t.Errorf("notes/a.md = %q, want %q", got, want)
+	}
+
+	if _, _, err := v.MoveAttachmentHandler(ctx, nil, MoveAttachmentArgs{Source: "assets/diagram.png", Destination: "notes/diagram.md"}); err == nil {
+		t.Error("expected a non-attachment destination to be refused")
+	}
+	if _, _, err := v.MoveAttachmentHandler(ctx, nil, MoveAttachmentArgs{Source: "notes/a.md", Destination: "b.md"}); err == nil {
+		t.Error("expected moving a note to fail")
+	}
+}
+
+func TestMoveAttachmentUpdatesCanvases(t *testing.T) {
+	v, dir := setupAttachmentVault(t)
+	ctx := context.Background()
+
+	result, _, err := v.MoveAttachmentHandler(ctx, nil, MoveAttachmentArgs{Source: "assets/board.png", Destination: "images/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "Updated file nodes in 1 canvases: board.canvas") {
+		t.Errorf("unexpected move output:\n%s", text)
+	}
+	want := `{"nodes":[{"id":"1","type":"file","file":"images/board.png","x":0,"y":0,"width":100,"height":100}],"edges":[]}`
+	if got := readTestFile(t, dir, "board.canvas"); got != want {
+		t.Errorf("board.canvas = %s, want %s", got, want)
+	}
+}
+
+func TestMoveAttachmentRefusesIgnoredPaths(t *testing.T) {
+	v, dir := setupAttachmentVault(t)
+	writeTestFile(t, dir, ".obsidian/icon.png", "png")
+	writeTestFile(t, dir, ".obxignore", "private/\n")
+	ctx := context.Background()
+
+	for _, args := range []MoveAttachmentArgs{
+		{Source: ".obsidian/icon.png", Destination: "assets/"},
+		{Source: "assets/used.png", Destination: ".trash/"},
+		{Source: "assets/used.png", Destination: ".git/objects/used.png"},
+		{Source: "assets/used.png", Destination: "private/deep/"},
+	} {
+		if _, _, err := v.MoveAttachmentHandler(ctx, nil, args); err == nil || !strings.Contains(err.Error(), "ignored") {
+			t.Errorf("expected %s → %s to be refused, got %v", args.Source, args.Destination, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "assets", "used.png")); err != nil {
+		t.Errorf("refused move touched the source: %v", err)
+	}
+}
+
+func TestImportAttachment(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, ".obsidian/app.json", `{"attachmentFolderPath": "./files"}`)
+	ctx := context.Background()
+
+	outside := t.TempDir()
+	source := filepath.Join(outside, "photo.jpg")
+	if err := os.WriteFile(source, []byte("jpg"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	args := ImportAttachmentArgs{Source: source, Note: "trips/rome.md"}
+
+	if _, _, err := v.ImportAttachmentHandler(ctx, nil, args); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("expected import to be disabled, got %v", err)
+	}
+
+	v.SetImportPaths([]string{t.TempDir()})
+	if _, _, err := v.ImportAttachmentHandler(ctx, nil, args); err == nil {
+		t.Error("expected import outside allowed paths to fail")
+	}
+
+	v.SetImportPaths([]string{outside})
+	for _, want := range []string{"photo.jpg", "photo 1.jpg"} {
+		result, _, err := v.ImportAttachmentHandler(ctx, nil, args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "Embed with: ![["+want+"]]") {
+			t.Errorf("unexpected import output:\n%s", text)
+		}
+		if got := readTestFile(t, dir, filepath.Join("trips", "files", want)); got != "jpg" {
+			t.Errorf("imported %s = %q", want, got)
+		}
+	}
+	// Symlinks are checked and copied by their target
+	secret := filepath.Join(t.TempDir(), "secret.png")
+	if err := os.WriteFile(secret, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	escape := filepath.Join(outside, "escape.png")
+	if err := os.Symlink(secret, escape); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := v.ImportAttachmentHandler(ctx, nil, ImportAttachmentArgs{Source: escape}); err == nil {
+		t.Error("expected a symlink out of the import path to be refused")
+	}
+	link := filepath.Join(t.TempDir(), "link.jpg")
+	if err := os.Symlink(source, link); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := v.ImportAttachmentHandler(ctx, nil, ImportAttachmentArgs{Source: link, Name: "linked.jpg"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, dir, filepath.Join("files", "linked.jpg")); got != "jpg" {
+		t.Errorf("imported through link = %q", got)
+	}
+}
@@ -91,8 +91,8 @@ func (v *Vault) ReadNotesHandler(ctx context.Context, req *mcp.CallToolRequest,
 			fm := ParseFrontmatter(contentStr)
 			if len(fm) > 0 {
 				sb.WriteString("**Frontmatter:**\n```yaml\n")
-				for k, val := range fm {
-					fmt.Fprintf(&sb, "%s: %v\n", k, val)
+				for _, k := range sortedFrontmatterKeys(fm) {
+					fmt.Fprintf(&sb, "%s: %s\n", k, frontmatterDisplay(fm[k]))
 				}
 				sb.WriteString("```\n\n")
 			}
@@ -100,6 +100,9 @@ func (v *Vault) ReadNotesHandler(ctx context.Context, req *mcp.CallToolRequest,
 
 		// Remove frontmatter for content display
 		body := RemoveFrontmatter(contentStr)
+		if args.RenderQueries {
+			body = v.renderQueryBlocks(body)
+		}
 		sb.WriteString(body)
 		sb.WriteString("\n\n---\n\n")
 	}
@@ -148,7 +151,7 @@ func (v *Vault) GetNoteSummaryHandler(ctx context.Context, req *mcp.CallToolRequ
 		Path:        notePath,
 		Frontmatter: ParseFrontmatter(contentStr),
 		WordCount:   len(strings.Fields(body)),
-		LinkCount:   len(ExtractWikilinks(body)),
+		LinkCount:   len(ExtractLinks(body)),
 		TagCount:    len(ExtractTags(contentStr)),
 		Headings:    extractHeadings(body),
 	}
@@ -166,8 +169,8 @@ func (v *Vault) GetNoteSummaryHandler(ctx context.Context, req *mcp.CallToolRequ
 
 	if len(summary.Frontmatter) > 0 {
 		sb.WriteString("## Frontmatter\n")
-		for k, val := range summary.Frontmatter {
-			fmt.Fprintf(&sb, "- **%s:** %v\n", k, val)
+		for _, k := range sortedFrontmatterKeys(summary.Frontmatter) {
+			fmt.Fprintf(&sb, "- **%s:** %s\n", k, frontmatterDisplay(summary.Frontmatter[k]))
 		}
 		sb.WriteString("\n")
 	}
@@ -320,36 +323,21 @@ func (v *Vault) SearchHeadingsHandler(ctx context.Context, req *mcp.CallToolRequ
 		heading Heading
 	}
 
-	var matches []headingMatch
-
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		relPath, _ := filepath.Rel(v.GetPath(), path)
-		body := RemoveFrontmatter(string(content))
-		headings := extractHeadings(body)
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("search failed: %v", err)
+	}
 
-		for _, h := range headings {
+	var matches []headingMatch
+	for _, note := range notes {
+		for _, h := range note.Headings {
 			if level > 0 && h.Level != level {
 				continue
 			}
 			if strings.Contains(strings.ToLower(h.Text), queryLower) {
-				matches = append(matches, headingMatch{path: relPath, heading: h})
+				matches = append(matches, headingMatch{path: note.RelPath, heading: h})
 			}
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("search failed: %v", err)
 	}
 
 	if len(matches) == 0 {
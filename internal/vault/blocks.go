@@ -0,0 +1,373 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// validBlockID matches the identifiers Obsidian accepts after ^.
+var validBlockID = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// readNoteFile reads a note by vault path, adding .md when it is missing.
+func (v *Vault) readNoteFile(notePath string) (string, string, error) {
+	if !strings.HasSuffix(notePath, ".md") {
+		notePath += ".md"
+	}
+	fullPath := filepath.Join(v.GetPath(), notePath)
+	if !v.isPathSafe(fullPath) {
+		return "", "", fmt.Errorf("path must be within vault")
+	}
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", fmt.Errorf("note not found: %s", notePath)
+		}
+		return "", "", fmt.Errorf("failed to read note: %v", err)
+	}
+	return notePath, string(content), nil
+}
+
+// GetBlockHandler returns the paragraph, list item or other block marked
+// with a ^block-id
+func (v *Vault) GetBlockHandler(ctx context.Context, req *mcp.CallToolRequest, args GetBlockArgs) (*mcp.CallToolResult, any, error) {
+	id := strings.TrimPrefix(strings.TrimSpace(args.BlockID), "^")
+	if id == "" {
+		return nil, nil, fmt.Errorf("block_id is required")
+	}
+	notePath, content, err := v.readNoteFile(args.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block := blockText(strings.Split(RemoveFrontmatter(content), "\n"), id)
+	if block == "" {
+		return nil, nil, fmt.Errorf("block not found: ^%s in %s", id, notePath)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("## %s#^%s\n\n%s", notePath, id, block)},
+		},
+	}, nil, nil
+}
+
+// AddBlockIDHandler marks the block containing a line of text with a ^block-id
+// and returns a link to it
+func (v *Vault) AddBlockIDHandler(ctx context.Context, req *mcp.CallToolRequest, args AddBlockIDArgs) (*mcp.CallToolResult, any, error) {
+	if strings.TrimSpace(args.Text) == "" {
+		return nil, nil, fmt.Errorf("text is required")
+	}
+	id := strings.TrimPrefix(strings.TrimSpace(args.BlockID), "^")
+	if id != "" && !validBlockID.MatchString(id) {
+		return nil, nil, fmt.Errorf("invalid block_id %q: use letters, numbers and dashes", id)
+	}
+
+	notePath, content, err := v.readNoteFile(args.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	fullPath := filepath.Join(v.GetPath(), notePath)
+	if err := ensureExpectedMtime(fullPath, args.ExpectedMtime); err != nil {
+		return nil, nil, err
+	}
+
+	lines := strings.Split(content, "\n")
+	target, err := findBlockLine(lines, args.Text)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%v in %s", err, notePath)
+	}
+
+	link, err := v.blockLinkBase(notePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Headings are linked by name, not by block ID.
+	if m := headingRegex.FindStringSubmatch(lines[target]); m != nil {
+		return blockIDResult(fmt.Sprintf("L%d is a heading; link to it by name", target+1), fmt.Sprintf("[[%s#%s]]", link, strings.TrimSpace(m[2])))
+	}
+
+	placement := blockIDPlacement(lines, target)
+	if existing := existingBlockID(lines, placement); existing != "" {
+		return blockIDResult(fmt.Sprintf("L%d already has block ID ^%s", target+1, existing), fmt.Sprintf("[[%s#^%s]]", link, existing))
+	}
+
+	used := make(map[string]bool)
+	for _, existing := range extractBlockIDs(content) {
+		used[strings.ToLower(existing)] = true
+	}
+	if id == "" {
+		id = newBlockID(used)
+	} else if used[strings.ToLower(id)] {
+		return nil, nil, fmt.Errorf("block ID ^%s is already used in %s", id, notePath)
+	}
+
+	newLines := insertBlockID(lines, placement, id)
+	if err := os.WriteFile(fullPath, []byte(strings.Join(newLines, "\n")), 0o600); err != nil {
+		return nil, nil, fmt.Errorf("failed to write note: %v", err)
+	}
+
+	return blockIDResult(fmt.Sprintf("Added ^%s to %s", id, notePath), fmt.Sprintf("[[%s#^%s]]", link, id))
+}
+
+func blockIDResult(summary, link string) (*mcp.CallToolResult, any, error) {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("%s\nLink: %s", summary, link)},
+		},
+	}, nil, nil
+}
+
+// blockLinkBase returns the shortest link path for a note: its name, or its
+// full path without .md when another file shares the name.
+func (v *Vault) blockLinkBase(notePath string) (string, error) {
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return "", fmt.Errorf("failed to scan vault: %v", err)
+	}
+	slashPath := strings.TrimSuffix(filepath.ToSlash(notePath), ".md")
+	name := filepath.Base(notePath)
+	if len(resolver.byName[strings.ToLower(name)]) > 1 {
+		return slashPath, nil
+	}
+	return strings.TrimSuffix(name, ".md"), nil
+}
+
+// frontmatterLineCount returns the number of lines taken by frontmatter.
+func frontmatterLineCount(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// findBlockLine finds the one line outside frontmatter containing text,
+// ignoring case.
+func findBlockLine(lines []string, text string) (int, error) {
+	text = strings.ToLower(text)
+	var matches []int
+	for i := frontmatterLineCount(lines); i < len(lines); i++ {
+		if strings.Contains(strings.ToLower(lines[i]), text) {
+			matches = append(matches, i)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return -1, fmt.Errorf("text not found")
+	case 1:
+		return matches[0], nil
+	default:
+		return -1, fmt.Errorf("text is ambiguous, found on %d lines", len(matches))
+	}
+}
+
+// blockPlacement says where a block ID goes: at the end of a line, or on a
+// line of its own after a structured block.
+type blockPlacement struct {
+	line     int
+	ownLine  bool
+	blockEnd int // last line of the structured block when ownLine is set
+}
+
+// blockIDPlacement decides where the ID for the block containing line goes.
+// List items and paragraphs take it at the end of their last line. Code
+// blocks, tables, quotes and callouts take it on a separate line after them,
+// as Obsidian requires.
+func blockIDPlacement(lines []string, line int) blockPlacement {
+	if start, end := enclosingCodeBlock(lines, line); start >= 0 {
+		return blockPlacement{line: end + 1, ownLine: true, blockEnd: end}
+	}
+
+	trimmed := strings.TrimSpace(lines[line])
+	for _, prefix := range []string{"|", ">"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			end := line
+			for end+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end+1]), prefix) {
+				end++
+			}
+			return blockPlacement{line: end + 1, ownLine: true, blockEnd: end}
+		}
+	}
+
+	if isListItem(lines[line]) {
+		return blockPlacement{line: line}
+	}
+	end := line
+	for end+1 < len(lines) {
+		next := lines[end+1]
+		if strings.TrimSpace(next) == "" || isListItem(next) || headingRegex.MatchString(next) {
+			break
+		}
+		if fence, _ := codeFence(next); fence != "" {
+			break
+		}
+		end++
+	}
+	return blockPlacement{line: end}
+}
+
+// enclosingCodeBlock returns the fence lines of the code block containing
+// line, or -1, -1.
+func enclosingCodeBlock(lines []string, line int) (int, int) {
+	for i := frontmatterLineCount(lines); i < len(lines); i++ {
+		fence, _ := codeFence(lines[i])
+		if fence == "" {
+			continue
+		}
+		end := closingFence(lines, i+1, fence)
+		if end < 0 {
+			end = len(lines) - 1
+		}
+		if line >= i && line <= end {
+			return i, end
+		}
+		if i > line {
+			break
+		}
+		i = end
+	}
+	return -1, -1
+}
+
+// existingBlockID returns the ID already marking the block, if any.
+func existingBlockID(lines []string, p blockPlacement) string {
+	idx := p.line
+	if p.ownLine {
+		// Skip the blank line Obsidian leaves between a block and its ID.
+		for idx < len(lines) && strings.TrimSpace(lines[idx]) == "" {
+			idx++
+		}
+		if idx >= len(lines) || !strings.HasPrefix(strings.TrimSpace(lines[idx]), "^") {
+			return ""
+		}
+	}
+	if idx >= len(lines) {
+		return ""
+	}
+	if m := blockIDRegex.FindStringSubmatch(lines[idx]); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// insertBlockID writes ^id at the placement.
+func insertBlockID(lines []string, p blockPlacement, id string) []string {
+	if !p.ownLine {
+		out := append([]string(nil), lines...)
+		out[p.line] = strings.TrimRight(out[p.line], " \t") + " ^" + id
+		return out
+	}
+	out := append([]string(nil), lines[:p.blockEnd+1]...)
+	out = append(out, "", "^"+id)
+	rest := lines[p.blockEnd+1:]
+	if len(rest) > 0 && strings.TrimSpace(rest[0]) != "" {
+		out = append(out, "")
+	}
+	return append(out, rest...)
+}
+
+// newBlockID generates a six-character ID, like Obsidian's, that is not in
+// used.
+func newBlockID(used map[string]bool) string {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+	for {
+		b := make([]byte, 6)
+		for i := range b {
+			b[i] = chars[rand.IntN(len(chars))]
+		}
+		if id := string(b); !used[id] {
+			return id
+		}
+	}
+}
+
+// BlockReferencesHandler finds links and embeds pointing at a block in a
+// note, or at any block in it when no block ID is given
+func (v *Vault) BlockReferencesHandler(ctx context.Context, req *mcp.CallToolRequest, args BlockReferencesArgs) (*mcp.CallToolResult, any, error) {
+	id := strings.TrimPrefix(strings.TrimSpace(args.BlockID), "^")
+
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search block references: %v", err)
+	}
+	targetPath, _ := resolver.resolvePath(args.Path, "")
+	if targetPath == "" {
+		return nil, nil, fmt.Errorf("note not found: %s", args.Path)
+	}
+
+	notes, err := v.indexedNotes(v.GetPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search block references: %v", err)
+	}
+
+	// block id (lowercased) -> "source L3: line" entries
+	refs := make(map[string][]string)
+	names := make(map[string]string)
+	total := 0
+	for _, note := range notes {
+		for i, line := range note.Lines {
+			for _, link := range parseNoteLinks(line) {
+				res := resolver.resolve(link.Target, note.RelPath)
+				if res.Target != targetPath || res.Ref.Block == "" {
+					continue
+				}
+				if id != "" && !strings.EqualFold(res.Ref.Block, id) {
+					continue
+				}
+				key := strings.ToLower(res.Ref.Block)
+				if _, ok := names[key]; !ok {
+					names[key] = res.Ref.Block
+				}
+				refs[key] = append(refs[key], fmt.Sprintf("%s L%d: %s", note.RelPath, i+1, truncateLine(strings.TrimSpace(line), 100)))
+				total++
+			}
+		}
+	}
+
+	if total == 0 {
+		what := "any block"
+		if id != "" {
+			what = "^" + id
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("No references found to %s in %s", what, targetPath)},
+			},
+		}, nil, nil
+	}
+
+	keys := make([]string, 0, len(refs))
+	for key := range refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Found %d references to blocks in %s:\n\n", total, targetPath)
+	for _, key := range keys {
+		fmt.Fprintf(&sb, "## ^%s (%d)\n", names[key], len(refs[key]))
+		for _, ref := range refs[key] {
+			fmt.Fprintf(&sb, "  %s\n", ref)
+		}
+		sb.WriteString("\n")
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: sb.String()},
+		},
+	}, nil, nil
+}
@@ -0,0 +1,151 @@
+package vault
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestGetBlock(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "note.md", strings.Join([]string{
+		"---",
+		"title: x",
+		"---",
+		"First paragraph",
+		"spans two lines. ^para",
+		"",
+		"- one",
+		"- two ^item",
+		"",
+		"| a | b |",
+		"| - | - |",
+		"",
+		"^table",
+		"",
+		"```go",
+		"x := 1",
+		"",
+		"y := 2",
+		"```",
+		"^code",
+	}, "\n"))
+	ctx := context.Background()
+
+	tests := map[string]string{
+		"para":   "First paragraph\nspans two lines.",
+		"^ITEM":  "- two",
+		"table":  "| a | b |\n| - | - |",
+		"code":   "```go\nx := 1\n\ny := 2\n```",
+		"^para ": "First paragraph\nspans two lines.",
+	}
+	for id, want := range tests {
+		result, _, err := v.GetBlockHandler(ctx, nil, GetBlockArgs{Path: "note", BlockID: id})
+		if err != nil {
+			t.Fatalf("get-block %q: %v", id, err)
+		}
+		if text := result.Content[0].(*mcp.TextContent).Text; !strings.HasSuffix(text, "\n\n"+want) {
+			t.Errorf("get-block %q = %q, want %q", id, text, want)
+		}
+	}
+
+	if _, _, err := v.GetBlockHandler(ctx, nil, GetBlockArgs{Path: "note", BlockID: "nope"}); err == nil {
+		t.Error("expected missing block to fail")
+	}
+}
+
+func TestAddBlockID(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "note.md", strings.Join([]string{
+		"# Title",
+		"A paragraph",
+		"that continues.",
+		"- task item",
+		"> quoted",
+		"> more",
+		"after",
+		"- marked ^keep",
+	}, "\n"))
+	ctx := context.Background()
+	add := func(text, id string) string {
+		t.Helper()
+		result, _, err := v.AddBlockIDHandler(ctx, nil, AddBlockIDArgs{Path: "note.md", Text: text, BlockID: id})
+		if err != nil {
+			t.Fatalf("add-block-id %q: %v", text, err)
+		}
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	if text := add("a paragraph", "para"); !strings.Contains(text, "Link: [[note#^para]]") {
+		t.Errorf("unexpected output:\n%s", text)
+	}
+	text := add("task item", "")
+	if !regexp.MustCompile(`Link: \[\[note#\^[a-z0-9]{6}\]\]`).MatchString(text) {
+		t.Errorf("expected generated id:\n%s", text)
+	}
+	id := text[strings.Index(text, "^")+1 : strings.Index(text, "^")+7]
+	add("more", "quote")
+	if text := add("marked", ""); !strings.Contains(text, "already has block ID ^keep") {
+		t.Errorf("expected existing id:\n%s", text)
+	}
+	if text := add("title", ""); !strings.Contains(text, "[[note#Title]]") {
+		t.Errorf("expected heading link:\n%s", text)
+	}
+
+	want := strings.Join([]string{
+		"# Title",
+		"A paragraph",
+		"that continues. ^para",
+		"- task item ^" + id,
+		"> quoted",
+		"> more",
+		"",
+		"^quote",
+		"",
+		"after",
+		"- marked ^keep",
+	}, "\n")
+	if got := readTestFile(t, dir, "note.md"); got != want {
+		t.Errorf("note =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, _, err := v.AddBlockIDHandler(ctx, nil, AddBlockIDArgs{Path: "note.md", Text: "after", BlockID: "para"}); err == nil {
+		t.Error("expected duplicate id to fail")
+	}
+	if _, _, err := v.AddBlockIDHandler(ctx, nil, AddBlockIDArgs{Path: "note.md", Text: "^"}); err == nil {
+		t.Error("expected ambiguous text to fail")
+	}
+}
+
+func TestBlockReferences(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "projects/Plan.md", "Ship it. ^ship\n\nHire. ^hire\n\nSee [[#^ship]].\n")
+	writeTestFile(t, dir, "a.md", "![[Plan#^ship]]\n[[Plan#^hire|hiring]] [[Plan]]\n")
+	writeTestFile(t, dir, "b.md", "[why](projects/Plan.md#^ship)\n")
+	ctx := context.Background()
+
+	result, _, err := v.BlockReferencesHandler(ctx, nil, BlockReferencesArgs{Path: "projects/Plan.md", BlockID: "^ship"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"Found 3 references", "## ^ship (3)", "a.md L1: ![[Plan#^ship]]", "b.md L1:", "Plan.md L5: See [[#^ship]]."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("block refs missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "hire") {
+		t.Errorf("block refs include other blocks:\n%s", text)
+	}
+
+	result, _, err = v.BlockReferencesHandler(ctx, nil, BlockReferencesArgs{Path: "Plan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "Found 4 references") || !strings.Contains(text, "## ^hire (1)") {
+		t.Errorf("unexpected refs for every block:\n%s", text)
+	}
+}
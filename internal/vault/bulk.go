@@ -129,15 +129,9 @@ func addTagToNote(content, tag string) (modified bool, result string) {
 	}
 
 	// Try to add to frontmatter first
-	if hasFrontmatter(content) {
-		fm := ParseFrontmatter(content)
-		if tagsVal, ok := fm["tags"]; ok {
-			// Append to existing tags
-			newTags := tagsVal + ", " + tag
-			return true, setFrontmatterKey(content, "tags", newTags)
-		}
-		// Add tags field
-		return true, addFrontmatterField(content, "tags", tag)
+	if hasFrontmatter(content) && validateFrontmatter(content) == nil {
+		newContent := addToFrontmatterArray(content, "tags", tag)
+		return newContent != content, newContent
 	}
 
 	// Add inline tag at the end
@@ -179,57 +173,28 @@ func removeTagFromNote(content, tag string) (changed bool, newContent string) {
 	newContent = strings.Join(resultLines, "\n")
 
 	if hasFrontmatter(newContent) {
-		fm := ParseFrontmatter(newContent)
-		if tagsVal, ok := fm["tags"]; ok {
-			tagList := strings.Split(tagsVal, ",")
-			var newTags []string
-			for _, t := range tagList {
-				t = strings.TrimSpace(t)
-				t = strings.Trim(t, "[]")
-				if !strings.EqualFold(t, tag) {
-					newTags = append(newTags, t)
-				} else {
-					changed = true
-				}
-			}
-			if len(newTags) > 0 {
-				newContent = setFrontmatterKey(newContent, "tags", strings.Join(newTags, ", "))
-			} else {
-				newContent, _ = removeFrontmatterKey(newContent, "tags")
+		newContent = editFrontmatter(newContent, func(doc *frontmatterDoc) bool {
+			if doc.removeFromList("tags", tag) {
+				changed = true
+				return true
 			}
-		}
+			return false
+		})
 	}
 
 	return changed, newContent
 }
 
-// addFrontmatterField adds a new field to existing frontmatter
+// addFrontmatterField adds a new field to existing frontmatter, leaving an
+// existing value for key untouched
 func addFrontmatterField(content, key, value string) string {
-	lines := strings.Split(content, "\n")
-	var result []string
-	inFrontmatter := false
-	fieldAdded := false
-
-	for i, line := range lines {
-		if i == 0 && line == "---" {
-			inFrontmatter = true
-			result = append(result, line)
-			continue
+	return editFrontmatter(content, func(doc *frontmatterDoc) bool {
+		if doc.index(key) >= 0 {
+			return false
 		}
-
-		if inFrontmatter && line == "---" {
-			// Add field before closing ---
-			if !fieldAdded {
-				result = append(result, fmt.Sprintf("%s: %s", key, value))
-				fieldAdded = true
-			}
-			inFrontmatter = false
-		}
-
-		result = append(result, line)
-	}
-
-	return strings.Join(result, "\n")
+		doc.set(key, frontmatterValueNode(value))
+		return true
+	})
 }
 
 // BulkMoveHandler moves multiple notes to a folder
@@ -258,6 +223,16 @@ func (v *Vault) BulkMoveHandler(ctx context.Context, req *mcp.CallToolRequest, a
 	var results []string
 	var errors []string
 
+	// Resolve links against the vault before anything moves
+	var rewriter *linkRewriter
+	if updateLinks {
+		var err error
+		if rewriter, err = v.newLinkRewriter(); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan links: %v", err)
+		}
+	}
+	moves := make(map[string]string)
+
 	for _, p := range paths {
 		if !strings.HasSuffix(p, ".md") {
 			p += ".md"
@@ -294,13 +269,8 @@ func (v *Vault) BulkMoveHandler(ctx context.Context, req *mcp.CallToolRequest, a
 				errors = append(errors, fmt.Sprintf("%s: move failed", p))
 				continue
 			}
-
-			// Update links if requested
-			if updateLinks {
-				oldName := strings.TrimSuffix(filename, ".md")
-				_ = v.updateLinksInVault(oldName, oldName)
-			}
 		}
+		moves[filepath.Clean(p)] = newRelPath
 		if dryRun {
 			results = append(results, fmt.Sprintf("%s -> %s (dry run)", p, newRelPath))
 		} else {
@@ -308,6 +278,12 @@ func (v *Vault) BulkMoveHandler(ctx context.Context, req *mcp.CallToolRequest, a
 		}
 	}
 
+	// Update links if requested
+	updatedFiles := 0
+	if rewriter != nil && len(moves) > 0 {
+		updatedFiles = rewriter.apply(moves, dryRun)
+	}
+
 	var sb strings.Builder
 	if dryRun {
 		sb.WriteString(fmt.Sprintf("# Dry Run: Bulk Move to %s\n\n", destination))
@@ -322,6 +298,14 @@ func (v *Vault) BulkMoveHandler(ctx context.Context, req *mcp.CallToolRequest, a
 		}
 	}
 
+	if rewriter != nil {
+		if dryRun {
+			sb.WriteString(fmt.Sprintf("\nWould update links in %d files\n", updatedFiles))
+		} else {
+			sb.WriteString(fmt.Sprintf("\nUpdated links in %d files\n", updatedFiles))
+		}
+	}
+
 	if len(errors) > 0 {
 		sb.WriteString("\n## Errors\n\n")
 		for _, e := range errors {
@@ -347,6 +331,10 @@ func (v *Vault) BulkSetFrontmatterHandler(ctx context.Context, req *mcp.CallTool
 	if len(paths) == 0 {
 		return nil, nil, fmt.Errorf("at least one path is required")
 	}
+	node, err := v.propertyValueNode(key, value)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var results []string
 	var errors []string
@@ -368,7 +356,11 @@ func (v *Vault) BulkSetFrontmatterHandler(ctx context.Context, req *mcp.CallTool
 		}
 
 		contentStr := string(content)
-		newContent := setFrontmatterKey(contentStr, key, value)
+		if err := validateFrontmatter(contentStr); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: invalid frontmatter: %v", p, err))
+			continue
+		}
+		newContent := setFrontmatterNode(contentStr, key, node)
 
 		if !dryRun {
 			if err := os.WriteFile(fullPath, []byte(newContent), 0o600); err != nil {
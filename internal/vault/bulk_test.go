@@ -118,42 +118,42 @@ title: Test
 	}
 }
 
-func TestUpdateWikilinks(t *testing.T) {
+func TestLinkRewriterRenamesWikilinks(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "Old Note.md", "# Old")
+	w, err := v.newLinkRewriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves := map[string]string{"Old Note.md": "New Note.md"}
+
 	tests := []struct {
 		name    string
 		content string
-		oldName string
-		newName string
 		want    string
 	}{
 		{
 			name:    "simple link",
 			content: "See [[Old Note]] for details",
-			oldName: "Old Note",
-			newName: "New Note",
 			want:    "See [[New Note]] for details",
 		},
 		{
 			name:    "aliased link",
 			content: "See [[Old Note|alias]] for details",
-			oldName: "Old Note",
-			newName: "New Note",
 			want:    "See [[New Note|alias]] for details",
 		},
 		{
 			name:    "multiple links",
 			content: "See [[Old Note]] and [[Old Note|other]]",
-			oldName: "Old Note",
-			newName: "New Note",
 			want:    "See [[New Note]] and [[New Note|other]]",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := updateWikilinks(tt.content, tt.oldName, tt.newName)
+			got := w.rewrite(tt.content, "source.md", "source.md", moves)
 			if got != tt.want {
-				t.Errorf("updateWikilinks() = %q, want %q", got, tt.want)
+				t.Errorf("rewrite() = %q, want %q", got, tt.want)
 			}
 		})
 	}
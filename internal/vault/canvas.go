@@ -66,7 +66,7 @@ func (v *Vault) ListCanvasesHandler(ctx context.Context, req *mcp.CallToolReques
 
 	var canvases []string
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+	err := v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
 			return nil
 		}
@@ -216,6 +216,7 @@ func (v *Vault) CreateCanvasHandler(ctx context.Context, req *mcp.CallToolReques
 	if !strings.HasSuffix(canvasPath, ".canvas") {
 		canvasPath += ".canvas"
 	}
+	canvasPath = v.obsidianSettings().newNotePath(canvasPath, "")
 
 	fullPath := filepath.Join(v.GetPath(), canvasPath)
 	if !v.isPathSafe(fullPath) {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -18,11 +19,9 @@ func (v *Vault) DailyNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 	format := args.Format
 	createIfMissing := args.CreateIfMissing
 
+	settings := v.obsidianSettings().Periodic["daily"]
 	if folder == "" {
-		folder = "daily"
-	}
-	if format == "" {
-		format = "2006-01-02"
+		folder = settings.Folder
 	}
 
 	targetDate, err := parseFlexibleDate(dateStr)
@@ -30,9 +29,12 @@ func (v *Vault) DailyNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 		return nil, nil, err
 	}
 
-	filename := targetDate.Format(format) + ".md"
+	filename := formatMoment(targetDate, settings.Format) + ".md"
+	if format != "" {
+		filename = targetDate.Format(format) + ".md"
+	}
 
-	return v.getOrCreatePeriodicNote(folder, filename, createIfMissing, func() string {
+	return v.getOrCreatePeriodicNote(folder, filename, createIfMissing, v.periodicContent(settings, targetDate, filename, func() string {
 		return fmt.Sprintf(`# %s
 
 ## Goals
@@ -44,7 +46,7 @@ func (v *Vault) DailyNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 ## Review
 
 `, targetDate.Format("Monday, January 2, 2006"))
-	})
+	}))
 }
 
 // ListDailyNotesHandler lists daily notes in a date range
@@ -53,8 +55,9 @@ func (v *Vault) ListDailyNotesHandler(ctx context.Context, req *mcp.CallToolRequ
 	folder := args.Folder
 	limit := args.Limit
 
+	settings := v.obsidianSettings().Periodic["daily"]
 	if folder == "" {
-		folder = "daily"
+		folder = settings.Folder
 	}
 	if limit <= 0 {
 		limit = 30
@@ -74,13 +77,21 @@ func (v *Vault) ListDailyNotesHandler(ctx context.Context, req *mcp.CallToolRequ
 	}
 
 	var notes []noteInfo
+	// Daily notes kept in the vault root are told apart from other notes by name
+	var names *regexp.Regexp
+	if folder == "" {
+		names = momentPattern(settings.Format)
+	}
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+	err := v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
 		if !info.IsDir() && strings.HasSuffix(path, ".md") {
 			relPath, _ := filepath.Rel(v.GetPath(), path)
+			if names != nil && !names.MatchString(filepath.ToSlash(strings.TrimSuffix(relPath, ".md"))) {
+				return nil
+			}
 			notes = append(notes, noteInfo{path: relPath, modTime: info.ModTime()})
 		}
 		return nil
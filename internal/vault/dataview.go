@@ -0,0 +1,229 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DataviewHandler executes a Dataview (DQL) query over the vault
+func (v *Vault) DataviewHandler(ctx context.Context, req *mcp.CallToolRequest, args DataviewArgs) (*mcp.CallToolResult, any, error) {
+	mode := normalizeMode(args.Mode)
+
+	result, err := v.runDataview(args.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !isDetailedMode(mode) {
+		return compactResult(fmt.Sprintf("Dataview %s returned %d rows", result.Type, len(result.Rows)), false, map[string]any{
+			"query":   args.Query,
+			"type":    result.Type,
+			"headers": result.Headers,
+			"rows":    result.Rows,
+		}, nil)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatDataviewResult(result)},
+		},
+	}, nil, nil
+}
+
+// runDataview parses and evaluates a DQL query against every note in the vault.
+func (v *Vault) runDataview(query string) (*DataviewResult, error) {
+	q, err := parseDQL(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dataview query: %v", err)
+	}
+	env, err := v.dataviewEnv()
+	if err != nil {
+		return nil, err
+	}
+	return q.execute(env), nil
+}
+
+func (v *Vault) dataviewEnv() (*dqlEnv, error) {
+	notes, err := v.indexedNotes(v.GetPath())
+	if err != nil {
+		return nil, fmt.Errorf("dataview query failed: %v", err)
+	}
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, fmt.Errorf("dataview query failed: %v", err)
+	}
+	return newDQLEnv(notes, resolver, v.taskStatuses()), nil
+}
+
+// generatedBlockEnd closes a section produced by renderQueryBlocks.
+const generatedBlockEnd = "<!-- obx:generated end -->"
+
+// renderQueryBlocks replaces each ```dataview and ```tasks code block in
+// content with the rendered markdown result, wrapped in obx:generated comment
+// markers. The begin marker records the original query on a single line.
+func (v *Vault) renderQueryBlocks(content string) string {
+	lines := strings.Split(content, "\n")
+	var out []string
+	var env *dqlEnv
+
+	for i := 0; i < len(lines); i++ {
+		fence, lang := codeFence(lines[i])
+		if fence == "" || (lang != "dataview" && lang != "tasks") {
+			out = append(out, lines[i])
+			continue
+		}
+		end := closingFence(lines, i+1, fence)
+		if end < 0 {
+			out = append(out, lines[i:]...)
+			break
+		}
+		query := strings.Join(lines[i+1:end], "\n")
+
+		var rendered string
+		if lang == "tasks" {
+			total, groups, err := v.runTasksQuery(query, v.GetPath())
+			if err != nil {
+				rendered = fmt.Sprintf("**Tasks query error:** %v\n", err)
+			} else {
+				rendered = formatTaskGroups(total, groups)
+			}
+		} else {
+			q, err := parseDQL(query)
+			if err == nil && env == nil {
+				env, err = v.dataviewEnv()
+			}
+			if err != nil {
+				rendered = fmt.Sprintf("**Dataview error:** %v\n", err)
+			} else {
+				rendered = formatDataviewResult(q.execute(env))
+			}
+		}
+
+		out = append(out, generatedBlockStart(lang, query))
+		out = append(out, strings.Split(strings.TrimRight(rendered, "\n"), "\n")...)
+		out = append(out, generatedBlockEnd)
+		i = end
+	}
+	return strings.Join(out, "\n")
+}
+
+func generatedBlockStart(lang, query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	query = strings.ReplaceAll(query, "-->", "- ->")
+	return fmt.Sprintf("<!-- obx:generated %s: %s -->", lang, query)
+}
+
+// codeFence returns the fence marker (``` or ~~~, possibly longer) and the
+// lower-cased info string when line opens a fenced code block.
+func codeFence(line string) (fence, lang string) {
+	trimmed := strings.TrimSpace(line)
+	for _, ch := range []string{"`", "~"} {
+		if !strings.HasPrefix(trimmed, ch+ch+ch) {
+			continue
+		}
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, ch))
+		return trimmed[:n], strings.ToLower(strings.TrimSpace(trimmed[n:]))
+	}
+	return "", ""
+}
+
+func closingFence(lines []string, from int, fence string) int {
+	for i := from; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// formatDataviewResult renders a query result as markdown, the way Dataview
+// would display it in reading view.
+func formatDataviewResult(result *DataviewResult) string {
+	if len(result.Rows) == 0 {
+		return "_No results._\n"
+	}
+
+	var sb strings.Builder
+	switch result.Type {
+	case string(dqlTable):
+		sb.WriteString("| " + strings.Join(escapeTableCells(result.Headers), " | ") + " |\n")
+		sb.WriteString("|" + strings.Repeat(" --- |", len(result.Headers)) + "\n")
+		for _, row := range result.Rows {
+			cells := make([]string, len(row.Values))
+			for i, value := range row.Values {
+				cells[i] = dataviewCell(value)
+			}
+			sb.WriteString("| " + strings.Join(escapeTableCells(cells), " | ") + " |\n")
+		}
+	case string(dqlList):
+		for _, row := range result.Rows {
+			item := dataviewRowLabel(row)
+			if len(row.Values) == 1 {
+				if list, ok := row.Values[0].([]any); ok && row.Group != nil {
+					sb.WriteString("- " + item + "\n")
+					for _, member := range list {
+						sb.WriteString("    - " + dataviewCell(member) + "\n")
+					}
+					continue
+				}
+				item += ": " + dataviewCell(row.Values[0])
+			}
+			sb.WriteString("- " + item + "\n")
+		}
+	case string(dqlTask):
+		for i, row := range result.Rows {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			fmt.Fprintf(&sb, "%s\n\n", dataviewRowLabel(row))
+			for _, task := range row.Tasks {
+				fmt.Fprintf(&sb, "- [%s] %s\n", task.Status, task.Text)
+			}
+		}
+	}
+	return sb.String()
+}
+
+func dataviewRowLabel(row DataviewRow) string {
+	if row.File != "" {
+		return "[[" + dqlNotePath(row.File) + "]]"
+	}
+	return dataviewCell(row.Group)
+}
+
+// dataviewCell renders an exported value; null renders as "-" like Dataview.
+func dataviewCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case string:
+		return v
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = dataviewCell(item)
+		}
+		return strings.Join(parts, ", ")
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+	return dqlString(value)
+}
+
+func escapeTableCells(cells []string) []string {
+	out := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, "\n", " ")
+		out[i] = strings.ReplaceAll(cell, "|", "\\|")
+	}
+	return out
+}
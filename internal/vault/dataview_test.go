@@ -0,0 +1,249 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func setupDataviewVault(t *testing.T) *Vault {
+	t.Helper()
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "projects/alpha.md", "---\nstatus: active\npriority: 2\ndue: 2026-11-01\ntags: [project, client]\n---\n# Alpha\n\nDepends on [[beta]].\n\n- [ ] draft plan 📅 2026-10-20\n- [x] kickoff\n")
+	writeTestFile(t, dir, "projects/beta.md", "---\nstatus: done\npriority: 1\ntags: [project]\n---\nowner:: [[Alice]]\nestimate:: 3\n- [ ] write docs\n")
+	writeTestFile(t, dir, "notes/gamma.md", "#idea/big links to [[alpha]] and [[beta]]\n")
+	return v
+}
+
+func runDataviewQuery(t *testing.T, v *Vault, query string) *DataviewResult {
+	t.Helper()
+	result, err := v.runDataview(query)
+	if err != nil {
+		t.Fatalf("query %q failed: %v", query, err)
+	}
+	return result
+}
+
+func dataviewFiles(result *DataviewResult) []string {
+	var files []string
+	for _, row := range result.Rows {
+		files = append(files, strings.ReplaceAll(row.File, "\\", "/"))
+	}
+	return files
+}
+
+func TestDataviewTableWhere(t *testing.T) {
+	v := setupDataviewVault(t)
+	result := runDataviewQuery(t, v, `TABLE status, priority AS "Prio" FROM #project WHERE priority >= 2`)
+
+	if want := []string{"File", "status", "Prio"}; !reflect.DeepEqual(result.Headers, want) {
+		t.Errorf("headers = %v, want %v", result.Headers, want)
+	}
+	if len(result.Rows) != 1 {
+		t.Fatalf("expected one row, got %+v", result.Rows)
+	}
+	if want := []any{"[[projects/alpha]]", "active", float64(2)}; !reflect.DeepEqual(result.Rows[0].Values, want) {
+		t.Errorf("values = %#v, want %#v", result.Rows[0].Values, want)
+	}
+}
+
+func TestDataviewSourcesAndSort(t *testing.T) {
+	v := setupDataviewVault(t)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`LIST FROM "projects" SORT file.name DESC`, []string{"projects/beta.md", "projects/alpha.md"}},
+		{`LIST FROM #idea`, []string{"notes/gamma.md"}},
+		{`LIST FROM [[beta]]`, []string{"notes/gamma.md", "projects/alpha.md"}},
+		{`LIST FROM outgoing([[gamma]])`, []string{"projects/alpha.md", "projects/beta.md"}},
+		{`LIST FROM #project AND -"projects/beta"`, []string{"projects/alpha.md"}},
+		{`LIST WHERE contains(file.tags, "#client")`, []string{"projects/alpha.md"}},
+		{`LIST WHERE owner = [[Alice]]`, []string{"projects/beta.md"}},
+		{`LIST WHERE length(file.inlinks) = 2`, []string{"projects/beta.md"}},
+		{`LIST WHERE due >= date(2026-10-01) AND due < date(2026-10-01) + dur(2 months)`, []string{"projects/alpha.md"}},
+		{`LIST WHERE estimate * 2 = 6`, []string{"projects/beta.md"}},
+		{`LIST SORT priority ASC LIMIT 1`, []string{"notes/gamma.md"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := dataviewFiles(runDataviewQuery(t, v, tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataviewGroupBy(t *testing.T) {
+	v := setupDataviewVault(t)
+	result := runDataviewQuery(t, v, `TABLE rows.file.name AS "Notes", length(rows) AS "Count" FROM "projects" GROUP BY status`)
+
+	if want := []string{"status", "Notes", "Count"}; !reflect.DeepEqual(result.Headers, want) {
+		t.Errorf("headers = %v, want %v", result.Headers, want)
+	}
+	if len(result.Rows) != 2 || result.Rows[0].Group != "active" || result.Rows[1].Group != "done" {
+		t.Fatalf("unexpected groups: %+v", result.Rows)
+	}
+	if want := []any{"active", []any{"alpha"}, float64(1)}; !reflect.DeepEqual(result.Rows[0].Values, want) {
+		t.Errorf("values = %#v, want %#v", result.Rows[0].Values, want)
+	}
+}
+
+func TestDataviewResolvesLinksLikeLinkTools(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "a/note.md", "---\npriority: 2\n---\n")
+	writeTestFile(t, dir, "b/note.md", "---\npriority: 2.0\n---\n")
+	writeTestFile(t, dir, "b/source.md", "See [[note]] and [[Nick]].\n")
+	writeTestFile(t, dir, "people/Nicholas.md", "---\naliases: [Nick]\n---\n")
+
+	// A bare name resolves to the note next to the linking note, and
+	// aliases resolve, as in find-links and check-links
+	for query, want := range map[string][]string{
+		`LIST WHERE contains(file.outlinks, [[b/note]])`: {"b/source.md"},
+		`LIST WHERE contains(file.outlinks, [[a/note]])`: nil,
+		`LIST FROM [[Nicholas]]`:                         {"b/source.md"},
+	} {
+		if got := dataviewFiles(runDataviewQuery(t, v, query)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: files = %v, want %v", query, got, want)
+		}
+	}
+
+	result := runDataviewQuery(t, v, `LIST FROM "a" OR "b" GROUP BY priority`)
+	if len(result.Rows) != 2 || !reflect.DeepEqual(result.Rows[1].Values, []any{[]any{"[[a/note]]", "[[b/note]]"}}) {
+		t.Errorf("equal keys should share a group: %+v", result.Rows)
+	}
+}
+
+func TestDataviewTask(t *testing.T) {
+	v := setupDataviewVault(t)
+	result := runDataviewQuery(t, v, `TASK FROM "projects" WHERE !completed`)
+
+	if result.Type != "task" || len(result.Rows) != 2 {
+		t.Fatalf("expected tasks from two files, got %+v", result)
+	}
+	if result.Rows[0].Tasks[0].Text != "draft plan 📅 2026-10-20" || result.Rows[1].Tasks[0].Text != "write docs" {
+		t.Errorf("unexpected tasks: %+v", result.Rows)
+	}
+
+	result = runDataviewQuery(t, v, `TASK WHERE due AND due < date(2026-10-21) GROUP BY file.folder`)
+	if len(result.Rows) != 1 || len(result.Rows[0].Tasks) != 1 || result.Rows[0].Group != "projects" {
+		t.Errorf("expected one grouped task with a due date, got %+v", result.Rows)
+	}
+}
+
+func TestDataviewTaskUsesCustomStatuses(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, ".obsidian/plugins/obsidian-tasks-plugin/data.json", `{"statusSettings": {"customStatuses": [
+		{"symbol": "d", "name": "Delegated", "nextStatusSymbol": " ", "type": "DONE"}
+	]}}`)
+	writeTestFile(t, dir, "todo.md", "- [d] hand over\n- [ ] follow up\n")
+
+	result := runDataviewQuery(t, v, `TASK WHERE completed`)
+	if len(result.Rows) != 1 || len(result.Rows[0].Tasks) != 1 {
+		t.Fatalf("expected the delegated task to be completed, got %+v", result.Rows)
+	}
+	if task := result.Rows[0].Tasks[0]; task.Text != "hand over" || task.StatusName != "Delegated" {
+		t.Errorf("unexpected task: %+v", task)
+	}
+
+	result = runDataviewQuery(t, v, `LIST WHERE contains(file.tasks.completed, true)`)
+	if len(result.Rows) != 1 {
+		t.Errorf("expected file.tasks to use custom statuses, got %+v", result.Rows)
+	}
+}
+
+func TestDataviewHandlerModes(t *testing.T) {
+	v := setupDataviewVault(t)
+	ctx := context.Background()
+
+	result, _, err := v.DataviewHandler(ctx, nil, DataviewArgs{Query: `TABLE WITHOUT ID file.name AS "Name", status FROM "projects"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Data struct {
+			Headers []string      `json:"headers"`
+			Rows    []DataviewRow `json:"rows"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if len(envelope.Data.Rows) != 2 || !reflect.DeepEqual(envelope.Data.Headers, []string{"Name", "status"}) {
+		t.Errorf("unexpected compact result: %+v", envelope.Data)
+	}
+
+	result, _, err = v.DataviewHandler(ctx, nil, DataviewArgs{Query: `TABLE status FROM "projects"`, Mode: "detailed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "| File | status |") || !strings.Contains(text, "| [[projects/beta]] | done |") {
+		t.Errorf("unexpected markdown table:\n%s", text)
+	}
+}
+
+func TestParseDQLErrors(t *testing.T) {
+	for _, q := range []string{
+		``,
+		`SELECT x`,
+		`TABLE status FROM`,
+		`LIST WHERE (a`,
+		`LIST LIMIT many`,
+		`LIST WHERE frobnicate(x)`,
+		`LIST WHERE "unterminated`,
+		`LIST WHERE a FROM "x"`,
+	} {
+		if _, err := parseDQL(q); err == nil {
+			t.Errorf("expected error for %q", q)
+		}
+	}
+}
+
+func TestReadNoteRendersDataviewBlocks(t *testing.T) {
+	v := setupDataviewVault(t)
+	dir := v.GetPath()
+	writeTestFile(t, dir, "dashboard.md", "# Dashboard\n\n```dataview\nTABLE status\nFROM \"projects\"\n```\n\n~~~dataview\nLIST WHERE\n~~~\n\n```go\nfmt.Println()\n```\n")
+	ctx := context.Background()
+
+	result, _, err := v.ReadNoteHandler(ctx, nil, ReadNoteArgs{Path: "dashboard.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; strings.Contains(text, "obx:generated") {
+		t.Errorf("queries rendered without render_queries:\n%s", text)
+	}
+
+	result, _, err = v.ReadNoteHandler(ctx, nil, ReadNoteArgs{Path: "dashboard.md", RenderQueries: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{
+		`<!-- obx:generated dataview: TABLE status FROM "projects" -->`,
+		"| [[projects/alpha]] | active |",
+		generatedBlockEnd,
+		"**Dataview error:**",
+		"```go\nfmt.Println()\n```",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("rendered note missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "```dataview") || strings.Contains(text, "~~~dataview") {
+		t.Errorf("query blocks should be substituted:\n%s", text)
+	}
+
+	result, _, err = v.ReadNotesHandler(ctx, nil, ReadNotesArgs{Paths: "dashboard", RenderQueries: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "| [[projects/beta]] | done |") {
+		t.Errorf("read-batch did not render queries:\n%s", text)
+	}
+}
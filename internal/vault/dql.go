@@ -0,0 +1,769 @@
+package vault
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file implements the parser for a subset of the Dataview Query Language:
+//
+//	TABLE [WITHOUT ID] expr [AS "Name"], ... | LIST [WITHOUT ID] [expr] | TASK
+//	FROM #tag and "folder" or [[Note]] or outgoing([[Note]]), optionally negated with -
+//	WHERE expr
+//	SORT expr [ASC|DESC], ...
+//	GROUP BY expr [AS name]
+//	LIMIT n
+//
+// Data commands after FROM may appear in any order and any number of times;
+// they are applied in sequence, as Dataview does.
+
+type dqlTokenKind int
+
+const (
+	dqlTokEOF dqlTokenKind = iota
+	dqlTokIdent
+	dqlTokNumber
+	dqlTokString
+	dqlTokDate
+	dqlTokLink
+	dqlTokTag
+	dqlTokSymbol
+)
+
+type dqlToken struct {
+	kind  dqlTokenKind
+	text  string
+	start int
+	end   int
+}
+
+var (
+	dqlDateLiteralRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(?:T\d{2}:\d{2}(?::\d{2})?)?`)
+	dqlSymbols          = []string{"!=", "<=", ">=", "=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", ",", ".", "[", "]", "&", "|"}
+)
+
+// lexDQL splits a DQL query into tokens. Positions are byte offsets into the
+// input so expressions can keep their source text for column headers.
+func lexDQL(input string) ([]dqlToken, error) {
+	var tokens []dqlToken
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			tok, err := lexDQLString(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = tok.end
+		case strings.HasPrefix(input[i:], "[["):
+			end := strings.Index(input[i+2:], "]]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated link at position %d", i)
+			}
+			tokens = append(tokens, dqlToken{kind: dqlTokLink, text: input[i+2 : i+2+end], start: i, end: i + end + 4})
+			i += end + 4
+		case c == '#':
+			j := i + 1
+			for j < len(input) && isDQLTagByte(input[j]) {
+				j++
+			}
+			if j == i+1 {
+				return nil, fmt.Errorf("empty tag at position %d", i)
+			}
+			tokens = append(tokens, dqlToken{kind: dqlTokTag, text: input[i+1 : j], start: i, end: j})
+			i = j
+		case c >= '0' && c <= '9':
+			tok := lexDQLNumber(input, i)
+			tokens = append(tokens, tok)
+			i = tok.end
+		default:
+			tok, ok := lexDQLIdent(input, i)
+			if !ok {
+				tok, ok = lexDQLSymbol(input, i)
+			}
+			if !ok {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, tok)
+			i = tok.end
+		}
+	}
+	return append(tokens, dqlToken{kind: dqlTokEOF, start: len(input), end: len(input)}), nil
+}
+
+func lexDQLString(input string, start int) (dqlToken, error) {
+	quote := input[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			if i+1 < len(input) {
+				i++
+				sb.WriteByte(input[i])
+			}
+		case quote:
+			return dqlToken{kind: dqlTokString, text: sb.String(), start: start, end: i + 1}, nil
+		default:
+			sb.WriteByte(input[i])
+		}
+	}
+	return dqlToken{}, fmt.Errorf("unterminated string at position %d", start)
+}
+
+func lexDQLNumber(input string, start int) dqlToken {
+	if m := dqlDateLiteralRegex.FindString(input[start:]); m != "" {
+		return dqlToken{kind: dqlTokDate, text: m, start: start, end: start + len(m)}
+	}
+	i := start
+	for i < len(input) && input[i] >= '0' && input[i] <= '9' {
+		i++
+	}
+	if i+1 < len(input) && input[i] == '.' && input[i+1] >= '0' && input[i+1] <= '9' {
+		i++
+		for i < len(input) && input[i] >= '0' && input[i] <= '9' {
+			i++
+		}
+	}
+	return dqlToken{kind: dqlTokNumber, text: input[start:i], start: start, end: i}
+}
+
+// lexDQLIdent reads a field or keyword name. Like Dataview, identifiers may
+// contain hyphens, so subtraction needs surrounding spaces.
+func lexDQLIdent(input string, start int) (dqlToken, bool) {
+	rs := []rune(input[start:])
+	if len(rs) == 0 || !(unicode.IsLetter(rs[0]) || rs[0] == '_') {
+		return dqlToken{}, false
+	}
+	n := 0
+	for n < len(rs) && (unicode.IsLetter(rs[n]) || unicode.IsDigit(rs[n]) || rs[n] == '_' || rs[n] == '-') {
+		n++
+	}
+	for n > 0 && rs[n-1] == '-' {
+		n--
+	}
+	end := start + len(string(rs[:n]))
+	return dqlToken{kind: dqlTokIdent, text: input[start:end], start: start, end: end}, true
+}
+
+func lexDQLSymbol(input string, start int) (dqlToken, bool) {
+	for _, sym := range dqlSymbols {
+		if strings.HasPrefix(input[start:], sym) {
+			return dqlToken{kind: dqlTokSymbol, text: sym, start: start, end: start + len(sym)}, true
+		}
+	}
+	return dqlToken{}, false
+}
+
+func isDQLTagByte(c byte) bool {
+	return c == '_' || c == '-' || c == '/' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// --- AST ---
+
+type dqlQueryType string
+
+const (
+	dqlTable dqlQueryType = "table"
+	dqlList  dqlQueryType = "list"
+	dqlTask  dqlQueryType = "task"
+)
+
+// dqlQuery is a parsed DQL query.
+type dqlQuery struct {
+	kind      dqlQueryType
+	withoutID bool
+	columns   []dqlColumn // TABLE columns, or the single optional LIST expression
+	from      dqlSource   // nil means every note
+	commands  []dqlCommand
+}
+
+type dqlColumn struct {
+	expr dqlExpr
+	name string
+}
+
+type dqlCommandKind int
+
+const (
+	dqlCmdWhere dqlCommandKind = iota
+	dqlCmdSort
+	dqlCmdGroupBy
+	dqlCmdLimit
+)
+
+type dqlCommand struct {
+	kind  dqlCommandKind
+	expr  dqlExpr   // WHERE condition or GROUP BY key
+	name  string    // GROUP BY alias
+	sorts []dqlSort // SORT keys
+	limit int
+}
+
+type dqlSort struct {
+	expr dqlExpr
+	desc bool
+}
+
+// dqlSource is a FROM clause predicate over notes.
+type dqlSource interface {
+	matches(note *indexedNote, env *dqlEnv) bool
+}
+
+type dqlSourceTag struct{ tag string }
+type dqlSourceFolder struct{ path string }
+type dqlSourceLink struct {
+	target   string
+	outgoing bool
+}
+type dqlSourceAnd struct{ left, right dqlSource }
+type dqlSourceOr struct{ left, right dqlSource }
+type dqlSourceNot struct{ inner dqlSource }
+
+// dqlExpr is an expression evaluated against a row context.
+type dqlExpr interface {
+	eval(ctx map[string]any) any
+}
+
+type dqlLiteral struct{ value any }
+type dqlField struct{ name string }
+type dqlMember struct {
+	target dqlExpr
+	name   string
+}
+type dqlIndex struct{ target, index dqlExpr }
+type dqlUnary struct {
+	op      string
+	operand dqlExpr
+}
+type dqlBinary struct {
+	op          string
+	left, right dqlExpr
+}
+type dqlCall struct {
+	name string
+	fn   dqlFunc
+	args []dqlExpr
+}
+
+// --- Parser ---
+
+type dqlParser struct {
+	src    string
+	tokens []dqlToken
+	pos    int
+}
+
+// parseDQL parses a DQL query string.
+func parseDQL(input string) (*dqlQuery, error) {
+	tokens, err := lexDQL(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &dqlParser{src: input, tokens: tokens}
+	q, err := p.parseHeader()
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("from") {
+		if q.from, err = p.parseSourceOr(); err != nil {
+			return nil, err
+		}
+	}
+	for p.peek().kind != dqlTokEOF {
+		cmd, err := p.parseCommand()
+		if err != nil {
+			return nil, err
+		}
+		q.commands = append(q.commands, cmd)
+	}
+	return q, nil
+}
+
+func (p *dqlParser) peek() dqlToken { return p.tokens[p.pos] }
+
+func (p *dqlParser) next() dqlToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != dqlTokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *dqlParser) isKeyword(kw string) bool {
+	tok := p.peek()
+	return tok.kind == dqlTokIdent && strings.EqualFold(tok.text, kw)
+}
+
+func (p *dqlParser) acceptKeyword(kw string) bool {
+	if p.isKeyword(kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *dqlParser) isSymbol(sym string) bool {
+	tok := p.peek()
+	return tok.kind == dqlTokSymbol && tok.text == sym
+}
+
+func (p *dqlParser) acceptSymbol(sym string) bool {
+	if p.isSymbol(sym) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *dqlParser) expectSymbol(sym string) error {
+	if !p.acceptSymbol(sym) {
+		return p.errorf("expected %q", sym)
+	}
+	return nil
+}
+
+func (p *dqlParser) errorf(format string, args ...any) error {
+	tok := p.peek()
+	found := tok.text
+	if tok.kind == dqlTokEOF {
+		found = "end of query"
+	}
+	return fmt.Errorf("%s near %q at position %d", fmt.Sprintf(format, args...), found, tok.start)
+}
+
+// atClauseEnd reports whether the next token starts a new clause.
+func (p *dqlParser) atClauseEnd() bool {
+	if p.peek().kind == dqlTokEOF {
+		return true
+	}
+	for _, kw := range []string{"from", "where", "sort", "group", "limit", "flatten"} {
+		if p.isKeyword(kw) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *dqlParser) parseHeader() (*dqlQuery, error) {
+	q := &dqlQuery{}
+	switch {
+	case p.acceptKeyword("table"):
+		q.kind = dqlTable
+	case p.acceptKeyword("list"):
+		q.kind = dqlList
+	case p.acceptKeyword("task"):
+		q.kind = dqlTask
+		return q, nil
+	default:
+		return nil, p.errorf("query must start with TABLE, LIST or TASK")
+	}
+
+	if p.acceptKeyword("without") {
+		if !p.acceptKeyword("id") {
+			return nil, p.errorf("expected ID after WITHOUT")
+		}
+		q.withoutID = true
+	}
+	for !p.atClauseEnd() {
+		col, err := p.parseColumn()
+		if err != nil {
+			return nil, err
+		}
+		q.columns = append(q.columns, col)
+		if q.kind == dqlList || !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return q, nil
+}
+
+func (p *dqlParser) parseColumn() (dqlColumn, error) {
+	start := p.peek().start
+	expr, err := p.parseExpr()
+	if err != nil {
+		return dqlColumn{}, err
+	}
+	name := strings.TrimSpace(p.src[start:p.tokens[p.pos-1].end])
+	if p.acceptKeyword("as") {
+		tok := p.next()
+		if tok.kind != dqlTokString && tok.kind != dqlTokIdent {
+			return dqlColumn{}, fmt.Errorf("expected column name after AS at position %d", tok.start)
+		}
+		name = tok.text
+	}
+	return dqlColumn{expr: expr, name: name}, nil
+}
+
+func (p *dqlParser) parseCommand() (dqlCommand, error) {
+	switch {
+	case p.acceptKeyword("where"):
+		expr, err := p.parseExpr()
+		return dqlCommand{kind: dqlCmdWhere, expr: expr}, err
+	case p.acceptKeyword("sort"):
+		return p.parseSort()
+	case p.acceptKeyword("group"):
+		if !p.acceptKeyword("by") {
+			return dqlCommand{}, p.errorf("expected BY after GROUP")
+		}
+		col, err := p.parseColumn()
+		return dqlCommand{kind: dqlCmdGroupBy, expr: col.expr, name: col.name}, err
+	case p.acceptKeyword("limit"):
+		tok := p.next()
+		n, err := strconv.Atoi(tok.text)
+		if tok.kind != dqlTokNumber || err != nil {
+			return dqlCommand{}, fmt.Errorf("LIMIT requires a whole number at position %d", tok.start)
+		}
+		return dqlCommand{kind: dqlCmdLimit, limit: n}, nil
+	case p.isKeyword("from"):
+		return dqlCommand{}, p.errorf("FROM must directly follow the query type")
+	default:
+		return dqlCommand{}, p.errorf("unsupported clause")
+	}
+}
+
+func (p *dqlParser) parseSort() (dqlCommand, error) {
+	cmd := dqlCommand{kind: dqlCmdSort}
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return cmd, err
+		}
+		s := dqlSort{expr: expr}
+		switch {
+		case p.acceptKeyword("desc"), p.acceptKeyword("descending"):
+			s.desc = true
+		case p.acceptKeyword("asc"), p.acceptKeyword("ascending"):
+		}
+		cmd.sorts = append(cmd.sorts, s)
+		if !p.acceptSymbol(",") {
+			return cmd, nil
+		}
+	}
+}
+
+// --- FROM sources ---
+
+func (p *dqlParser) parseSourceOr() (dqlSource, error) {
+	left, err := p.parseSourceAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("or") || p.acceptSymbol("|") {
+		right, err := p.parseSourceAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &dqlSourceOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *dqlParser) parseSourceAnd() (dqlSource, error) {
+	left, err := p.parseSourceUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("and") || p.acceptSymbol("&") {
+		right, err := p.parseSourceUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &dqlSourceAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *dqlParser) parseSourceUnary() (dqlSource, error) {
+	if p.acceptSymbol("-") || p.acceptSymbol("!") {
+		inner, err := p.parseSourceUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &dqlSourceNot{inner: inner}, nil
+	}
+	tok := p.peek()
+	switch {
+	case tok.kind == dqlTokTag:
+		p.pos++
+		return &dqlSourceTag{tag: strings.ToLower(tok.text)}, nil
+	case tok.kind == dqlTokString:
+		p.pos++
+		return &dqlSourceFolder{path: strings.Trim(tok.text, "/")}, nil
+	case tok.kind == dqlTokLink:
+		p.pos++
+		return &dqlSourceLink{target: dqlLinkTarget(tok.text)}, nil
+	case p.isKeyword("outgoing"):
+		p.pos++
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		link := p.next()
+		if link.kind != dqlTokLink {
+			return nil, fmt.Errorf("outgoing() expects a [[link]] at position %d", link.start)
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return &dqlSourceLink{target: dqlLinkTarget(link.text), outgoing: true}, nil
+	case p.acceptSymbol("("):
+		inner, err := p.parseSourceOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expectSymbol(")")
+	default:
+		return nil, p.errorf("expected #tag, \"folder\" or [[link]] in FROM")
+	}
+}
+
+// --- Expressions ---
+
+func (p *dqlParser) parseExpr() (dqlExpr, error) { return p.parseOr() }
+
+func (p *dqlParser) parseOr() (dqlExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("or") || p.acceptSymbol("|") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &dqlBinary{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *dqlParser) parseAnd() (dqlExpr, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("and") || p.acceptSymbol("&") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &dqlBinary{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *dqlParser) parseComparison() (dqlExpr, error) {
+	return p.parseBinaryLevel([]string{"=", "!=", "<=", ">=", "<", ">"}, p.parseAdditive)
+}
+
+func (p *dqlParser) parseAdditive() (dqlExpr, error) {
+	return p.parseBinaryLevel([]string{"+", "-"}, p.parseMultiplicative)
+}
+
+func (p *dqlParser) parseMultiplicative() (dqlExpr, error) {
+	return p.parseBinaryLevel([]string{"*", "/", "%"}, p.parseUnary)
+}
+
+func (p *dqlParser) parseBinaryLevel(ops []string, operand func() (dqlExpr, error)) (dqlExpr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, candidate := range ops {
+			if p.acceptSymbol(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &dqlBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *dqlParser) parseUnary() (dqlExpr, error) {
+	for _, op := range []string{"!", "-"} {
+		if p.acceptSymbol(op) {
+			operand, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return &dqlUnary{op: op, operand: operand}, nil
+		}
+	}
+	return p.parsePostfix()
+}
+
+func (p *dqlParser) parsePostfix() (dqlExpr, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.acceptSymbol("."):
+			tok := p.next()
+			if tok.kind != dqlTokIdent {
+				return nil, fmt.Errorf("expected field name after '.' at position %d", tok.start)
+			}
+			expr = &dqlMember{target: expr, name: strings.ToLower(tok.text)}
+		case p.acceptSymbol("["):
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol("]"); err != nil {
+				return nil, err
+			}
+			expr = &dqlIndex{target: expr, index: index}
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *dqlParser) parsePrimary() (dqlExpr, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case dqlTokNumber:
+		p.pos++
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		return &dqlLiteral{value: n}, nil
+	case dqlTokString:
+		p.pos++
+		return &dqlLiteral{value: tok.text}, nil
+	case dqlTokDate:
+		p.pos++
+		t, ok := parseDQLDate(tok.text)
+		if !ok {
+			return nil, fmt.Errorf("invalid date %q", tok.text)
+		}
+		return &dqlLiteral{value: t}, nil
+	case dqlTokLink:
+		p.pos++
+		return &dqlLiteral{value: dqlLink(dqlLinkTarget(tok.text))}, nil
+	case dqlTokIdent:
+		p.pos++
+		return p.parseIdent(tok)
+	}
+	if p.acceptSymbol("(") {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expectSymbol(")")
+	}
+	return nil, p.errorf("expected expression")
+}
+
+func (p *dqlParser) parseIdent(tok dqlToken) (dqlExpr, error) {
+	name := strings.ToLower(tok.text)
+	if !p.isSymbol("(") {
+		switch name {
+		case "true":
+			return &dqlLiteral{value: true}, nil
+		case "false":
+			return &dqlLiteral{value: false}, nil
+		case "null":
+			return &dqlLiteral{value: nil}, nil
+		}
+		return &dqlField{name: name}, nil
+	}
+	p.pos++
+
+	// date(today) and dur(3 days) take bare words rather than expressions.
+	if name == "date" || name == "dur" {
+		if literal, ok, err := p.parseRawLiteralCall(name); ok || err != nil {
+			return literal, err
+		}
+	}
+
+	fn, ok := dqlFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s()", name)
+	}
+	call := &dqlCall{name: name, fn: fn}
+	for !p.acceptSymbol(")") {
+		if len(call.args) > 0 {
+			if err := p.expectSymbol(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+	}
+	if len(call.args) < fn.minArgs || (fn.maxArgs >= 0 && len(call.args) > fn.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments to %s()", name)
+	}
+	return call, nil
+}
+
+// parseRawLiteralCall handles date(today), date(2024-01-31) and dur(1 week).
+// It reports ok=false when the call should be parsed as a regular function.
+func (p *dqlParser) parseRawLiteralCall(name string) (dqlExpr, bool, error) {
+	if name == "date" {
+		tok := p.peek()
+		if tok.kind != dqlTokIdent || !p.tokenFollowedBy(")") {
+			return nil, false, nil
+		}
+		if _, ok := dqlRelativeDates[strings.ToLower(tok.text)]; !ok {
+			return nil, false, nil
+		}
+		p.pos += 2
+		return &dqlRelativeDate{name: strings.ToLower(tok.text)}, true, nil
+	}
+
+	start := p.pos
+	var parts []string
+	for !p.isSymbol(")") {
+		tok := p.next()
+		if tok.kind == dqlTokEOF || (tok.kind != dqlTokNumber && tok.kind != dqlTokIdent) {
+			p.pos = start
+			return nil, false, nil
+		}
+		parts = append(parts, tok.text)
+	}
+	d, ok := parseDQLDuration(strings.Join(parts, " "))
+	if !ok {
+		p.pos = start
+		return nil, false, nil
+	}
+	p.pos++
+	return &dqlLiteral{value: d}, true, nil
+}
+
+func (p *dqlParser) tokenFollowedBy(sym string) bool {
+	if p.pos+1 >= len(p.tokens) {
+		return false
+	}
+	tok := p.tokens[p.pos+1]
+	return tok.kind == dqlTokSymbol && tok.text == sym
+}
+
+// dqlLinkTarget strips the display alias, heading anchor and .md extension
+// from the inside of a [[link]].
+func dqlLinkTarget(raw string) string {
+	if idx := strings.Index(raw, "|"); idx >= 0 {
+		raw = raw[:idx]
+	}
+	return strings.TrimSuffix(normalizeNoteName(raw), ".md")
+}
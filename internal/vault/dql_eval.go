@@ -0,0 +1,1234 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DQL values are nil, bool, float64, string, time.Time, dqlDuration, dqlLink,
+// []any and map[string]any. Operations on mismatched types yield nil rather
+// than failing the whole query, as in Dataview.
+
+// dqlLink is a link to a note, stored as its vault path without the .md extension.
+type dqlLink string
+
+// dqlDuration is a calendar-aware duration as produced by dur() or date arithmetic.
+type dqlDuration struct {
+	months int
+	days   int
+	d      time.Duration
+}
+
+func (d dqlDuration) approx() time.Duration {
+	return time.Duration(d.months)*30*24*time.Hour + time.Duration(d.days)*24*time.Hour + d.d
+}
+
+func (d dqlDuration) String() string {
+	var parts []string
+	add := func(n int, unit string) {
+		if n == 0 {
+			return
+		}
+		if n != 1 && n != -1 {
+			unit += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", n, unit))
+	}
+	add(d.months/12, "year")
+	add(d.months%12, "month")
+	rest := d.d
+	days := d.days + int(rest/(24*time.Hour))
+	rest %= 24 * time.Hour
+	add(days, "day")
+	add(int(rest/time.Hour), "hour")
+	add(int((rest%time.Hour)/time.Minute), "minute")
+	add(int((rest%time.Minute)/time.Second), "second")
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return strings.Join(parts, ", ")
+}
+
+var (
+	dqlDurationPartRegex = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*([a-z]+)`)
+	dqlNumberRegex       = regexp.MustCompile(`^-?\d+(?:\.\d+)?$`)
+	dqlNumberInTextRegex = regexp.MustCompile(`-?\d+(?:\.\d+)?`)
+	dqlDateValueRegex    = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2})?)?$`)
+	dqlDateLayouts       = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+)
+
+// parseDQLDuration parses strings like "3 days", "1 week" or "2h 30m".
+func parseDQLDuration(s string) (dqlDuration, bool) {
+	var d dqlDuration
+	matches := dqlDurationPartRegex.FindAllStringSubmatch(s, -1)
+	rest := strings.TrimSpace(dqlDurationPartRegex.ReplaceAllString(s, ""))
+	if len(matches) == 0 || strings.Trim(rest, ", ") != "" {
+		return d, false
+	}
+	for _, m := range matches {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		switch strings.ToLower(m[2]) {
+		case "y", "yr", "yrs", "year", "years":
+			d.months += int(n * 12)
+		case "mo", "month", "months":
+			d.months += int(n)
+		case "w", "wk", "wks", "week", "weeks":
+			d.days += int(n * 7)
+		case "d", "day", "days":
+			d.days += int(n)
+		case "h", "hr", "hrs", "hour", "hours":
+			d.d += time.Duration(n * float64(time.Hour))
+		case "m", "min", "mins", "minute", "minutes":
+			d.d += time.Duration(n * float64(time.Minute))
+		case "s", "sec", "secs", "second", "seconds":
+			d.d += time.Duration(n * float64(time.Second))
+		default:
+			return d, false
+		}
+	}
+	return d, true
+}
+
+// parseDQLDate parses an ISO date or date-time in the local time zone.
+func parseDQLDate(s string) (time.Time, bool) {
+	for _, layout := range dqlDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// dqlRelativeDates are the named dates accepted by date(today) and friends.
+var dqlRelativeDates = map[string]func(now time.Time) time.Time{
+	"now":       func(now time.Time) time.Time { return now },
+	"today":     func(now time.Time) time.Time { return dqlStartOfDay(now) },
+	"tomorrow":  func(now time.Time) time.Time { return dqlStartOfDay(now).AddDate(0, 0, 1) },
+	"yesterday": func(now time.Time) time.Time { return dqlStartOfDay(now).AddDate(0, 0, -1) },
+	"sow": func(now time.Time) time.Time {
+		return dqlStartOfDay(now).AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+	},
+	"eow": func(now time.Time) time.Time {
+		return dqlStartOfDay(now).AddDate(0, 0, 6-(int(now.Weekday())+6)%7)
+	},
+	"som": func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	},
+	"eom": func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location())
+	},
+	"soy": func(now time.Time) time.Time { return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location()) },
+	"eoy": func(now time.Time) time.Time { return time.Date(now.Year(), 12, 31, 0, 0, 0, 0, now.Location()) },
+}
+
+func dqlStartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// dqlRelativeDate evaluates date(today)-style expressions at query time.
+type dqlRelativeDate struct{ name string }
+
+func (e *dqlRelativeDate) eval(map[string]any) any { return dqlRelativeDates[e.name](time.Now()) }
+
+// dqlInferValue converts a raw frontmatter or inline field string into a
+// typed value: numbers, booleans, dates, [[links]] and [a, b] lists.
+func dqlInferValue(raw string) any {
+	s := strings.TrimSpace(raw)
+	switch {
+	case s == "":
+		return nil
+	case strings.EqualFold(s, "true"):
+		return true
+	case strings.EqualFold(s, "false"):
+		return false
+	case dqlNumberRegex.MatchString(s):
+		n, _ := strconv.ParseFloat(s, 64)
+		return n
+	case dqlDateValueRegex.MatchString(s):
+		if t, ok := parseDQLDate(s); ok {
+			return t
+		}
+	case strings.HasPrefix(s, "[[") && strings.HasSuffix(s, "]]") && strings.Count(s, "[[") == 1:
+		return dqlLink(dqlLinkTarget(s[2 : len(s)-2]))
+	case strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]"):
+		var list []any
+		for _, item := range strings.Split(s[1:len(s)-1], ",") {
+			if item = strings.Trim(strings.TrimSpace(item), `"'`); item != "" {
+				list = append(list, dqlInferValue(item))
+			}
+		}
+		return list
+	}
+	return s
+}
+
+// dqlFromFrontmatter converts a typed frontmatter value into a DQL value.
+// Strings still go through dqlInferValue so dates and links are recognised.
+func dqlFromFrontmatter(value any) any {
+	switch v := value.(type) {
+	case string:
+		return dqlInferValue(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = dqlFromFrontmatter(item)
+		}
+		return list
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for key, item := range v {
+			obj[key] = dqlFromFrontmatter(item)
+		}
+		return obj
+	}
+	return value
+}
+
+// --- Evaluation ---
+
+func (e *dqlLiteral) eval(map[string]any) any { return e.value }
+
+func (e *dqlField) eval(ctx map[string]any) any { return ctx[e.name] }
+
+func (e *dqlMember) eval(ctx map[string]any) any { return dqlGet(e.target.eval(ctx), e.name) }
+
+// dqlGet reads a field from an object. On a list it maps over the elements,
+// so rows.file.name yields every grouped row's name.
+func dqlGet(value any, name string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return v[name]
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			out = append(out, dqlGet(item, name))
+		}
+		return out
+	case time.Time:
+		switch name {
+		case "year":
+			return float64(v.Year())
+		case "month":
+			return float64(v.Month())
+		case "day":
+			return float64(v.Day())
+		case "hour":
+			return float64(v.Hour())
+		case "minute":
+			return float64(v.Minute())
+		case "weekday":
+			return float64((int(v.Weekday())+6)%7 + 1)
+		}
+	}
+	return nil
+}
+
+func (e *dqlIndex) eval(ctx map[string]any) any {
+	target := e.target.eval(ctx)
+	switch idx := e.index.eval(ctx).(type) {
+	case float64:
+		list, ok := target.([]any)
+		i := int(idx)
+		if i < 0 && ok {
+			i += len(list)
+		}
+		if !ok || i < 0 || i >= len(list) {
+			return nil
+		}
+		return list[i]
+	case string:
+		return dqlGet(target, strings.ToLower(idx))
+	}
+	return nil
+}
+
+func (e *dqlUnary) eval(ctx map[string]any) any {
+	value := e.operand.eval(ctx)
+	if e.op == "!" {
+		return !dqlTruthy(value)
+	}
+	if n, ok := value.(float64); ok {
+		return -n
+	}
+	return nil
+}
+
+func (e *dqlBinary) eval(ctx map[string]any) any {
+	switch e.op {
+	case "and":
+		return dqlTruthy(e.left.eval(ctx)) && dqlTruthy(e.right.eval(ctx))
+	case "or":
+		return dqlTruthy(e.left.eval(ctx)) || dqlTruthy(e.right.eval(ctx))
+	}
+	left, right := e.left.eval(ctx), e.right.eval(ctx)
+	switch e.op {
+	case "=":
+		return dqlEqual(left, right)
+	case "!=":
+		return !dqlEqual(left, right)
+	case "<":
+		return dqlCompare(left, right) < 0
+	case "<=":
+		return dqlCompare(left, right) <= 0
+	case ">":
+		return dqlCompare(left, right) > 0
+	case ">=":
+		return dqlCompare(left, right) >= 0
+	}
+	return dqlArithmetic(e.op, left, right)
+}
+
+func (e *dqlCall) eval(ctx map[string]any) any {
+	args := make([]any, len(e.args))
+	for i, arg := range e.args {
+		args[i] = arg.eval(ctx)
+	}
+	return e.fn.call(args)
+}
+
+func dqlTruthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	case time.Time:
+		return !v.IsZero()
+	case dqlDuration:
+		return v.approx() != 0
+	}
+	return true
+}
+
+func dqlArithmetic(op string, left, right any) any {
+	if op == "+" {
+		if ls, ok := left.(string); ok {
+			return ls + dqlString(right)
+		}
+		if rs, ok := right.(string); ok {
+			return dqlString(left) + rs
+		}
+		if ll, ok := left.([]any); ok {
+			if rl, ok := right.([]any); ok {
+				return append(append([]any{}, ll...), rl...)
+			}
+		}
+	}
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return dqlNumberOp(op, l, r)
+		}
+	case time.Time:
+		switch r := right.(type) {
+		case dqlDuration:
+			if op == "-" {
+				r = dqlDuration{months: -r.months, days: -r.days, d: -r.d}
+			} else if op != "+" {
+				return nil
+			}
+			return l.AddDate(0, r.months, r.days).Add(r.d)
+		case time.Time:
+			if op == "-" {
+				return dqlDuration{d: l.Sub(r)}
+			}
+		}
+	case dqlDuration:
+		if r, ok := right.(dqlDuration); ok && (op == "+" || op == "-") {
+			sign := 1
+			if op == "-" {
+				sign = -1
+			}
+			return dqlDuration{months: l.months + sign*r.months, days: l.days + sign*r.days, d: l.d + time.Duration(sign)*r.d}
+		}
+		if r, ok := right.(time.Time); ok && op == "+" {
+			return r.AddDate(0, l.months, l.days).Add(l.d)
+		}
+	}
+	return nil
+}
+
+func dqlNumberOp(op string, l, r float64) any {
+	switch op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		if r == 0 {
+			return nil
+		}
+		return l / r
+	case "%":
+		if r == 0 {
+			return nil
+		}
+		return math.Mod(l, r)
+	}
+	return nil
+}
+
+// dqlTypeRank orders values of different types; null sorts before everything.
+func dqlTypeRank(value any) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	case dqlDuration:
+		return 3
+	case time.Time:
+		return 4
+	case string:
+		return 5
+	case dqlLink:
+		return 6
+	case []any:
+		return 7
+	}
+	return 8
+}
+
+// dqlCompare returns -1, 0 or 1, ordering values first by type and then by value.
+func dqlCompare(a, b any) int {
+	ra, rb := dqlTypeRank(a), dqlTypeRank(b)
+	if ra != rb {
+		return compareInts(ra, rb)
+	}
+	switch av := a.(type) {
+	case bool:
+		return compareInts(boolToInt(av), boolToInt(b.(bool)))
+	case float64:
+		bv := b.(float64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+	case dqlDuration:
+		return compareInts64(int64(av.approx()), int64(b.(dqlDuration).approx()))
+	case time.Time:
+		return av.Compare(b.(time.Time))
+	case string:
+		return strings.Compare(av, b.(string))
+	case dqlLink:
+		return strings.Compare(strings.ToLower(string(av)), strings.ToLower(string(b.(dqlLink))))
+	case []any:
+		bv := b.([]any)
+		for i := 0; i < len(av) && i < len(bv); i++ {
+			if c := dqlCompare(av[i], bv[i]); c != 0 {
+				return c
+			}
+		}
+		return compareInts(len(av), len(bv))
+	}
+	return 0
+}
+
+// dqlEqual compares values; links match when either side is a bare note name
+// that equals the other's basename, mirroring wikilink resolution.
+func dqlEqual(a, b any) bool {
+	if al, ok := a.(dqlLink); ok {
+		if bl, ok := b.(dqlLink); ok {
+			return dqlLinksEqual(al, bl)
+		}
+	}
+	return dqlTypeRank(a) == dqlTypeRank(b) && dqlCompare(a, b) == 0
+}
+
+func dqlLinksEqual(a, b dqlLink) bool {
+	as, bs := strings.ToLower(string(a)), strings.ToLower(string(b))
+	if as == bs {
+		return true
+	}
+	if !strings.Contains(as, "/") || !strings.Contains(bs, "/") {
+		return filepath.Base(as) == filepath.Base(bs)
+	}
+	return false
+}
+
+func compareInts(a, b int) int {
+	return compareInts64(int64(a), int64(b))
+}
+
+func compareInts64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// dqlString renders a value as display text.
+func dqlString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if v.Equal(dqlStartOfDay(v)) {
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02T15:04")
+	case dqlDuration:
+		return v.String()
+	case dqlLink:
+		return "[[" + string(v) + "]]"
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = dqlString(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	data, err := json.Marshal(dqlExport(value))
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// dqlExport converts a value into plain JSON types for tool responses.
+func dqlExport(value any) any {
+	switch v := value.(type) {
+	case time.Time, dqlDuration, dqlLink:
+		return dqlString(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = dqlExport(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = dqlExport(item)
+		}
+		return out
+	}
+	return value
+}
+
+// --- Functions ---
+
+type dqlFunc struct {
+	minArgs int
+	maxArgs int // -1 for variadic
+	call    func(args []any) any
+}
+
+var dqlFunctions = map[string]dqlFunc{
+	"contains":   {2, 2, func(a []any) any { return dqlContains(a[0], a[1], false) }},
+	"icontains":  {2, 2, func(a []any) any { return dqlContains(a[0], a[1], true) }},
+	"length":     {1, 1, dqlLength},
+	"lower":      {1, 1, func(a []any) any { return strings.ToLower(dqlString(a[0])) }},
+	"upper":      {1, 1, func(a []any) any { return strings.ToUpper(dqlString(a[0])) }},
+	"startswith": {2, 2, func(a []any) any { return strings.HasPrefix(dqlString(a[0]), dqlString(a[1])) }},
+	"endswith":   {2, 2, func(a []any) any { return strings.HasSuffix(dqlString(a[0]), dqlString(a[1])) }},
+	"replace":    {3, 3, func(a []any) any { return strings.ReplaceAll(dqlString(a[0]), dqlString(a[1]), dqlString(a[2])) }},
+	"regexmatch": {2, 2, dqlRegexMatch},
+	"default":    {2, 2, func(a []any) any { return dqlDefault(a[0], a[1]) }},
+	"choice": {3, 3, func(a []any) any {
+		if dqlTruthy(a[0]) {
+			return a[1]
+		}
+		return a[2]
+	}},
+	"date":   {1, 1, dqlToDate},
+	"dur":    {1, 1, dqlToDuration},
+	"number": {1, 1, dqlToNumber},
+	"string": {1, 1, func(a []any) any { return dqlString(a[0]) }},
+	"round":  {1, 2, dqlRound},
+	"list":   {0, -1, func(a []any) any { return append([]any{}, a...) }},
+	"join":   {1, 2, dqlJoin},
+	"sum":    {1, -1, func(a []any) any { return dqlAggregate(a, "sum") }},
+	"min":    {1, -1, func(a []any) any { return dqlAggregate(a, "min") }},
+	"max":    {1, -1, func(a []any) any { return dqlAggregate(a, "max") }},
+	"link":   {1, 1, func(a []any) any { return dqlLink(dqlLinkTarget(dqlString(a[0]))) }},
+}
+
+func dqlContains(haystack, needle any, fold bool) any {
+	switch h := haystack.(type) {
+	case []any:
+		for _, item := range h {
+			if fold {
+				if strings.EqualFold(dqlString(item), dqlString(needle)) {
+					return true
+				}
+			} else if dqlEqual(item, needle) {
+				return true
+			}
+		}
+		return false
+	case string:
+		if fold {
+			return strings.Contains(strings.ToLower(h), strings.ToLower(dqlString(needle)))
+		}
+		return strings.Contains(h, dqlString(needle))
+	case map[string]any:
+		_, ok := h[strings.ToLower(dqlString(needle))]
+		return ok
+	case dqlLink:
+		return dqlContains(string(h), needle, fold)
+	}
+	return false
+}
+
+func dqlLength(a []any) any {
+	switch v := a[0].(type) {
+	case []any:
+		return float64(len(v))
+	case string:
+		return float64(len([]rune(v)))
+	case map[string]any:
+		return float64(len(v))
+	case nil:
+		return float64(0)
+	}
+	return float64(1)
+}
+
+func dqlRegexMatch(a []any) any {
+	re, err := regexp.Compile("^(?:" + dqlString(a[0]) + ")$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(dqlString(a[1]))
+}
+
+func dqlDefault(value, fallback any) any {
+	if list, ok := value.([]any); ok {
+		out := make([]any, len(list))
+		for i, item := range list {
+			out[i] = dqlDefault(item, fallback)
+		}
+		return out
+	}
+	if value == nil {
+		return fallback
+	}
+	return value
+}
+
+func dqlToDate(a []any) any {
+	switch v := a[0].(type) {
+	case time.Time:
+		return v
+	case string:
+		if t, ok := parseDQLDate(strings.TrimSpace(v)); ok {
+			return t
+		}
+		if fn, ok := dqlRelativeDates[strings.ToLower(strings.TrimSpace(v))]; ok {
+			return fn(time.Now())
+		}
+	case dqlLink:
+		if t, ok := parseDQLDate(filepath.Base(string(v))); ok {
+			return t
+		}
+	}
+	return nil
+}
+
+func dqlToDuration(a []any) any {
+	switch v := a[0].(type) {
+	case dqlDuration:
+		return v
+	case string:
+		if d, ok := parseDQLDuration(v); ok {
+			return d
+		}
+	}
+	return nil
+}
+
+func dqlToNumber(a []any) any {
+	switch v := a[0].(type) {
+	case float64:
+		return v
+	case string:
+		if m := dqlNumberInTextRegex.FindString(v); m != "" {
+			n, _ := strconv.ParseFloat(m, 64)
+			return n
+		}
+	}
+	return nil
+}
+
+func dqlRound(a []any) any {
+	n, ok := a[0].(float64)
+	if !ok {
+		return nil
+	}
+	digits := 0.0
+	if len(a) > 1 {
+		if d, ok := a[1].(float64); ok {
+			digits = d
+		}
+	}
+	scale := math.Pow(10, digits)
+	return math.Round(n*scale) / scale
+}
+
+func dqlJoin(a []any) any {
+	sep := ", "
+	if len(a) > 1 {
+		sep = dqlString(a[1])
+	}
+	list, ok := a[0].([]any)
+	if !ok {
+		return dqlString(a[0])
+	}
+	parts := make([]string, len(list))
+	for i, item := range list {
+		parts[i] = dqlString(item)
+	}
+	return strings.Join(parts, sep)
+}
+
+// dqlAggregate implements sum/min/max over either one list or several arguments.
+func dqlAggregate(args []any, op string) any {
+	values := args
+	if len(args) == 1 {
+		if list, ok := args[0].([]any); ok {
+			values = list
+		}
+	}
+	var result any
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		switch {
+		case result == nil:
+			result = value
+		case op == "sum":
+			result = dqlArithmetic("+", result, value)
+		case op == "min" && dqlCompare(value, result) < 0,
+			op == "max" && dqlCompare(value, result) > 0:
+			result = value
+		}
+	}
+	if result == nil && op == "sum" {
+		return float64(0)
+	}
+	return result
+}
+
+// --- Pages, sources and execution ---
+
+// dqlEnv holds vault-wide state needed to evaluate a query: page contexts and
+// the resolved link graph used for file.outlinks, file.inlinks and [[link]] sources.
+type dqlEnv struct {
+	notes    []*indexedNote
+	resolver *linkResolver
+	outlinks map[string][]string
+	inlinks  map[string][]string
+	pages    map[string]map[string]any
+	statuses taskStatuses
+}
+
+func newDQLEnv(notes []*indexedNote, resolver *linkResolver, statuses taskStatuses) *dqlEnv {
+	env := &dqlEnv{
+		notes:    notes,
+		resolver: resolver,
+		statuses: statuses,
+		outlinks: make(map[string][]string),
+		inlinks:  make(map[string][]string),
+		pages:    make(map[string]map[string]any),
+	}
+	for _, note := range notes {
+		seen := make(map[string]bool)
+		for _, link := range note.Links {
+			target, ok := env.resolve(dqlLinkTarget(link), note.RelPath)
+			if !ok {
+				target = dqlLinkTarget(link)
+			}
+			if seen[target] {
+				continue
+			}
+			seen[target] = true
+			env.outlinks[note.RelPath] = append(env.outlinks[note.RelPath], target)
+			if ok && target != note.RelPath {
+				env.inlinks[target] = append(env.inlinks[target], note.RelPath)
+			}
+		}
+	}
+	return env
+}
+
+// tasks returns a note's tasks with the vault's checkbox statuses applied.
+// Index entries are shared, so statuses are applied to copies.
+func (env *dqlEnv) tasks(note *indexedNote) []*Task {
+	tasks := make([]*Task, len(note.Tasks))
+	for i := range note.Tasks {
+		task := new(Task)
+		*task = note.Tasks[i]
+		env.statuses.apply(task)
+		tasks[i] = task
+	}
+	return tasks
+}
+
+// resolve maps a link target in sourcePath to the relPath of an existing
+// file, the same way the link tools do.
+func (env *dqlEnv) resolve(target, sourcePath string) (string, bool) {
+	rel, _ := env.resolver.resolvePath(target, sourcePath)
+	return rel, rel != ""
+}
+
+// dqlNotePath converts a relPath into the slash-separated, extension-less form used by links.
+func dqlNotePath(relPath string) string {
+	return strings.TrimSuffix(filepath.ToSlash(relPath), ".md")
+}
+
+// dqlFieldKey normalizes field names the way Dataview exposes them.
+func dqlFieldKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), " ", "-")
+}
+
+// page returns the evaluation context for a note: its frontmatter and inline
+// fields plus the implicit file object.
+func (env *dqlEnv) page(note *indexedNote) map[string]any {
+	if ctx, ok := env.pages[note.RelPath]; ok {
+		return ctx
+	}
+	ctx := make(map[string]any)
+	for key, value := range note.Frontmatter {
+		ctx[dqlFieldKey(key)] = dqlFromFrontmatter(value)
+	}
+	for _, field := range note.InlineFields {
+		key := dqlFieldKey(field.Key)
+		value := dqlInferValue(field.Value)
+		if existing, ok := ctx[key]; ok {
+			if list, ok := existing.([]any); ok {
+				ctx[key] = append(list, value)
+			} else {
+				ctx[key] = []any{existing, value}
+			}
+			continue
+		}
+		ctx[key] = value
+	}
+	ctx["file"] = env.fileObject(note)
+	env.pages[note.RelPath] = ctx
+	return ctx
+}
+
+func (env *dqlEnv) fileObject(note *indexedNote) map[string]any {
+	folder := filepath.ToSlash(filepath.Dir(note.RelPath))
+	if folder == "." {
+		folder = ""
+	}
+
+	var tags, etags []any
+	seenTags := make(map[string]bool)
+	for _, tag := range note.Tags {
+		etags = append(etags, "#"+tag)
+		parts := strings.Split(tag, "/")
+		for i := range parts {
+			parent := "#" + strings.Join(parts[:i+1], "/")
+			if !seenTags[parent] {
+				seenTags[parent] = true
+				tags = append(tags, parent)
+			}
+		}
+	}
+
+	outlinks := make([]any, 0, len(env.outlinks[note.RelPath]))
+	for _, target := range env.outlinks[note.RelPath] {
+		outlinks = append(outlinks, dqlLink(dqlNotePath(target)))
+	}
+	inlinks := make([]any, 0, len(env.inlinks[note.RelPath]))
+	for _, source := range env.inlinks[note.RelPath] {
+		inlinks = append(inlinks, dqlLink(dqlNotePath(source)))
+	}
+	tasks := make([]any, 0, len(note.Tasks))
+	for _, task := range env.tasks(note) {
+		tasks = append(tasks, dqlTaskFields(task))
+	}
+
+	return map[string]any{
+		"name":     strings.TrimSuffix(filepath.Base(note.RelPath), ".md"),
+		"path":     filepath.ToSlash(note.RelPath),
+		"folder":   folder,
+		"ext":      "md",
+		"link":     dqlLink(dqlNotePath(note.RelPath)),
+		"size":     float64(note.Size),
+		"mtime":    note.ModTime,
+		"ctime":    dqlCreated(note),
+		"tags":     tags,
+		"etags":    etags,
+		"outlinks": outlinks,
+		"inlinks":  inlinks,
+		"tasks":    tasks,
+	}
+}
+
+// dqlCreated returns the note's creation time. Filesystems do not expose it
+// portably, so the "created" frontmatter property is used when present and
+// the modification time otherwise.
+func dqlCreated(note *indexedNote) time.Time {
+	if t, ok := parseDQLDate(frontmatterText(note.Frontmatter["created"])); ok {
+		return t
+	}
+	return note.ModTime
+}
+
+// dqlTaskDate converts an emoji task date to a DQL date, or nil.
+func dqlTaskDate(date *string) any {
+	if date != nil {
+		if t, ok := parseDQLDate(*date); ok {
+			return t
+		}
+	}
+	return nil
+}
+
+func dqlTaskFields(task *Task) map[string]any {
+	var priority any
+	if task.Priority != nil {
+		priority = *task.Priority
+	}
+	tags := make([]any, 0, len(task.Tags))
+	for _, tag := range task.Tags {
+		tags = append(tags, "#"+tag)
+	}
+	return map[string]any{
+		"text":       task.Text,
+		"completed":  task.StatusType == statusTypeDone,
+		"checked":    task.Status != " ",
+		"status":     task.Status,
+		"line":       float64(task.Line),
+		"due":        dqlTaskDate(task.DueDate),
+		"scheduled":  dqlTaskDate(task.ScheduledDate),
+		"start":      dqlTaskDate(task.StartDate),
+		"created":    dqlTaskDate(task.CreatedDate),
+		"completion": dqlTaskDate(task.DoneDate),
+		"priority":   priority,
+		"tags":       tags,
+		"path":       filepath.ToSlash(task.File),
+	}
+}
+
+func (s *dqlSourceTag) matches(note *indexedNote, _ *dqlEnv) bool {
+	for _, tag := range note.Tags {
+		tag = strings.ToLower(tag)
+		if tag == s.tag || strings.HasPrefix(tag, s.tag+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *dqlSourceFolder) matches(note *indexedNote, _ *dqlEnv) bool {
+	path := filepath.ToSlash(note.RelPath)
+	return s.path == "" || path == s.path || path == s.path+".md" || strings.HasPrefix(path, s.path+"/")
+}
+
+func (s *dqlSourceLink) matches(note *indexedNote, env *dqlEnv) bool {
+	target, ok := env.resolve(s.target, "")
+	if !ok {
+		return false
+	}
+	if s.outgoing {
+		return slices.Contains(env.outlinks[target], note.RelPath)
+	}
+	return slices.Contains(env.inlinks[target], note.RelPath)
+}
+
+func (s *dqlSourceAnd) matches(note *indexedNote, env *dqlEnv) bool {
+	return s.left.matches(note, env) && s.right.matches(note, env)
+}
+
+func (s *dqlSourceOr) matches(note *indexedNote, env *dqlEnv) bool {
+	return s.left.matches(note, env) || s.right.matches(note, env)
+}
+
+func (s *dqlSourceNot) matches(note *indexedNote, env *dqlEnv) bool {
+	return !s.inner.matches(note, env)
+}
+
+// dqlRow is one row flowing through the query pipeline: a page, a task, or a
+// group produced by GROUP BY.
+type dqlRow struct {
+	ctx     map[string]any
+	note    *indexedNote
+	task    *Task
+	grouped bool
+	key     any
+	members []dqlRow
+}
+
+// DataviewResult is the structured output of a DQL query. For tables, each
+// row's Values line up with Headers (including the File or group column
+// unless WITHOUT ID was used).
+type DataviewResult struct {
+	Type    string        `json:"type"`
+	Headers []string      `json:"headers,omitempty"`
+	Rows    []DataviewRow `json:"rows"`
+}
+
+// DataviewRow is a single result row.
+type DataviewRow struct {
+	File   string `json:"file,omitempty"`
+	Group  any    `json:"group,omitempty"`
+	Values []any  `json:"values,omitempty"`
+	Tasks  []Task `json:"tasks,omitempty"`
+}
+
+// execute evaluates the query over the notes in env.
+func (q *dqlQuery) execute(env *dqlEnv) *DataviewResult {
+	var rows []dqlRow
+	for _, note := range env.notes {
+		if q.from != nil && !q.from.matches(note, env) {
+			continue
+		}
+		page := env.page(note)
+		if q.kind != dqlTask {
+			rows = append(rows, dqlRow{ctx: page, note: note})
+			continue
+		}
+		for _, task := range env.tasks(note) {
+			ctx := make(map[string]any, len(page)+8)
+			for k, v := range page {
+				ctx[k] = v
+			}
+			for k, v := range dqlTaskFields(task) {
+				ctx[k] = v
+			}
+			rows = append(rows, dqlRow{ctx: ctx, note: note, task: task})
+		}
+	}
+
+	groupName := ""
+	for _, cmd := range q.commands {
+		switch cmd.kind {
+		case dqlCmdWhere:
+			filtered := rows[:0:0]
+			for _, row := range rows {
+				if dqlTruthy(cmd.expr.eval(row.ctx)) {
+					filtered = append(filtered, row)
+				}
+			}
+			rows = filtered
+		case dqlCmdSort:
+			sortDQLRows(rows, cmd.sorts)
+		case dqlCmdGroupBy:
+			rows = groupDQLRows(rows, cmd.expr, cmd.name)
+			groupName = cmd.name
+		case dqlCmdLimit:
+			if len(rows) > cmd.limit {
+				rows = rows[:cmd.limit]
+			}
+		}
+	}
+	return q.buildResult(rows, groupName)
+}
+
+func sortDQLRows(rows []dqlRow, sorts []dqlSort) {
+	keys := make([][]any, len(rows))
+	for i, row := range rows {
+		keys[i] = make([]any, len(sorts))
+		for j, s := range sorts {
+			keys[i][j] = s.expr.eval(row.ctx)
+		}
+	}
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		for j, s := range sorts {
+			c := dqlCompare(keys[order[a]][j], keys[order[b]][j])
+			if s.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	sorted := make([]dqlRow, len(rows))
+	for i, idx := range order {
+		sorted[i] = rows[idx]
+	}
+	copy(rows, sorted)
+}
+
+// groupDQLRows buckets rows by key. Group contexts expose the key as "key"
+// (and under the GROUP BY alias) and the grouped row contexts as "rows".
+func groupDQLRows(rows []dqlRow, keyExpr dqlExpr, name string) []dqlRow {
+	var groups []dqlRow
+	index := make(map[string]int)
+	for _, row := range rows {
+		key := keyExpr.eval(row.ctx)
+		id := dqlGroupID(key)
+		idx, ok := index[id]
+		if !ok {
+			groups = append(groups, dqlRow{grouped: true, key: key})
+			idx = len(groups) - 1
+			index[id] = idx
+		}
+		groups[idx].members = append(groups[idx].members, row)
+	}
+	sort.SliceStable(groups, func(a, b int) bool { return dqlCompare(groups[a].key, groups[b].key) < 0 })
+
+	for i := range groups {
+		members := make([]any, len(groups[i].members))
+		for j, member := range groups[i].members {
+			members[j] = member.ctx
+		}
+		groups[i].ctx = map[string]any{"key": groups[i].key, "rows": members}
+		if name != "" {
+			groups[i].ctx[strings.ToLower(name)] = groups[i].key
+		}
+	}
+	return groups
+}
+
+// dqlGroupID returns a canonical string for a GROUP BY key, so keys that
+// dqlCompare treats as equal share a group. Links are compared by their
+// lower-cased path.
+func dqlGroupID(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "b:" + strconv.FormatBool(val)
+	case float64:
+		return "n:" + strconv.FormatFloat(val, 'g', -1, 64)
+	case dqlDuration:
+		return "d:" + strconv.FormatInt(int64(val.approx()), 10)
+	case time.Time:
+		return "t:" + strconv.FormatInt(val.UnixNano(), 10)
+	case string:
+		return "s:" + val
+	case dqlLink:
+		return "l:" + strings.ToLower(string(val))
+	case []any:
+		ids := make([]string, len(val))
+		for i, item := range val {
+			ids[i] = dqlGroupID(item)
+		}
+		return "a:[" + strings.Join(ids, "\x00") + "]"
+	}
+	return "o:"
+}
+
+func (q *dqlQuery) buildResult(rows []dqlRow, groupName string) *DataviewResult {
+	result := &DataviewResult{Type: string(q.kind), Rows: []DataviewRow{}}
+	if q.kind == dqlTask {
+		result.Rows = buildDQLTaskRows(rows)
+		return result
+	}
+
+	if q.kind == dqlTable {
+		if !q.withoutID {
+			idHeader := "File"
+			if groupName != "" {
+				idHeader = groupName
+			}
+			result.Headers = append(result.Headers, idHeader)
+		}
+		for _, col := range q.columns {
+			result.Headers = append(result.Headers, col.name)
+		}
+	}
+
+	for _, row := range rows {
+		out := DataviewRow{}
+		var id any
+		if row.grouped {
+			out.Group = dqlExport(row.key)
+			id = out.Group
+		} else {
+			out.File = row.note.RelPath
+			id = dqlExport(dqlLink(dqlNotePath(row.note.RelPath)))
+		}
+		if q.kind == dqlTable && !q.withoutID {
+			out.Values = append(out.Values, id)
+		}
+		for _, col := range q.columns {
+			out.Values = append(out.Values, dqlExport(col.expr.eval(row.ctx)))
+		}
+		if q.kind == dqlList && row.grouped && len(q.columns) == 0 {
+			out.Values = []any{dqlExport(dqlGet(dqlGet(row.ctx["rows"], "file"), "link"))}
+		}
+		result.Rows = append(result.Rows, out)
+	}
+	return result
+}
+
+// buildDQLTaskRows collects tasks per file, or per group after GROUP BY.
+func buildDQLTaskRows(rows []dqlRow) []DataviewRow {
+	out := []DataviewRow{}
+	byFile := make(map[string]int)
+	for _, row := range rows {
+		if row.grouped {
+			out = append(out, DataviewRow{Group: dqlExport(row.key), Tasks: collectDQLTasks(row.members)})
+			continue
+		}
+		idx, ok := byFile[row.note.RelPath]
+		if !ok {
+			out = append(out, DataviewRow{File: row.note.RelPath})
+			idx = len(out) - 1
+			byFile[row.note.RelPath] = idx
+		}
+		out[idx].Tasks = append(out[idx].Tasks, *row.task)
+	}
+	return out
+}
+
+func collectDQLTasks(rows []dqlRow) []Task {
+	var tasks []Task
+	for _, row := range rows {
+		if row.grouped {
+			tasks = append(tasks, collectDQLTasks(row.members)...)
+		} else if row.task != nil {
+			tasks = append(tasks, *row.task)
+		}
+	}
+	return tasks
+}
@@ -310,6 +310,115 @@ func (v *Vault) ReplaceSectionHandler(ctx context.Context, req *mcp.CallToolRequ
 	}, nil, nil
 }
 
+// RenameHeadingHandler renames a heading and rewrites every link to it across
+// the vault
+func (v *Vault) RenameHeadingHandler(ctx context.Context, req *mcp.CallToolRequest, args RenameHeadingArgs) (*mcp.CallToolResult, any, error) {
+	notePath := args.Path
+	newHeading := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(args.NewHeading), "#"))
+	if args.Heading == "" || newHeading == "" {
+		return nil, nil, fmt.Errorf("heading and new_heading are required")
+	}
+
+	if !strings.HasSuffix(notePath, ".md") {
+		notePath += ".md"
+	}
+
+	fullPath := filepath.Join(v.GetPath(), notePath)
+	if !v.isPathSafe(fullPath) {
+		return nil, nil, fmt.Errorf("path must be within vault")
+	}
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("note not found: %s", notePath)
+		}
+		return nil, nil, fmt.Errorf("failed to read note: %v", err)
+	}
+	if err := ensureExpectedMtime(fullPath, args.ExpectedMtime); err != nil {
+		return nil, nil, err
+	}
+
+	headingLine := findHeadingLine(strings.Split(string(content), "\n"), args.Heading)
+	if headingLine < 0 {
+		return nil, nil, fmt.Errorf("heading '%s' not found in %s", args.Heading, notePath)
+	}
+	oldHeading := strings.TrimSpace(headingRegex.FindStringSubmatch(strings.Split(string(content), "\n")[headingLine])[2])
+
+	// Resolve links against the vault before the heading changes
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan links: %v", err)
+	}
+	relPath := filepath.Clean(notePath)
+
+	type headingLinkUpdate struct {
+		path    string
+		content string
+		links   int
+	}
+	var updates []headingLinkUpdate
+	sources := make([]string, 0, len(resolver.notes))
+	for source := range resolver.notes {
+		sources = append(sources, source)
+	}
+	if _, ok := resolver.notes[relPath]; !ok {
+		sources = append(sources, relPath) // the note itself may be ignored by scans
+	}
+	sort.Strings(sources)
+
+	totalLinks, linkingFiles := 0, 0
+	for _, source := range sources {
+		data, err := os.ReadFile(filepath.Join(v.GetPath(), source))
+		if err != nil {
+			continue
+		}
+		newContent, n := resolver.rewriteHeadingLinks(string(data), source, relPath, oldHeading, newHeading)
+		if source == relPath {
+			lines := strings.Split(newContent, "\n")
+			m := headingRegex.FindStringSubmatch(lines[headingLine])
+			lines[headingLine] = m[1] + " " + newHeading
+			newContent = strings.Join(lines, "\n")
+		}
+		if newContent == string(data) {
+			continue
+		}
+		updates = append(updates, headingLinkUpdate{path: source, content: newContent, links: n})
+		totalLinks += n
+		if n > 0 {
+			linkingFiles++
+		}
+	}
+
+	if !args.DryRun {
+		for _, u := range updates {
+			if err := os.WriteFile(filepath.Join(v.GetPath(), u.path), []byte(u.content), 0o600); err != nil {
+				return nil, nil, fmt.Errorf("failed to write %s: %v", u.path, err)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	if args.DryRun {
+		fmt.Fprintf(&sb, "Dry run: would rename heading '%s' → '%s' in %s\n", oldHeading, newHeading, notePath)
+		fmt.Fprintf(&sb, "Would update %d links in %d files", totalLinks, linkingFiles)
+	} else {
+		fmt.Fprintf(&sb, "Renamed heading '%s' → '%s' in %s\n", oldHeading, newHeading, notePath)
+		fmt.Fprintf(&sb, "Updated %d links in %d files", totalLinks, linkingFiles)
+	}
+	for _, u := range updates {
+		if u.links > 0 {
+			fmt.Fprintf(&sb, "\n- %s (%d links)", u.path, u.links)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: sb.String()},
+		},
+	}, nil, nil
+}
+
 // AppendNoteHandler appends content to a note, optionally at a specific position
 func (v *Vault) AppendNoteHandler(ctx context.Context, req *mcp.CallToolRequest, args AppendNoteArgs) (*mcp.CallToolResult, any, error) {
 	notePath := args.Path
@@ -460,6 +569,25 @@ func createNewNote(fullPath, notePath, content, expectedMtime string) (*mcp.Call
 	}, nil
 }
 
+// findHeadingLine returns the index of the first heading outside code blocks
+// that a link to heading would match, or -1.
+func findHeadingLine(lines []string, heading string) int {
+	want := normalizeHeadingRef(strings.TrimLeft(strings.TrimSpace(heading), "#"))
+	for i := frontmatterLineCount(lines); i < len(lines); i++ {
+		if fence, _ := codeFence(lines[i]); fence != "" {
+			if end := closingFence(lines, i+1, fence); end > 0 {
+				i = end
+				continue
+			}
+			break
+		}
+		if m := headingRegex.FindStringSubmatch(lines[i]); m != nil && normalizeHeadingRef(m[2]) == want {
+			return i
+		}
+	}
+	return -1
+}
+
 // findTargetLine finds the line index matching the target string (heading or text)
 func findTargetLine(lines []string, target string) (int, error) {
 	targetLower := strings.ToLower(target)
@@ -1100,3 +1100,52 @@ func TestValidateBatchEdits(t *testing.T) {
 		}
 	})
 }
+
+func TestRenameHeadingHandler(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "Plan.md", "# Plan\n\n```md\n## Goals: 2026\n```\n\n## Goals: 2026\n\nSee [[#Goals 2026]].\n")
+	writeTestFile(t, dir, "other/Plan.md", "## Goals: 2026\n")
+	writeTestFile(t, dir, "a.md", strings.Join([]string{
+		"[[Plan#Goals 2026|goals]] ![[Plan#goals: 2026]]",
+		"[[Plan#Plan#Goals 2026]] [[Plan#^goals]] [[other/Plan#Goals 2026]]",
+		"[md](Plan.md#Goals%202026) [angle](<Plan.md#Goals 2026>)",
+	}, "\n"))
+	writeTestFile(t, dir, "b.md", "[[Plan]] [[Plan#Plan]]\n")
+	ctx := context.Background()
+	args := RenameHeadingArgs{Path: "Plan", Heading: "goals 2026", NewHeading: "## Targets & Milestones", DryRun: true}
+
+	result, _, err := v.RenameHeadingHandler(ctx, nil, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"Dry run: would rename heading 'Goals: 2026' → 'Targets & Milestones' in Plan.md", "Would update 6 links in 2 files", "- a.md (5 links)", "- Plan.md (1 links)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("dry run missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "b.md") || readTestFile(t, dir, "a.md") == "" || !strings.Contains(readTestFile(t, dir, "Plan.md"), "## Goals: 2026\n\nSee") {
+		t.Errorf("dry run changed files or listed b.md:\n%s", text)
+	}
+
+	args.DryRun = false
+	if _, _, err := v.RenameHeadingHandler(ctx, nil, args); err != nil {
+		t.Fatal(err)
+	}
+	wantA := strings.Join([]string{
+		"[[Plan#Targets & Milestones|goals]] ![[Plan#Targets & Milestones]]",
+		"[[Plan#Plan#Targets & Milestones]] [[Plan#^goals]] [[other/Plan#Goals 2026]]",
+		"[md](Plan.md#Targets%20&%20Milestones) [angle](<Plan.md#Targets & Milestones>)",
+	}, "\n")
+	if got := readTestFile(t, dir, "a.md"); got != wantA {
+		t.Errorf("a.md =\n%s\nwant\n%s", got, wantA)
+	}
+	wantPlan := "# Plan\n\n```md\n## Goals: 2026\n```\n\n## Targets & Milestones\n\nSee [[#Targets & Milestones]].\n"
+	if got := readTestFile(t, dir, "Plan.md"); got != wantPlan {
+		t.Errorf("Plan.md = %q, want %q", got, wantPlan)
+	}
+
+	if _, _, err := v.RenameHeadingHandler(ctx, nil, RenameHeadingArgs{Path: "Plan", Heading: "Nope", NewHeading: "X"}); err == nil {
+		t.Error("expected missing heading to fail")
+	}
+}
@@ -0,0 +1,434 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Embedder turns text into vectors for semantic search. Vectors from
+// different embedders are never compared: the vector index is rebuilt
+// whenever the embedder's Name changes.
+type Embedder interface {
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// SetEmbedder sets the embedder used for semantic search. Without one, the
+// built-in LSA embedder is fitted to the vault's own text.
+func (v *Vault) SetEmbedder(e Embedder) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.embedder = e
+}
+
+func (v *Vault) getEmbedder() Embedder {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.embedder
+}
+
+const (
+	lsaDims       = 100
+	lsaDimsPer    = 4 // passages per dimension in small vaults
+	lsaOversample = 10
+	lsaPowerIters = 2
+	lsaMaxVocab   = 20000
+)
+
+// lsaEmbedder embeds text with latent semantic analysis: TF-IDF term weights
+// projected onto the top singular vectors of the vault's chunk-term matrix,
+// so passages that use related words land close together. It works offline
+// and is fitted to the vault, then stored with the vector index.
+type lsaEmbedder struct {
+	Terms map[string]int // term -> row in Basis
+	IDF   []float64
+	Basis [][]float32 // per term, its weight in each dimension
+	Dims  int
+}
+
+func (e *lsaEmbedder) Name() string { return "lsa" }
+
+func (e *lsaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.project(e.weights(termCounts(text)))
+	}
+	return vectors, nil
+}
+
+func termCounts(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, term := range tokenize(text) {
+		counts[term]++
+	}
+	return counts
+}
+
+// weights returns the unit-length TF-IDF weights of the known terms.
+func (e *lsaEmbedder) weights(counts map[string]int) map[int]float64 {
+	weights := make(map[int]float64, len(counts))
+	norm := 0.0
+	for term, count := range counts {
+		j, ok := e.Terms[term]
+		if !ok {
+			continue
+		}
+		w := (1 + math.Log(float64(count))) * e.IDF[j]
+		weights[j] = w
+		norm += w * w
+	}
+	norm = math.Sqrt(norm)
+	for j := range weights {
+		weights[j] /= norm
+	}
+	return weights
+}
+
+func (e *lsaEmbedder) project(weights map[int]float64) []float32 {
+	vec := make([]float32, e.Dims)
+	for j, w := range weights {
+		for d, b := range e.Basis[j] {
+			vec[d] += float32(w) * b
+		}
+	}
+	return normalizeVector(vec)
+}
+
+// fitLSA builds an LSA embedder from a corpus of passages, keeping the
+// lsaMaxVocab terms found in the most passages.
+func fitLSA(texts []string) *lsaEmbedder {
+	counts := make([]map[string]int, len(texts))
+	df := make(map[string]int)
+	for i, text := range texts {
+		counts[i] = termCounts(text)
+		for term := range counts[i] {
+			df[term]++
+		}
+	}
+	vocab := make([]string, 0, len(df))
+	for term := range df {
+		vocab = append(vocab, term)
+	}
+	sort.Slice(vocab, func(i, j int) bool {
+		if df[vocab[i]] != df[vocab[j]] {
+			return df[vocab[i]] > df[vocab[j]]
+		}
+		return vocab[i] < vocab[j]
+	})
+	if len(vocab) > lsaMaxVocab {
+		vocab = vocab[:lsaMaxVocab]
+	}
+
+	e := &lsaEmbedder{Terms: make(map[string]int, len(vocab)), IDF: make([]float64, len(vocab))}
+	n := float64(len(texts))
+	for j, term := range vocab {
+		e.Terms[term] = j
+		e.IDF[j] = math.Log((1+n)/(1+float64(df[term]))) + 1
+	}
+	rows := make([]map[int]float64, len(texts))
+	for i := range texts {
+		rows[i] = e.weights(counts[i])
+	}
+	// At full rank LSA only reproduces TF-IDF, so small vaults get fewer
+	// dimensions to let passages with related words share them.
+	e.Basis, e.Dims = truncatedSVD(rows, len(vocab), min(lsaDims, max(len(texts)/lsaDimsPer, 2)))
+	return e
+}
+
+// truncatedSVD returns the top k right singular vectors of a sparse matrix,
+// one row per term, using a seeded randomized range finder so the result
+// is deterministic.
+func truncatedSVD(rows []map[int]float64, terms, k int) (basis [][]float32, dims int) {
+	basis = make([][]float32, terms)
+	l := min(k+lsaOversample, len(rows), terms)
+	if l == 0 {
+		return basis, 0
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	omega := make([][]float64, l)
+	for c := range omega {
+		omega[c] = make([]float64, terms)
+		for j := range omega[c] {
+			omega[c][j] = rng.NormFloat64()
+		}
+	}
+	q := orthonormalize(mulRows(rows, omega, len(rows)))
+	for range lsaPowerIters {
+		q = orthonormalize(mulRows(rows, orthonormalize(mulRowsT(rows, q, terms)), len(rows)))
+	}
+
+	// z = Aᵀq is Bᵀ for the small matrix B = qᵀA, whose singular vectors
+	// come from the eigenvectors of BBᵀ = zᵀz.
+	z := mulRowsT(rows, q, terms)
+	gram := make([][]float64, l)
+	for a := range gram {
+		gram[a] = make([]float64, l)
+		for b := range gram[a] {
+			gram[a][b] = dot(z[a], z[b])
+		}
+	}
+	values, vectors := jacobiEigen(gram)
+	order := make([]int, l)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return values[order[i]] > values[order[j]] })
+	for _, r := range order {
+		if dims == k || values[r] < 1e-12 {
+			break
+		}
+		dims++
+	}
+
+	for j := range basis {
+		basis[j] = make([]float32, dims)
+	}
+	for d, r := range order[:dims] {
+		sigma := math.Sqrt(values[r])
+		for a := range z {
+			coef := vectors[a][r] / sigma
+			for j, x := range z[a] {
+				basis[j][d] += float32(coef * x)
+			}
+		}
+	}
+	return basis, dims
+}
+
+// mulRows multiplies a sparse matrix by the columns in cols.
+func mulRows(rows []map[int]float64, cols [][]float64, m int) [][]float64 {
+	out := make([][]float64, len(cols))
+	for c, col := range cols {
+		out[c] = make([]float64, m)
+		for i, row := range rows {
+			for j, w := range row {
+				out[c][i] += w * col[j]
+			}
+		}
+	}
+	return out
+}
+
+// mulRowsT multiplies the transpose of a sparse matrix by the columns in cols.
+func mulRowsT(rows []map[int]float64, cols [][]float64, n int) [][]float64 {
+	out := make([][]float64, len(cols))
+	for c, col := range cols {
+		out[c] = make([]float64, n)
+		for i, row := range rows {
+			for j, w := range row {
+				out[c][j] += w * col[i]
+			}
+		}
+	}
+	return out
+}
+
+// orthonormalize makes cols orthonormal in place with modified Gram-Schmidt.
+// Columns that are linearly dependent on earlier ones become zero.
+func orthonormalize(cols [][]float64) [][]float64 {
+	for c, col := range cols {
+		for _, prev := range cols[:c] {
+			p := dot(col, prev)
+			for i := range col {
+				col[i] -= p * prev[i]
+			}
+		}
+		norm := math.Sqrt(dot(col, col))
+		for i := range col {
+			if norm < 1e-10 {
+				col[i] = 0
+			} else {
+				col[i] /= norm
+			}
+		}
+	}
+	return cols
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// jacobiEigen returns the eigenvalues of a symmetric matrix and the matching
+// eigenvectors as columns, using cyclic Jacobi rotations. The matrix is
+// modified in place.
+func jacobiEigen(a [][]float64) (values []float64, vectors [][]float64) {
+	n := len(a)
+	vectors = make([][]float64, n)
+	for i := range vectors {
+		vectors[i] = make([]float64, n)
+		vectors[i][i] = 1
+	}
+
+	for range 100 {
+		off := 0.0
+		for p := range n {
+			for q := p + 1; q < n; q++ {
+				off += a[p][q] * a[p][q]
+			}
+		}
+		if off < 1e-22 {
+			break
+		}
+		for p := range n {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := range n {
+					a[k][p], a[k][q] = c*a[k][p]-s*a[k][q], s*a[k][p]+c*a[k][q]
+				}
+				for k := range n {
+					a[p][k], a[q][k] = c*a[p][k]-s*a[q][k], s*a[p][k]+c*a[q][k]
+				}
+				for k := range n {
+					vectors[k][p], vectors[k][q] = c*vectors[k][p]-s*vectors[k][q], s*vectors[k][p]+c*vectors[k][q]
+				}
+			}
+		}
+	}
+
+	values = make([]float64, n)
+	for i := range values {
+		values[i] = a[i][i]
+	}
+	return values, vectors
+}
+
+// normalizeVector scales vec to unit length in place, leaving zero vectors
+// alone.
+func normalizeVector(vec []float32) []float32 {
+	norm := 0.0
+	for _, x := range vec {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		return vec
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vec {
+		vec[i] *= scale
+	}
+	return vec
+}
+
+// cosine returns the cosine similarity of two unit vectors.
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	sum := 0.0
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+const httpEmbedBatch = 64
+
+// httpEmbedder calls an OpenAI-compatible embeddings endpoint, such as
+// OpenAI itself or a local Ollama, LM Studio or llama.cpp server.
+type httpEmbedder struct {
+	url    string
+	model  string
+	apiKey string
+	client *http.Client
+}
+
+// NewHTTPEmbedder returns an Embedder for the OpenAI-compatible API at
+// baseURL (for example http://localhost:11434/v1). apiKey may be empty for
+// local servers.
+func NewHTTPEmbedder(baseURL, model, apiKey string) Embedder {
+	return &httpEmbedder{
+		url:    strings.TrimSuffix(baseURL, "/") + "/embeddings",
+		model:  model,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+func (e *httpEmbedder) Name() string { return e.model + "@" + e.url }
+
+func (e *httpEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += httpEmbedBatch {
+		batch, err := e.embedBatch(ctx, texts[start:min(start+httpEmbedBatch, len(texts))])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+func (e *httpEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(map[string]any{"model": e.model, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 256<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embeddings: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embedding request failed: %s: %s", resp.Status, truncateLine(strings.TrimSpace(string(data)), 200))
+	}
+
+	var parsed struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid embeddings response: %v", err)
+	}
+	vectors := make([][]float32, len(texts))
+	for _, item := range parsed.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("invalid embeddings response: index %d out of range", item.Index)
+		}
+		vectors[item.Index] = normalizeVector(item.Embedding)
+	}
+	for i, vec := range vectors {
+		if vec == nil {
+			return nil, fmt.Errorf("invalid embeddings response: missing embedding %d", i)
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,188 @@
+package vault
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Depth limits for inlining nested embeds on read.
+const (
+	defaultEmbedDepth = 3
+	maxEmbedDepth     = 10
+)
+
+// embedRenderer inlines ![[note]], ![[note#Heading]] and ![[note#^block]]
+// transclusions and collects embedded attachments.
+type embedRenderer struct {
+	resolver    *linkResolver
+	attachments []linkResolution
+	seen        map[string]bool
+}
+
+// resolveEmbeds replaces each note embed in content with the embedded note,
+// section or block, wrapped in obx:generated comment markers. Embeds inside
+// embedded content are resolved too, up to depth levels. Embeds of images
+// and other files are left in place and returned as attachments.
+func (v *Vault) resolveEmbeds(content, sourcePath string, depth int) (string, []linkResolution, error) {
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return "", nil, err
+	}
+	if depth <= 0 {
+		depth = defaultEmbedDepth
+	}
+	depth = min(depth, maxEmbedDepth)
+
+	r := &embedRenderer{resolver: resolver, seen: make(map[string]bool)}
+	return r.render(content, sourcePath, depth, []string{sourcePath}), r.attachments, nil
+}
+
+// render inlines the embeds in content, which belongs to sourcePath. stack
+// holds the embeds being expanded, to catch cycles.
+func (r *embedRenderer) render(content, sourcePath string, depth int, stack []string) string {
+	var sb strings.Builder
+	last := 0
+	for _, link := range parseNoteLinks(content) {
+		if !link.Embed {
+			continue
+		}
+		res := r.resolver.resolve(link.Target, sourcePath)
+		if isAttachmentLink(res) {
+			r.addAttachment(res)
+			continue
+		}
+
+		key := res.Target
+		if res.Ref.Heading != "" {
+			key += "#" + normalizeHeadingRef(res.Ref.Heading)
+		} else if res.Ref.Block != "" {
+			key += "#^" + strings.ToLower(res.Ref.Block)
+		}
+
+		var body string
+		switch {
+		case res.Missing == "note":
+			body = fmt.Sprintf("**Embed error:** no note matches %q", res.Ref.Path)
+		case res.broken():
+			body = "**Embed error:**" + res.anchorProblem()
+		case slices.Contains(stack, key):
+			body = fmt.Sprintf("**Embed error:** circular embed of %s", link.Target)
+		case depth <= 0:
+			body = "**Embed error:** embed depth limit reached"
+		default:
+			body = r.render(r.embedText(res), res.Target, depth-1, append(stack, key))
+		}
+
+		sb.WriteString(content[last:link.Start])
+		sb.WriteString(generatedBlockStart("embed", link.Target) + "\n")
+		sb.WriteString(strings.Trim(body, "\n") + "\n")
+		sb.WriteString(generatedBlockEnd)
+		last = link.End
+	}
+	if last == 0 {
+		return content
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
+}
+
+func (r *embedRenderer) addAttachment(res linkResolution) {
+	key := res.Target
+	if key == "" {
+		key = res.Raw
+	}
+	if !r.seen[key] {
+		r.seen[key] = true
+		r.attachments = append(r.attachments, res)
+	}
+}
+
+// embedText returns the part of a resolved note an embed shows: the whole
+// body, one heading's section, or one block.
+func (r *embedRenderer) embedText(res linkResolution) string {
+	note := r.resolver.notes[res.Target]
+	if note == nil {
+		return ""
+	}
+	lines := strings.Split(note.Body, "\n")
+
+	if res.Ref.Heading != "" {
+		want := normalizeHeadingRef(res.Ref.Heading)
+		for _, h := range note.Headings {
+			if normalizeHeadingRef(h.Text) == want {
+				return strings.Join(lines[h.Line-1:min(h.EndLine, len(lines))], "\n")
+			}
+		}
+	}
+	if res.Ref.Block != "" {
+		return blockText(lines, res.Ref.Block)
+	}
+	return note.Body
+}
+
+// blockText returns the block with the given id, without its ^id marker.
+// An id on a list item marks that item; an id alone on a line marks the
+// block above it, such as a table, quote or code block; otherwise it marks
+// the paragraph it ends.
+func blockText(lines []string, id string) string {
+	for i, line := range lines {
+		m := blockIDRegex.FindStringSubmatchIndex(line)
+		if m == nil || !strings.EqualFold(line[m[2]:m[3]], id) {
+			continue
+		}
+		marker := strings.TrimRight(line[:m[2]-1], " \t")
+		if isListItem(marker) {
+			return marker
+		}
+		end := i
+		if strings.TrimSpace(marker) == "" {
+			// The block above may be separated from its ID by a blank line.
+			end = i - 1
+			for end >= 0 && strings.TrimSpace(lines[end]) == "" {
+				end--
+			}
+		} else {
+			lines[i] = marker
+		}
+		if end < 0 {
+			return ""
+		}
+		start := end
+		if fenceStart, fenceEnd := enclosingCodeBlock(lines, end); fenceEnd == end {
+			start = fenceStart
+		} else {
+			for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+				start--
+			}
+		}
+		return strings.Join(lines[start:end+1], "\n")
+	}
+	return ""
+}
+
+// isListItem reports whether line is a bullet, numbered or task list item.
+func isListItem(line string) bool {
+	trimmed := strings.TrimLeft(line, " \t")
+	if len(trimmed) > 1 && strings.ContainsRune("-*+", rune(trimmed[0])) && trimmed[1] == ' ' {
+		return true
+	}
+	digits := len(trimmed) - len(strings.TrimLeft(trimmed, "0123456789"))
+	return digits > 0 && digits+1 < len(trimmed) && (trimmed[digits] == '.' || trimmed[digits] == ')') && trimmed[digits+1] == ' '
+}
+
+// formatAttachments lists embedded attachments for a read with resolved
+// embeds.
+func formatAttachments(attachments []linkResolution) string {
+	var sb strings.Builder
+	sb.WriteString("<!-- obx:generated attachments -->\n## Attachments\n")
+	for _, res := range attachments {
+		if res.Target == "" {
+			fmt.Fprintf(&sb, "- %s (missing)\n", res.Ref.Path)
+		} else {
+			fmt.Fprintf(&sb, "- %s\n", res.Target)
+		}
+	}
+	sb.WriteString(generatedBlockEnd)
+	return sb.String()
+}
@@ -0,0 +1,92 @@
+package vault
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestParseNoteLinksEmbeds(t *testing.T) {
+	content := "![[Note#Part]] [[Plain]] ![chart](chart.png)"
+	links := parseNoteLinks(content)
+	if len(links) != 3 || !links[0].Embed || links[1].Embed || !links[2].Embed {
+		t.Fatalf("unexpected embed flags: %+v", links)
+	}
+	if got := content[links[0].Start:links[0].End]; got != "![[Note#Part]]" {
+		t.Errorf("embed text = %q", got)
+	}
+	if got := ExtractEmbeds(content); strings.Join(got, ",") != "Note#Part,chart.png" {
+		t.Errorf("ExtractEmbeds = %v", got)
+	}
+}
+
+func TestReadResolveEmbeds(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "Source.md", "---\ntags: [x]\n---\n# Source\n\nIntro.\n\n## Part\n\nPart text.\n![[Deep]]\n\n## Other\n\nOther text.\n\n- item one ^item\n- item two\n\nA paragraph\nover two lines. ^para\n")
+	writeTestFile(t, dir, "Deep.md", "Deep text ![[Main]]\n")
+	writeTestFile(t, dir, "assets/pic.png", "png")
+	writeTestFile(t, dir, "Main.md", strings.Join([]string{
+		"# Main",
+		"![[Source#Part]]",
+		"![[Source#^item]]",
+		"![[Source#^para]]",
+		"![[Source#Nope]]",
+		"![[pic.png]]",
+		"![[gone.pdf]]",
+	}, "\n"))
+
+	result, _, err := v.ReadNoteHandler(context.Background(), nil, ReadNoteArgs{Path: "Main.md", ResolveEmbeds: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{
+		"<!-- obx:generated embed: Source#Part -->\n## Part\n\nPart text.\n<!-- obx:generated embed: Deep -->\nDeep text <!-- obx:generated embed: Main -->\n**Embed error:** circular embed of Main\n<!-- obx:generated end -->\n<!-- obx:generated end -->",
+		"<!-- obx:generated embed: Source#^item -->\n- item one\n<!-- obx:generated end -->",
+		"A paragraph\nover two lines.\n<!-- obx:generated end -->",
+		`**Embed error:** (no heading "Nope" in Source.md)`,
+		"![[pic.png]]",
+		"## Attachments\n- " + filepath.Join("assets", "pic.png") + "\n- gone.pdf (missing)\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("resolved note missing %q:\n%s", want, text)
+		}
+	}
+
+	result, _, err = v.ReadNoteHandler(context.Background(), nil, ReadNoteArgs{Path: "Main.md", ResolveEmbeds: true, EmbedDepth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "**Embed error:** embed depth limit reached") {
+		t.Errorf("expected depth limit:\n%s", text)
+	}
+}
+
+func TestForwardLinksListsEmbedsAndAttachments(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "Target.md", "# Target\n")
+	writeTestFile(t, dir, "img.png", "png")
+	writeTestFile(t, dir, "note.md", "![[Target]] [[Target]] ![[img.png]] ![[lost.jpg]]\n")
+
+	result, _, err := v.ForwardLinksHandler(context.Background(), nil, ForwardLinksArgs{Path: "note.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"(1 existing, 0 broken, 2 attachments)", "- ![[Target]] → Target.md", "- ![[img.png]] → img.png", "- ![[lost.jpg]] ⚠️ (missing)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("forward links missing %q:\n%s", want, text)
+		}
+	}
+
+	graph, err := v.buildLinkGraph(v.GetPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edges := graph.notes["note"].edges; len(edges) != 1 || edges[0].target != "Target" || !edges[0].embed {
+		t.Errorf("unexpected edges: %+v", edges)
+	}
+}
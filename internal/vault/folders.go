@@ -31,7 +31,7 @@ func (v *Vault) ListFoldersHandler(ctx context.Context, req *mcp.CallToolRequest
 
 	folders := make(map[string]*folderInfo)
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+	err := v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
@@ -172,9 +172,13 @@ func (v *Vault) MoveNoteHandler(ctx context.Context, req *mcp.CallToolRequest, a
 		return nil, nil, fmt.Errorf("failed to create directory: %v", err)
 	}
 
-	var updatedFiles int
-	if updateLinks && !dryRun {
-		updatedFiles = v.updateLinksForMove(sourcePath, destPath)
+	// Resolve links against the vault before anything moves
+	var rewriter *linkRewriter
+	if updateLinks {
+		var err error
+		if rewriter, err = v.newLinkRewriter(); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan links: %v", err)
+		}
 	}
 
 	if !dryRun {
@@ -184,6 +188,11 @@ func (v *Vault) MoveNoteHandler(ctx context.Context, req *mcp.CallToolRequest, a
 		}
 	}
 
+	var updatedFiles int
+	if rewriter != nil {
+		updatedFiles = rewriter.apply(map[string]string{filepath.Clean(sourcePath): filepath.Clean(destPath)}, dryRun)
+	}
+
 	var result string
 	if updateLinks {
 		if dryRun {
@@ -206,51 +215,6 @@ func (v *Vault) MoveNoteHandler(ctx context.Context, req *mcp.CallToolRequest, a
 	}, nil, nil
 }
 
-// updateLinksForMove updates wikilinks when a note is moved
-func (v *Vault) updateLinksForMove(oldPath, newPath string) int {
-	oldName := strings.TrimSuffix(oldPath, ".md")
-	oldBase := strings.TrimSuffix(filepath.Base(oldPath), ".md")
-	newName := strings.TrimSuffix(newPath, ".md")
-	newBase := strings.TrimSuffix(filepath.Base(newPath), ".md")
-
-	updatedFiles := 0
-
-	_ = filepath.Walk(v.GetPath(), func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		contentStr := string(content)
-		newContent := contentStr
-
-		// Replace full path links
-		newContent = strings.ReplaceAll(newContent, "[["+oldName+"]]", "[["+newName+"]]")
-		newContent = strings.ReplaceAll(newContent, "[["+oldName+"|", "[["+newName+"|")
-
-		// Replace basename links (if name changed)
-		if oldBase != newBase {
-			newContent = strings.ReplaceAll(newContent, "[["+oldBase+"]]", "[["+newBase+"]]")
-			newContent = strings.ReplaceAll(newContent, "[["+oldBase+"|", "[["+newBase+"|")
-		}
-
-		if newContent != contentStr {
-			if err := os.WriteFile(path, []byte(newContent), 0o600); err != nil {
-				return nil
-			}
-			updatedFiles++
-		}
-
-		return nil
-	})
-
-	return updatedFiles
-}
-
 // DeleteFolderHandler deletes an empty folder
 func (v *Vault) DeleteFolderHandler(ctx context.Context, req *mcp.CallToolRequest, args DeleteDirArgs) (*mcp.CallToolResult, any, error) {
 	folderPath := args.Path
@@ -6,36 +6,43 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// Frontmatter represents parsed YAML frontmatter
-type Frontmatter map[string]string
+// Frontmatter represents parsed YAML frontmatter. Keys are lower-cased and
+// values are typed: string, int, float64, bool, nil, []any or map[string]any.
+// Dates are kept as strings.
+type Frontmatter map[string]any
+
+// legacyKeyValueRegex matches flat key: value lines for frontmatter that is not
+// valid YAML
+var legacyKeyValueRegex = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_-]*)\s*:\s*(.*)$`)
 
 // ParseFrontmatter extracts frontmatter from note content
 func ParseFrontmatter(content string) Frontmatter {
-	fm := make(Frontmatter)
-
-	if !strings.HasPrefix(content, "---") {
-		return fm
+	doc, err := parseFrontmatterDoc(content)
+	if err == nil {
+		return doc.values()
 	}
+	return parseLegacyFrontmatter(content)
+}
 
-	endIdx := strings.Index(content[3:], "---")
-	if endIdx < 0 {
+// parseLegacyFrontmatter reads key: value lines as strings, so notes with
+// malformed YAML still expose whatever properties can be recovered.
+func parseLegacyFrontmatter(content string) Frontmatter {
+	fm := make(Frontmatter)
+
+	yamlText, _, ok := splitFrontmatter(content)
+	if !ok {
 		return fm
 	}
 
-	fmContent := content[3 : endIdx+3]
-	lines := strings.Split(fmContent, "\n")
-
-	// Simple YAML parsing (key: value)
-	keyValueRegex := regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_-]*)\s*:\s*(.*)$`)
-
-	for _, line := range lines {
+	for _, line := range strings.Split(yamlText, "\n") {
 		line = strings.TrimSpace(line)
-		if match := keyValueRegex.FindStringSubmatch(line); match != nil {
+		if match := legacyKeyValueRegex.FindStringSubmatch(line); match != nil {
 			key := strings.ToLower(match[1])
 			value := strings.Trim(strings.TrimSpace(match[2]), `"'`)
 			fm[key] = value
@@ -50,16 +57,9 @@ func (v *Vault) QueryFrontmatterHandler(ctx context.Context, req *mcp.CallToolRe
 	query := args.Query
 	dir := args.Directory
 
-	// Parse query (supports key=value or key:value)
-	var key, value string
-	if idx := strings.Index(query, "="); idx > 0 {
-		key = strings.ToLower(strings.TrimSpace(query[:idx]))
-		value = strings.ToLower(strings.TrimSpace(query[idx+1:]))
-	} else if idx := strings.Index(query, ":"); idx > 0 {
-		key = strings.ToLower(strings.TrimSpace(query[:idx]))
-		value = strings.ToLower(strings.TrimSpace(query[idx+1:]))
-	} else {
-		return nil, nil, fmt.Errorf("invalid query format: use key=value or key:value")
+	cond, err := parseFrontmatterQuery(query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid frontmatter query: %v", err)
 	}
 
 	searchPath := v.GetPath()
@@ -75,40 +75,24 @@ func (v *Vault) QueryFrontmatterHandler(ctx context.Context, req *mcp.CallToolRe
 		frontmatter Frontmatter
 	}
 
-	var results []result
-
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		fm := ParseFrontmatter(string(content))
-		if len(fm) == 0 {
-			return nil
-		}
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query failed: %v", err)
+	}
 
-		// Check if frontmatter matches query
-		if fmValue, ok := fm[key]; ok {
-			// Support partial matching (contains)
-			if strings.Contains(strings.ToLower(fmValue), value) {
-				relPath, _ := filepath.Rel(v.GetPath(), path)
-				results = append(results, result{path: relPath, frontmatter: fm})
-			}
+	var results []result
+	for _, note := range notes {
+		fm := note.Frontmatter
+		if cond.eval(fm) {
+			results = append(results, result{path: note.RelPath, frontmatter: fm})
 		}
+	}
 
-		return nil
-	})
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("query failed: %v", err)
+	if args.Sort != "" {
+		key, desc := parseFrontmatterSort(args.Sort)
+		sort.SliceStable(results, func(i, j int) bool {
+			return frontmatterLess(results[i].frontmatter, results[j].frontmatter, key, desc)
+		})
 	}
 
 	if len(results) == 0 {
@@ -124,8 +108,8 @@ func (v *Vault) QueryFrontmatterHandler(ctx context.Context, req *mcp.CallToolRe
 
 	for _, r := range results {
 		sb.WriteString(fmt.Sprintf("## %s\n", r.path))
-		for k, v := range r.frontmatter {
-			sb.WriteString(fmt.Sprintf("  %s: %s\n", k, v))
+		for _, k := range sortedFrontmatterKeys(r.frontmatter) {
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", k, frontmatterDisplay(r.frontmatter[k])))
 		}
 		sb.WriteString("\n")
 	}
@@ -137,6 +121,39 @@ func (v *Vault) QueryFrontmatterHandler(ctx context.Context, req *mcp.CallToolRe
 	}, nil, nil
 }
 
+// parseFrontmatterSort reads a sort spec such as "due", "due desc" or "-due".
+func parseFrontmatterSort(spec string) (key string, desc bool) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return "", false
+	}
+	key = fields[0]
+	if strings.HasPrefix(key, "-") {
+		return key[1:], true
+	}
+	return key, len(fields) > 1 && strings.EqualFold(fields[1], "desc")
+}
+
+// frontmatterLess orders notes by a property. Notes without the property sort
+// last in either direction.
+func frontmatterLess(a, b Frontmatter, key string, desc bool) bool {
+	av, aok := frontmatterLookup(a, key)
+	bv, bok := frontmatterLookup(b, key)
+	aok = aok && frontmatterText(av) != ""
+	bok = bok && frontmatterText(bv) != ""
+	if !aok || !bok {
+		return aok && !bok
+	}
+	cmp, ok := compareFrontmatterValues(av, bv)
+	if !ok {
+		cmp = strings.Compare(frontmatterText(av), frontmatterText(bv))
+	}
+	if desc {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
 // GetFrontmatterHandler returns frontmatter for a specific note
 func (v *Vault) GetFrontmatterHandler(ctx context.Context, req *mcp.CallToolRequest, args GetFrontmatterArgs) (*mcp.CallToolResult, any, error) {
 	path := args.Path
@@ -159,9 +176,13 @@ func (v *Vault) GetFrontmatterHandler(ctx context.Context, req *mcp.CallToolRequ
 		return nil, nil, fmt.Errorf("failed to read note: %v", err)
 	}
 
-	fm := ParseFrontmatter(string(content))
+	doc, err := parseFrontmatterDoc(string(content))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid frontmatter in %s: %v", path, err)
+	}
 
-	if len(fm) == 0 {
+	keys := doc.keys()
+	if len(keys) == 0 {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				&mcp.TextContent{Text: fmt.Sprintf("No frontmatter found in: %s", path)},
@@ -171,8 +192,9 @@ func (v *Vault) GetFrontmatterHandler(ctx context.Context, req *mcp.CallToolRequ
 
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Frontmatter for %s:\n\n", path))
-	for k, v := range fm {
-		sb.WriteString(fmt.Sprintf("%s: %s\n", k, v))
+	for _, k := range keys {
+		value, _ := doc.get(k)
+		sb.WriteString(fmt.Sprintf("%s: %s\n", k, frontmatterDisplay(value)))
 	}
 
 	return &mcp.CallToolResult{
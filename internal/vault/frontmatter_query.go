@@ -0,0 +1,415 @@
+package vault
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file implements the frontmatter query language used by
+// search-vault frontmatter:
+//
+//	priority>=2 AND status!=done AND due<2026-11-01 AND tags contains "client"
+//	(status=active OR status=review) AND NOT owner missing
+//	title:plan
+//
+// Conditions compare a property with a literal using =, !=, <, <=, > or >=,
+// test substrings with ":" or membership with "contains", and check presence
+// with "exists" and "missing". Adjacent conditions are ANDed, OR binds looser
+// than AND, and parentheses group sub-expressions. Keywords are
+// case-insensitive and dotted keys reach into nested maps.
+
+type fmQueryNode interface {
+	eval(fm Frontmatter) bool
+}
+
+type fmQueryAnd struct{ left, right fmQueryNode }
+
+type fmQueryOr struct{ left, right fmQueryNode }
+
+type fmQueryNot struct{ inner fmQueryNode }
+
+// fmQueryCond is a single property test.
+type fmQueryCond struct {
+	key   string
+	op    string // =, !=, <, <=, >, >=, :, contains, exists, missing
+	value string
+}
+
+func (n *fmQueryAnd) eval(fm Frontmatter) bool { return n.left.eval(fm) && n.right.eval(fm) }
+
+func (n *fmQueryOr) eval(fm Frontmatter) bool { return n.left.eval(fm) || n.right.eval(fm) }
+
+func (n *fmQueryNot) eval(fm Frontmatter) bool { return !n.inner.eval(fm) }
+
+func (n *fmQueryCond) eval(fm Frontmatter) bool {
+	actual, ok := frontmatterLookup(fm, n.key)
+	present := ok && frontmatterText(actual) != ""
+
+	switch n.op {
+	case "exists":
+		return present
+	case "missing":
+		return !present
+	case "!=":
+		return !ok || !frontmatterMatches(actual, n.value, "=")
+	}
+	if !ok {
+		return false
+	}
+	return frontmatterMatches(actual, n.value, n.op)
+}
+
+// frontmatterMatches applies op to a property value and a query literal. List
+// values match when any item does.
+func frontmatterMatches(actual any, literal, op string) bool {
+	switch op {
+	case ":":
+		return strings.Contains(strings.ToLower(frontmatterText(actual)), strings.ToLower(literal))
+	case "contains":
+		switch v := actual.(type) {
+		case []any:
+			return frontmatterMatchesAny(v, literal, "=")
+		case map[string]any:
+			_, ok := frontmatterLookup(v, literal)
+			return ok
+		}
+		return strings.Contains(strings.ToLower(frontmatterText(actual)), strings.ToLower(literal))
+	}
+
+	if list, ok := actual.([]any); ok {
+		return frontmatterMatchesAny(list, literal, op)
+	}
+	cmp, ok := compareFrontmatterValues(actual, inferFrontmatterLiteral(literal))
+	if !ok {
+		if op != "=" {
+			return false
+		}
+		return strings.EqualFold(frontmatterText(actual), literal)
+	}
+	switch op {
+	case "=":
+		return cmp == 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func frontmatterMatchesAny(list []any, literal, op string) bool {
+	for _, item := range list {
+		if frontmatterMatches(item, literal, op) {
+			return true
+		}
+	}
+	return false
+}
+
+// frontmatterLookup finds a property case-insensitively. Dotted keys such as
+// owner.name descend into nested maps when no property has the full name.
+func frontmatterLookup(fm map[string]any, key string) (any, bool) {
+	for k, value := range fm {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+	head, rest, ok := strings.Cut(key, ".")
+	if !ok {
+		return nil, false
+	}
+	for k, value := range fm {
+		if !strings.EqualFold(k, head) {
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			return frontmatterLookup(nested, rest)
+		}
+	}
+	return nil, false
+}
+
+// inferFrontmatterLiteral types a query literal the way YAML would type the
+// same text: numbers, booleans, and everything else as a string.
+func inferFrontmatterLiteral(literal string) any {
+	if f, err := strconv.ParseFloat(literal, 64); err == nil {
+		return f
+	}
+	switch strings.ToLower(literal) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return literal
+}
+
+// compareFrontmatterValues orders two scalar values. Numbers compare
+// numerically, booleans false before true, strings that both parse as dates
+// chronologically, and other strings case-insensitively. ok is false when
+// the values have incompatible types.
+func compareFrontmatterValues(a, b any) (cmp int, ok bool) {
+	a, b = frontmatterNumber(a), frontmatterNumber(b)
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			s, isString := b.(string)
+			if !isString {
+				return 0, false
+			}
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return 0, false
+			}
+			bv = f
+		}
+		return compareFloats(av, bv), true
+	case bool:
+		bv, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case av == bv:
+			return 0, true
+		case bv:
+			return -1, true
+		}
+		return 1, true
+	case string:
+		switch bv := b.(type) {
+		case string:
+			if at, ok := parseDQLDate(av); ok {
+				if bt, ok := parseDQLDate(bv); ok {
+					return at.Compare(bt), true
+				}
+			}
+			return strings.Compare(strings.ToLower(av), strings.ToLower(bv)), true
+		case float64:
+			cmp, ok := compareFrontmatterValues(b, a)
+			return -cmp, ok
+		}
+	}
+	return 0, false
+}
+
+func frontmatterNumber(value any) any {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	}
+	return value
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// parseFrontmatterQuery parses a frontmatter query expression.
+func parseFrontmatterQuery(input string) (fmQueryNode, error) {
+	p := &fmQueryParser{input: []rune(input)}
+	p.skipSpace()
+	if p.done() {
+		return nil, fmt.Errorf("empty query")
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at position %d", string(p.input[p.pos]), p.pos+1)
+	}
+	return node, nil
+}
+
+type fmQueryParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *fmQueryParser) done() bool { return p.pos >= len(p.input) }
+
+func (p *fmQueryParser) skipSpace() {
+	for !p.done() && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// keyword consumes word (case-insensitive) when it appears as a whole word.
+func (p *fmQueryParser) keyword(word string) bool {
+	end := p.pos + len(word)
+	if end > len(p.input) || !strings.EqualFold(string(p.input[p.pos:end]), word) {
+		return false
+	}
+	if end < len(p.input) && isFrontmatterKeyRune(p.input[end]) {
+		return false
+	}
+	p.pos = end
+	p.skipSpace()
+	return true
+}
+
+func (p *fmQueryParser) parseOr() (fmQueryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &fmQueryOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *fmQueryParser) parseAnd() (fmQueryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for !p.done() && p.input[p.pos] != ')' {
+		save := p.pos
+		if p.keyword("OR") {
+			p.pos = save
+			break
+		}
+		p.keyword("AND")
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &fmQueryAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *fmQueryParser) parseUnary() (fmQueryNode, error) {
+	if p.keyword("NOT") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &fmQueryNot{inner: inner}, nil
+	}
+	if !p.done() && p.input[p.pos] == '(' {
+		p.pos++
+		p.skipSpace()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.input[p.pos] != ')' {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		p.skipSpace()
+		return node, nil
+	}
+	return p.parseCond()
+}
+
+func (p *fmQueryParser) parseCond() (fmQueryNode, error) {
+	start := p.pos
+	for !p.done() && isFrontmatterKeyRune(p.input[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.done() {
+			return nil, fmt.Errorf("expected property name at end of query")
+		}
+		return nil, fmt.Errorf("expected property name at position %d", p.pos+1)
+	}
+	cond := &fmQueryCond{key: string(p.input[start:p.pos])}
+	p.skipSpace()
+
+	switch {
+	case p.keyword("exists"):
+		cond.op = "exists"
+		return cond, nil
+	case p.keyword("missing"):
+		cond.op = "missing"
+		return cond, nil
+	case p.keyword("contains"):
+		cond.op = "contains"
+	default:
+		for _, op := range []string{">=", "<=", "!=", "=", "<", ">", ":"} {
+			end := p.pos + len(op)
+			if end <= len(p.input) && string(p.input[p.pos:end]) == op {
+				cond.op = op
+				p.pos = end
+				break
+			}
+		}
+		if cond.op == "" {
+			return nil, fmt.Errorf("expected operator after %q", cond.key)
+		}
+		p.skipSpace()
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %v", cond.key, cond.op, err)
+	}
+	cond.value = value
+	return cond, nil
+}
+
+// parseValue reads a double-quoted string or a bare word ending at
+// whitespace or a closing parenthesis.
+func (p *fmQueryParser) parseValue() (string, error) {
+	if p.done() {
+		return "", fmt.Errorf("missing value")
+	}
+	if p.input[p.pos] == '"' {
+		var sb strings.Builder
+		for p.pos++; !p.done(); p.pos++ {
+			r := p.input[p.pos]
+			if r == '\\' && p.pos+1 < len(p.input) {
+				p.pos++
+				sb.WriteRune(p.input[p.pos])
+				continue
+			}
+			if r == '"' {
+				p.pos++
+				p.skipSpace()
+				return sb.String(), nil
+			}
+			sb.WriteRune(r)
+		}
+		return "", fmt.Errorf("unterminated quoted value")
+	}
+
+	start := p.pos
+	for !p.done() && !unicode.IsSpace(p.input[p.pos]) && p.input[p.pos] != ')' {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", fmt.Errorf("missing value")
+	}
+	value := string(p.input[start:p.pos])
+	p.skipSpace()
+	return value, nil
+}
+
+func isFrontmatterKeyRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' || r == '/'
+}
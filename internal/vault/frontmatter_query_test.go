@@ -0,0 +1,103 @@
+package vault
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func setupFrontmatterQueryVault(t *testing.T) *Vault {
+	t.Helper()
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "alpha.md", "---\npriority: 3\nstatus: active\ndue: 2026-10-20\ntags: [client, urgent]\narchived: false\nowner:\n  name: Alice\n---\n# Alpha")
+	writeTestFile(t, dir, "beta.md", "---\npriority: 1\nstatus: done\ndue: 2026-12-01\ntags: [internal]\narchived: true\n---\n# Beta")
+	writeTestFile(t, dir, "gamma.md", "---\npriority: 2\nstatus: review\ndue:\ntags:\n  - client\n---\n# Gamma")
+	writeTestFile(t, dir, "plain.md", "# No frontmatter")
+	return v
+}
+
+func frontmatterQueryPaths(t *testing.T, v *Vault, query, sortKey string) []string {
+	t.Helper()
+	result, _, err := v.QueryFrontmatterHandler(context.Background(), nil, QueryFrontmatterArgs{Query: query, Sort: sortKey})
+	if err != nil {
+		t.Fatalf("query %q failed: %v", query, err)
+	}
+	var paths []string
+	for _, line := range strings.Split(result.Content[0].(*mcp.TextContent).Text, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			paths = append(paths, strings.TrimPrefix(line, "## "))
+		}
+	}
+	return paths
+}
+
+func TestQueryFrontmatterTyped(t *testing.T) {
+	v := setupFrontmatterQueryVault(t)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`priority>=2 AND status!=done AND due<2026-11-01 AND tags contains "client"`, []string{"alpha.md"}},
+		{`priority > 1`, []string{"alpha.md", "gamma.md"}},
+		{`priority=1`, []string{"beta.md"}},
+		{`status=ACTIVE`, []string{"alpha.md"}},
+		{`status:act`, []string{"alpha.md"}},
+		{`status!=done`, []string{"alpha.md", "gamma.md", "plain.md"}},
+		{`due <= 2026-12-01`, []string{"alpha.md", "beta.md"}},
+		{`archived = true`, []string{"beta.md"}},
+		{`tags contains client`, []string{"alpha.md", "gamma.md"}},
+		{`tags = urgent`, []string{"alpha.md"}},
+		{`due exists`, []string{"alpha.md", "beta.md"}},
+		{`due missing`, []string{"gamma.md", "plain.md"}},
+		{`owner.name = alice`, []string{"alpha.md"}},
+		{`status=done OR (priority<3 and tags contains client)`, []string{"beta.md", "gamma.md"}},
+		{`NOT status=active status exists`, []string{"beta.md", "gamma.md"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := frontmatterQueryPaths(t, v, tt.query, ""); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryFrontmatterSort(t *testing.T) {
+	v := setupFrontmatterQueryVault(t)
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"priority", []string{"beta.md", "gamma.md", "alpha.md"}},
+		{"priority desc", []string{"alpha.md", "gamma.md", "beta.md"}},
+		{"-due", []string{"beta.md", "alpha.md", "gamma.md"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			if got := frontmatterQueryPaths(t, v, "status exists", tt.sort); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFrontmatterQueryErrors(t *testing.T) {
+	for _, q := range []string{
+		``,
+		`priority`,
+		`priority >=`,
+		`(status=done`,
+		`status=done)`,
+		`title="unterminated`,
+		`AND status=done`,
+	} {
+		if _, err := parseFrontmatterQuery(q); err == nil {
+			t.Errorf("expected error for %q", q)
+		}
+	}
+}
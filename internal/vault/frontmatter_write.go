@@ -5,15 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v3"
 )
 
-// frontmatterRegex matches the frontmatter block
-var frontmatterRegex = regexp.MustCompile(`(?s)^---\n(.*?)\n---\n?`)
-
 // SetFrontmatterHandler sets or updates a frontmatter property
 func (v *Vault) SetFrontmatterHandler(ctx context.Context, req *mcp.CallToolRequest, args SetFrontmatterArgs) (*mcp.CallToolResult, any, error) {
 	notePath := args.Path
@@ -40,8 +37,15 @@ func (v *Vault) SetFrontmatterHandler(ctx context.Context, req *mcp.CallToolRequ
 	if err := ensureExpectedMtime(fullPath, expectedMtime); err != nil {
 		return nil, nil, err
 	}
+	if err := validateFrontmatter(string(content)); err != nil {
+		return nil, nil, fmt.Errorf("invalid frontmatter in %s: %v", notePath, err)
+	}
 
-	newContent := setFrontmatterKey(string(content), key, value)
+	node, err := v.propertyValueNode(key, value)
+	if err != nil {
+		return nil, nil, err
+	}
+	newContent := setFrontmatterNode(string(content), key, node)
 
 	if err := os.WriteFile(fullPath, []byte(newContent), 0o600); err != nil {
 		return nil, nil, fmt.Errorf("failed to write note: %v", err)
@@ -79,6 +83,9 @@ func (v *Vault) RemoveFrontmatterKeyHandler(ctx context.Context, req *mcp.CallTo
 	if err := ensureExpectedMtime(fullPath, expectedMtime); err != nil {
 		return nil, nil, err
 	}
+	if err := validateFrontmatter(string(content)); err != nil {
+		return nil, nil, fmt.Errorf("invalid frontmatter in %s: %v", notePath, err)
+	}
 
 	newContent, removed := removeFrontmatterKey(string(content), key)
 	if !removed {
@@ -125,6 +132,9 @@ func (v *Vault) AddAliasHandler(ctx context.Context, req *mcp.CallToolRequest, a
 	if err := ensureExpectedMtime(fullPath, expectedMtime); err != nil {
 		return nil, nil, err
 	}
+	if err := validateFrontmatter(string(content)); err != nil {
+		return nil, nil, fmt.Errorf("invalid frontmatter in %s: %v", notePath, err)
+	}
 
 	newContent := addToFrontmatterArray(string(content), "aliases", alias)
 
@@ -167,6 +177,9 @@ func (v *Vault) AddTagToFrontmatterHandler(ctx context.Context, req *mcp.CallToo
 	if err := ensureExpectedMtime(fullPath, expectedMtime); err != nil {
 		return nil, nil, err
 	}
+	if err := validateFrontmatter(string(content)); err != nil {
+		return nil, nil, fmt.Errorf("invalid frontmatter in %s: %v", notePath, err)
+	}
 
 	newContent := addToFrontmatterArray(string(content), "tags", tag)
 
@@ -181,152 +194,48 @@ func (v *Vault) AddTagToFrontmatterHandler(ctx context.Context, req *mcp.CallToo
 	}, nil, nil
 }
 
-// setFrontmatterKey sets a key in frontmatter, creating frontmatter if needed
+// setFrontmatterKey sets a key in frontmatter, creating frontmatter if needed.
+// The value is interpreted by frontmatterValueNode, so JSON lists and objects
+// become YAML structures. Content with invalid frontmatter is returned as is.
 func setFrontmatterKey(content, key, value string) string {
-	// Normalize key to lowercase
-	key = strings.ToLower(key)
-
-	if !strings.HasPrefix(content, "---") {
-		// No frontmatter, create it
-		return fmt.Sprintf("---\n%s: %s\n---\n\n%s", key, value, content)
-	}
-
-	match := frontmatterRegex.FindStringSubmatch(content)
-	if match == nil {
-		// Invalid frontmatter, add new
-		return fmt.Sprintf("---\n%s: %s\n---\n\n%s", key, value, content)
-	}
-
-	fmContent := match[1]
-	body := content[len(match[0]):]
-
-	// Check if key exists
-	keyPattern := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(key) + `\s*:.*$`)
-	if keyPattern.MatchString(fmContent) {
-		// Update existing key
-		fmContent = keyPattern.ReplaceAllString(fmContent, fmt.Sprintf("%s: %s", key, value))
-	} else {
-		// Add new key
-		fmContent = fmContent + "\n" + key + ": " + value
-	}
+	return setFrontmatterNode(content, key, frontmatterValueNode(value))
+}
 
-	return fmt.Sprintf("---\n%s\n---\n%s", strings.TrimSpace(fmContent), body)
+// setFrontmatterNode sets a key to an already-built YAML value.
+func setFrontmatterNode(content, key string, value *yaml.Node) string {
+	return editFrontmatter(content, func(doc *frontmatterDoc) bool {
+		doc.set(key, value)
+		return true
+	})
 }
 
 // removeFrontmatterKey removes a key from frontmatter
 func removeFrontmatterKey(content, key string) (string, bool) {
-	key = strings.ToLower(key)
-
-	if !strings.HasPrefix(content, "---") {
-		return content, false
-	}
-
-	match := frontmatterRegex.FindStringSubmatch(content)
-	if match == nil {
-		return content, false
-	}
-
-	fmContent := match[1]
-	body := content[len(match[0]):]
-
-	// Find and remove the key
-	keyPattern := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(key) + `\s*:.*\n?`)
-	if !keyPattern.MatchString(fmContent) {
-		return content, false
-	}
-
-	fmContent = keyPattern.ReplaceAllString(fmContent, "")
-	fmContent = strings.TrimSpace(fmContent)
-
-	if fmContent == "" {
-		// No more frontmatter, remove it entirely
-		return strings.TrimPrefix(body, "\n"), true
-	}
-
-	return fmt.Sprintf("---\n%s\n---\n%s", fmContent, body), true
+	var removed bool
+	newContent := editFrontmatter(content, func(doc *frontmatterDoc) bool {
+		removed = doc.remove(key)
+		return removed
+	})
+	return newContent, removed
 }
 
 // addToFrontmatterArray adds a value to an array property in frontmatter
 func addToFrontmatterArray(content, key, value string) string {
-	key = strings.ToLower(key)
-
-	if !strings.HasPrefix(content, "---") {
-		// No frontmatter, create it with the array
-		return fmt.Sprintf("---\n%s:\n  - %s\n---\n\n%s", key, value, content)
-	}
-
-	match := frontmatterRegex.FindStringSubmatch(content)
-	if match == nil {
-		return fmt.Sprintf("---\n%s:\n  - %s\n---\n\n%s", key, value, content)
-	}
-
-	fmContent := match[1]
-	body := content[len(match[0]):]
-
-	// Parse existing array
-	lines := strings.Split(fmContent, "\n")
-	var newLines []string
-	keyFound := false
-	inArray := false
-	arrayEnded := false
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		// Check if this is the key we're looking for
-		if strings.HasPrefix(strings.ToLower(trimmed), key+":") {
-			keyFound = true
-			newLines = append(newLines, line)
-
-			// Check if inline array format: key: [a, b, c]
-			if strings.Contains(line, "[") {
-				// Convert inline to multi-line and add new value
-				inlineMatch := regexp.MustCompile(`\[([^\]]*)\]`).FindStringSubmatch(line)
-				if inlineMatch != nil {
-					// Remove the inline array from this line
-					newLines[len(newLines)-1] = key + ":"
-					// Add existing values
-					existing := strings.Split(inlineMatch[1], ",")
-					for _, v := range existing {
-						v = strings.TrimSpace(v)
-						if v != "" {
-							newLines = append(newLines, "  - "+v)
-						}
-					}
-					// Add new value
-					newLines = append(newLines, "  - "+value)
-					arrayEnded = true
-				}
-			} else {
-				inArray = true
-			}
-			continue
-		}
-
-		// If we're in the array section
-		if inArray && !arrayEnded {
-			if strings.HasPrefix(trimmed, "- ") {
-				newLines = append(newLines, line)
-				continue
-			}
-			// Array ended, add new value before this line
-			newLines = append(newLines, "  - "+value)
-			arrayEnded = true
-			inArray = false
-		}
-
-		newLines = append(newLines, line)
-	}
+	return editFrontmatter(content, func(doc *frontmatterDoc) bool {
+		return doc.addToList(key, value)
+	})
+}
 
-	// If we were in array at end of frontmatter
-	if inArray && !arrayEnded {
-		newLines = append(newLines, "  - "+value)
+// editFrontmatter applies edit to the parsed frontmatter and re-renders the
+// note when edit reports a change.
+func editFrontmatter(content string, edit func(doc *frontmatterDoc) bool) string {
+	doc, err := parseFrontmatterDoc(content)
+	if err != nil || !edit(doc) {
+		return content
 	}
-
-	// If key not found, add it
-	if !keyFound {
-		newLines = append(newLines, key+":", "  - "+value)
+	newContent, err := doc.render()
+	if err != nil {
+		return content
 	}
-
-	return fmt.Sprintf("---\n%s\n---\n%s", strings.Join(newLines, "\n"), body)
+	return newContent
 }
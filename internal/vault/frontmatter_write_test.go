@@ -35,11 +35,18 @@ func TestSetFrontmatterKey(t *testing.T) {
 			expected: "---\ntitle: My Note\n---\n\n# Just Content\n\nBody text",
 		},
 		{
-			name:     "key is lowercased",
+			name:     "new key keeps its case",
 			content:  "---\ntitle: Test\n---\n\n# Content",
 			key:      "Status",
 			value:    "done",
-			expected: "---\ntitle: Test\nstatus: done\n---\n\n# Content",
+			expected: "---\ntitle: Test\nStatus: done\n---\n\n# Content",
+		},
+		{
+			name:     "existing key matches any case",
+			content:  "---\nTitle: Test\n---\n\n# Content",
+			key:      "title",
+			value:    "Renamed",
+			expected: "---\nTitle: Renamed\n---\n\n# Content",
 		},
 	}
 
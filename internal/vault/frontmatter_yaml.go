@@ -0,0 +1,511 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontmatterDoc is a note split into its YAML frontmatter and body. The YAML
+// is kept as a node tree for edits, along with the source text of each
+// property, so properties an edit does not touch are written back exactly as
+// they were.
+type frontmatterDoc struct {
+	node    *yaml.Node // document node wrapping a mapping
+	body    string     // everything after the closing ---
+	present bool       // whether the note had a frontmatter block
+
+	header       string                // source lines before the first property
+	spans        map[*yaml.Node]string // source text of a property, by key node
+	heads        map[*yaml.Node]string // blank and comment lines that open a span
+	dirty        map[*yaml.Node]bool   // properties whose value was replaced
+	compactLists bool                  // block lists are written at the key's indent
+}
+
+// splitFrontmatter separates a leading ---/--- block from the rest of the note.
+// body starts right after the closing delimiter line.
+func splitFrontmatter(content string) (yamlText, body string, ok bool) {
+	if !strings.HasPrefix(content, "---\n") && !strings.HasPrefix(content, "---\r\n") {
+		return "", content, false
+	}
+	start := strings.IndexByte(content, '\n') + 1
+	for pos := start; pos <= len(content); {
+		end := strings.IndexByte(content[pos:], '\n')
+		line, next := content[pos:], len(content)
+		if end >= 0 {
+			line, next = content[pos:pos+end], pos+end+1
+		}
+		if strings.TrimRight(line, "\r") == "---" {
+			return content[start:pos], content[next:], true
+		}
+		if end < 0 {
+			break
+		}
+		pos = next
+	}
+	return "", content, false
+}
+
+// parseFrontmatterDoc parses the frontmatter of a note. A note without
+// frontmatter yields an empty document; invalid YAML is an error.
+func parseFrontmatterDoc(content string) (*frontmatterDoc, error) {
+	yamlText, body, ok := splitFrontmatter(content)
+	doc := &frontmatterDoc{
+		body:    body,
+		present: ok,
+		spans:   make(map[*yaml.Node]string),
+		heads:   make(map[*yaml.Node]string),
+		dirty:   make(map[*yaml.Node]bool),
+	}
+
+	var node yaml.Node
+	if ok {
+		if err := yaml.Unmarshal([]byte(yamlText), &node); err != nil {
+			return nil, fmt.Errorf("invalid frontmatter YAML: %v", err)
+		}
+	}
+	if node.Kind == 0 {
+		node = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if node.Kind != yaml.DocumentNode || len(node.Content) != 1 || node.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("frontmatter must be a YAML mapping of properties")
+	}
+	doc.node = &node
+
+	doc.splitProperties(yamlText)
+	return doc, nil
+}
+
+// splitProperties records the source text of each top-level property. A
+// property's span runs from the blank and comment lines above its key to the
+// next property's span. Frontmatter whose keys cannot be placed on their own
+// lines, such as a flow mapping, keeps no spans and is re-encoded.
+func (d *frontmatterDoc) splitProperties(yamlText string) {
+	m := d.mapping()
+	if m.Style&yaml.FlowStyle != 0 || len(m.Content) == 0 {
+		return
+	}
+	lines := strings.SplitAfter(yamlText, "\n")
+	n := len(m.Content) / 2
+	starts, keyLines := make([]int, n), make([]int, n)
+	prev := -1
+	for i := 0; i < n; i++ {
+		key := m.Content[2*i]
+		k := key.Line - 1
+		if k <= prev || k >= len(lines) || key.Column != 1 {
+			return
+		}
+		j := k
+		for j > prev+1 && strings.HasPrefix(lines[j-1], "#") {
+			j--
+		}
+		for j > prev+1 && strings.TrimSpace(lines[j-1]) == "" {
+			j--
+		}
+		starts[i], keyLines[i], prev = j, k, k
+	}
+
+	d.header = strings.Join(lines[:starts[0]], "")
+	for i := 0; i < n; i++ {
+		end := len(lines)
+		if i+1 < n {
+			end = starts[i+1]
+		}
+		key := m.Content[2*i]
+		d.spans[key] = strings.Join(lines[starts[i]:end], "")
+		d.heads[key] = strings.Join(lines[starts[i]:keyLines[i]], "")
+	}
+
+	// Follow the note's list style: "- item" under the key, or indented
+	for i := 1; i < len(m.Content); i += 2 {
+		value := m.Content[i]
+		if value.Kind == yaml.SequenceNode && value.Style&yaml.FlowStyle == 0 && len(value.Content) > 0 {
+			if line := value.Content[0].Line - 1; line < len(lines) {
+				d.compactLists = strings.HasPrefix(lines[line], "-")
+			}
+			break
+		}
+	}
+}
+
+// validateFrontmatter reports whether a note's frontmatter can be edited safely.
+func validateFrontmatter(content string) error {
+	_, err := parseFrontmatterDoc(content)
+	return err
+}
+
+func (d *frontmatterDoc) mapping() *yaml.Node {
+	return d.node.Content[0]
+}
+
+// index returns the position of key's key node in the mapping, matching
+// case-insensitively, or -1.
+func (d *frontmatterDoc) index(key string) int {
+	m := d.mapping()
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if strings.EqualFold(m.Content[i].Value, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// keys returns the property names in document order.
+func (d *frontmatterDoc) keys() []string {
+	m := d.mapping()
+	keys := make([]string, 0, len(m.Content)/2)
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		keys = append(keys, m.Content[i].Value)
+	}
+	return keys
+}
+
+// get returns the typed value of a property.
+func (d *frontmatterDoc) get(key string) (any, bool) {
+	i := d.index(key)
+	if i < 0 {
+		return nil, false
+	}
+	return yamlNodeValue(d.mapping().Content[i+1]), true
+}
+
+// values returns all properties keyed by lower-cased name.
+func (d *frontmatterDoc) values() Frontmatter {
+	fm := make(Frontmatter)
+	m := d.mapping()
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		fm[strings.ToLower(m.Content[i].Value)] = yamlNodeValue(m.Content[i+1])
+	}
+	return fm
+}
+
+// set replaces a property's value, keeping the key's position and any
+// trailing comment, or appends key as given.
+func (d *frontmatterDoc) set(key string, value *yaml.Node) {
+	m := d.mapping()
+	if i := d.index(key); i >= 0 {
+		replacement := *value
+		if replacement.LineComment == "" {
+			replacement.LineComment = m.Content[i+1].LineComment
+		}
+		m.Content[i+1] = &replacement
+		d.dirty[m.Content[i]] = true
+		return
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	m.Content = append(m.Content, keyNode, value)
+}
+
+// remove deletes a property and reports whether it existed.
+func (d *frontmatterDoc) remove(key string) bool {
+	i := d.index(key)
+	if i < 0 {
+		return false
+	}
+	m := d.mapping()
+	m.Content = append(m.Content[:i], m.Content[i+2:]...)
+	return true
+}
+
+// listItems returns the string items of a list property. A scalar value is
+// treated as a comma-separated list, as Obsidian does for tags and aliases.
+func (d *frontmatterDoc) listItems(key string) []string {
+	i := d.index(key)
+	if i < 0 {
+		return nil
+	}
+	var items []string
+	value := d.mapping().Content[i+1]
+	switch value.Kind {
+	case yaml.SequenceNode:
+		for _, item := range value.Content {
+			items = append(items, item.Value)
+		}
+	case yaml.ScalarNode:
+		for _, item := range strings.Split(value.Value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// setList writes a list property in block style.
+func (d *frontmatterDoc) setList(key string, items []string) {
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, item := range items {
+		seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+	}
+	d.set(key, seq)
+}
+
+// addToList appends item to a list property unless it is already present.
+func (d *frontmatterDoc) addToList(key, item string) bool {
+	items := d.listItems(key)
+	for _, existing := range items {
+		if strings.EqualFold(existing, item) {
+			return false
+		}
+	}
+	d.setList(key, append(items, item))
+	return true
+}
+
+// removeFromList removes item from a list property, dropping the property
+// when the list becomes empty.
+func (d *frontmatterDoc) removeFromList(key, item string) bool {
+	items := d.listItems(key)
+	kept := items[:0:0]
+	for _, existing := range items {
+		if !strings.EqualFold(existing, item) {
+			kept = append(kept, existing)
+		}
+	}
+	if len(kept) == len(items) {
+		return false
+	}
+	if len(kept) == 0 {
+		d.remove(key)
+	} else {
+		d.setList(key, kept)
+	}
+	return true
+}
+
+// render serializes the note. A document left without properties loses its
+// frontmatter block entirely.
+func (d *frontmatterDoc) render() (string, error) {
+	if len(d.mapping().Content) == 0 {
+		if d.present {
+			return strings.TrimPrefix(d.body, "\n"), nil
+		}
+		return d.body, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(d.header)
+	m := d.mapping()
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		key, value := m.Content[i], m.Content[i+1]
+		span, ok := d.spans[key]
+		if ok && !d.dirty[key] {
+			sb.WriteString(span)
+			if !strings.HasSuffix(span, "\n") {
+				sb.WriteString("\n")
+			}
+			continue
+		}
+		text, err := d.encodeProperty(key, value, ok)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode frontmatter: %v", err)
+		}
+		sb.WriteString(d.heads[key])
+		sb.WriteString(text)
+	}
+
+	body := d.body
+	if !d.present {
+		body = "\n" + body
+	}
+	return "---\n" + sb.String() + "---\n" + body, nil
+}
+
+// encodeProperty writes a single property. When the property came from the
+// note, its head comment is already in the span's head and is left out.
+func (d *frontmatterDoc) encodeProperty(key, value *yaml.Node, fromSource bool) (string, error) {
+	k := *key
+	if fromSource {
+		k.HeadComment = ""
+	}
+	text, err := encodeYAML(&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{&k, value}})
+	if err != nil {
+		return "", err
+	}
+	if d.compactLists && value.Kind == yaml.SequenceNode && value.Style&yaml.FlowStyle == 0 {
+		lines := strings.SplitAfter(text, "\n")
+		for i := 1; i < len(lines); i++ {
+			lines[i] = strings.TrimPrefix(lines[i], "  ")
+		}
+		text = strings.Join(lines, "")
+	}
+	return text, nil
+}
+
+// encodeYAML encodes node with two-space indentation. The encoder escapes
+// characters outside the Basic Multilingual Plane, so emoji would come out as
+// "\U0001F680"; they are swapped for unused private-use characters while
+// encoding and put back afterwards.
+func encodeYAML(node *yaml.Node) (string, error) {
+	used := make(map[rune]bool)
+	var collect func(n *yaml.Node)
+	collect = func(n *yaml.Node) {
+		for _, text := range []string{n.Value, n.HeadComment, n.LineComment, n.FootComment} {
+			for _, r := range text {
+				used[r] = true
+			}
+		}
+		for _, c := range n.Content {
+			collect(c)
+		}
+	}
+	collect(node)
+
+	swaps, back := make(map[rune]rune), make(map[rune]rune)
+	next := rune(0xE000)
+	placeholder := func(r rune) rune {
+		if r <= 0xFFFF {
+			return r
+		}
+		if p, ok := swaps[r]; ok {
+			return p
+		}
+		for used[next] {
+			next++
+		}
+		swaps[r], back[next] = next, r
+		next++
+		return swaps[r]
+	}
+	var restore []func()
+	var swap func(n *yaml.Node)
+	swap = func(n *yaml.Node) {
+		if strings.IndexFunc(n.Value, func(r rune) bool { return r > 0xFFFF }) >= 0 {
+			original := n.Value
+			n.Value = strings.Map(placeholder, n.Value)
+			restore = append(restore, func() { n.Value = original })
+		}
+		for _, c := range n.Content {
+			swap(c)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	swap(node)
+	err := enc.Encode(node)
+	for _, undo := range restore {
+		undo()
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return strings.Map(func(r rune) rune {
+		if orig, ok := back[r]; ok {
+			return orig
+		}
+		return r
+	}, buf.String()), nil
+}
+
+// frontmatterValueNode converts a user-supplied value into a YAML node. JSON
+// arrays, objects and quoted strings are decoded; anything else is written as
+// a plain scalar so values like 2, true and 2024-01-31 keep their YAML types.
+func frontmatterValueNode(raw string) *yaml.Node {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, `"`) {
+		var value any
+		if err := json.Unmarshal([]byte(trimmed), &value); err == nil {
+			node := &yaml.Node{}
+			if err := node.Encode(value); err == nil {
+				return node
+			}
+		}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: raw}
+}
+
+// yamlNodeValue converts a node into plain Go values: string, int, float64,
+// bool, nil, []any and map[string]any. Dates are kept as their source text.
+func yamlNodeValue(n *yaml.Node) any {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) > 0 {
+			return yamlNodeValue(n.Content[0])
+		}
+		return nil
+	case yaml.AliasNode:
+		return yamlNodeValue(n.Alias)
+	case yaml.SequenceNode:
+		list := make([]any, 0, len(n.Content))
+		for _, item := range n.Content {
+			list = append(list, yamlNodeValue(item))
+		}
+		return list
+	case yaml.MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			m[n.Content[i].Value] = yamlNodeValue(n.Content[i+1])
+		}
+		return m
+	}
+
+	switch n.ShortTag() {
+	case "!!null":
+		return nil
+	case "!!bool", "!!int", "!!float":
+		var value any
+		if err := n.Decode(&value); err == nil {
+			return value
+		}
+	}
+	return n.Value
+}
+
+// frontmatterText flattens a typed value into plain text for searching:
+// lists are comma-joined and objects rendered as JSON.
+func frontmatterText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = frontmatterText(item)
+		}
+		return strings.Join(parts, ", ")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
+// frontmatterDisplay renders a value for tool output: strings as-is and
+// everything else as JSON, the same form `set` accepts.
+func frontmatterDisplay(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// sortedFrontmatterKeys returns the keys of fm in sorted order.
+func sortedFrontmatterKeys(fm Frontmatter) []string {
+	keys := make([]string, 0, len(fm))
+	for k := range fm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
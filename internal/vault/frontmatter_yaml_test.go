@@ -0,0 +1,239 @@
+package vault
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestParseFrontmatterTypedValues(t *testing.T) {
+	content := `---
+Title: "Quoted: title"
+priority: 2
+score: 4.5
+draft: false
+due: 2026-11-01
+empty:
+tags:
+  - project
+  - client/acme
+owner:
+  name: Alice
+  teams: [core, infra]
+summary: |
+  first line
+  second line
+---
+# Body`
+
+	fm := ParseFrontmatter(content)
+	want := Frontmatter{
+		"title":    "Quoted: title",
+		"priority": 2,
+		"score":    4.5,
+		"draft":    false,
+		"due":      "2026-11-01",
+		"empty":    nil,
+		"tags":     []any{"project", "client/acme"},
+		"owner":    map[string]any{"name": "Alice", "teams": []any{"core", "infra"}},
+		"summary":  "first line\nsecond line\n",
+	}
+	if !reflect.DeepEqual(fm, want) {
+		t.Errorf("ParseFrontmatter() = %#v, want %#v", fm, want)
+	}
+}
+
+func TestParseFrontmatterInvalidYAMLFallsBack(t *testing.T) {
+	content := "---\ntitle: Broken\ntags: [unclosed\n---\n# Body"
+
+	if _, err := parseFrontmatterDoc(content); err == nil {
+		t.Fatal("expected invalid YAML error")
+	}
+	if fm := ParseFrontmatter(content); fm["title"] != "Broken" {
+		t.Errorf("expected legacy fallback to recover title, got %#v", fm)
+	}
+	if got := setFrontmatterKey(content, "status", "done"); got != content {
+		t.Errorf("invalid frontmatter should not be rewritten, got:\n%s", got)
+	}
+}
+
+func TestSetFrontmatterKeyRoundTrip(t *testing.T) {
+	content := `---
+# project metadata
+title: "Plan"
+status: draft # updated weekly
+tags: [a, b]
+---
+Body`
+
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		want  string
+	}{
+		{
+			name:  "keeps comments, order and style",
+			key:   "status",
+			value: "active",
+			want:  "---\n# project metadata\ntitle: \"Plan\"\nstatus: active # updated weekly\ntags: [a, b]\n---\nBody",
+		},
+		{
+			name:  "typed scalar",
+			key:   "priority",
+			value: "3",
+			want:  "tags: [a, b]\npriority: 3\n---",
+		},
+		{
+			name:  "JSON list",
+			key:   "reviewers",
+			value: `["[[Alice]]", "Bob"]`,
+			want:  "reviewers:\n  - '[[Alice]]'\n  - Bob\n---",
+		},
+		{
+			name:  "JSON object",
+			key:   "meta",
+			value: `{"source": "import"}`,
+			want:  "meta:\n  source: import\n---",
+		},
+		{
+			name:  "string needing quotes",
+			key:   "note",
+			value: "see: #tag",
+			want:  "note: 'see: #tag'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := setFrontmatterKey(content, tt.key, tt.value)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("setFrontmatterKey() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+
+	fm := ParseFrontmatter(setFrontmatterKey(content, "reviewers", `["[[Alice]]", "Bob"]`))
+	if want := []any{"[[Alice]]", "Bob"}; !reflect.DeepEqual(fm["reviewers"], want) {
+		t.Errorf("reviewers = %#v, want %#v", fm["reviewers"], want)
+	}
+}
+
+func TestFrontmatterRoundTripKeepsBlankLinesAndKeyCase(t *testing.T) {
+	content := "---\ntitle: Plan\n\n# Status\nstatus: draft\n\n\nsummary: |\n  first\n\n  second\ntags:\n  - a\n---\nBody"
+
+	tests := []struct {
+		name string
+		edit func(doc *frontmatterDoc) bool
+		want string
+	}{
+		{
+			name: "unchanged",
+			edit: func(doc *frontmatterDoc) bool { return true },
+			want: content,
+		},
+		{
+			name: "set existing key",
+			edit: func(doc *frontmatterDoc) bool {
+				doc.set("STATUS", frontmatterValueNode("active"))
+				return true
+			},
+			want: "---\ntitle: Plan\n\n# Status\nstatus: active\n\n\nsummary: |\n  first\n\n  second\ntags:\n  - a\n---\nBody",
+		},
+		{
+			name: "new key keeps its case",
+			edit: func(doc *frontmatterDoc) bool {
+				doc.set("Reviewed By", frontmatterValueNode("Alice"))
+				return true
+			},
+			want: "---\ntitle: Plan\n\n# Status\nstatus: draft\n\n\nsummary: |\n  first\n\n  second\ntags:\n  - a\nReviewed By: Alice\n---\nBody",
+		},
+		{
+			name: "remove key",
+			edit: func(doc *frontmatterDoc) bool { return doc.remove("status") },
+			want: "---\ntitle: Plan\n\n\nsummary: |\n  first\n\n  second\ntags:\n  - a\n---\nBody",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := editFrontmatter(content, tt.edit); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrontmatterRoundTripKeepsUntouchedText(t *testing.T) {
+	content := "---\ntitle: Trip 🚀 plan\nemoji: 🙂\ncity: Zürich\nnote: \"café\"\nlist:\n- a\n- b\n---\nBody"
+	front := strings.TrimSuffix(content, "---\nBody")
+
+	tests := []struct {
+		name string
+		edit func(doc *frontmatterDoc) bool
+		want string
+	}{
+		{
+			name: "new key",
+			edit: func(doc *frontmatterDoc) bool {
+				doc.set("status", frontmatterValueNode("done"))
+				return true
+			},
+			want: front + "status: done\n---\nBody",
+		},
+		{
+			name: "emoji value",
+			edit: func(doc *frontmatterDoc) bool {
+				doc.set("emoji", frontmatterValueNode("🎉 party"))
+				return true
+			},
+			want: strings.Replace(content, "emoji: 🙂", "emoji: 🎉 party", 1),
+		},
+		{
+			name: "list keeps its indent",
+			edit: func(doc *frontmatterDoc) bool { return doc.addToList("list", "c") },
+			want: strings.Replace(content, "- b\n", "- b\n- c\n", 1),
+		},
+		{
+			name: "new list follows the note's style",
+			edit: func(doc *frontmatterDoc) bool { return doc.addToList("aliases", "Été 🌞") },
+			want: front + "aliases:\n- Été 🌞\n---\nBody",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := editFrontmatter(content, tt.edit); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFrontmatterHandlerRejectsInvalidYAML(t *testing.T) {
+	v, dir := setupTestVault(t)
+	original := "---\ntitle: [broken\n---\n# Body"
+	writeTestFile(t, dir, "broken.md", original)
+
+	_, _, err := v.SetFrontmatterHandler(context.Background(), nil, SetFrontmatterArgs{Path: "broken.md", Key: "status", Value: "done"})
+	if err == nil || !strings.Contains(err.Error(), "invalid frontmatter") {
+		t.Fatalf("expected invalid frontmatter error, got %v", err)
+	}
+	if got := readTestFile(t, dir, "broken.md"); got != original {
+		t.Errorf("note was modified:\n%s", got)
+	}
+}
+
+func TestGetFrontmatterHandlerTypedOutput(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "note.md", "---\nTitle: Plan\npriority: 2\ntags: [a, b]\n---\n# Body")
+
+	result, _, err := v.GetFrontmatterHandler(context.Background(), nil, GetFrontmatterArgs{Path: "note.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if want := "Title: Plan\npriority: 2\ntags: [\"a\",\"b\"]\n"; !strings.Contains(text, want) {
+		t.Errorf("GetFrontmatterHandler() = %q, want it to contain %q", text, want)
+	}
+}
@@ -0,0 +1,400 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Full-text fields, scored separately and combined with per-field boosts.
+const (
+	ftsFieldBody = iota
+	ftsFieldTitle
+	ftsFieldHeadings
+	ftsFieldFrontmatter
+	ftsFieldCount
+)
+
+// ftsBoosts weights a match in each field relative to a body match.
+var ftsBoosts = [ftsFieldCount]float64{
+	ftsFieldBody:        1.0,
+	ftsFieldTitle:       3.0,
+	ftsFieldHeadings:    2.0,
+	ftsFieldFrontmatter: 1.5,
+}
+
+const (
+	// ftsIndexVersion is bumped whenever the tokenizer or on-disk layout changes,
+	// forcing a full rebuild of persisted indexes.
+	ftsIndexVersion = 1
+	ftsIndexDir     = ".obx/index"
+	ftsIndexFile    = "fulltext.gob"
+
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// ftsDoc is the per-note state needed to score and incrementally update the index.
+type ftsDoc struct {
+	ModTime time.Time
+	Size    int64
+	Lengths [ftsFieldCount]int
+	Terms   []string // distinct terms, used to drop postings when the note changes
+}
+
+// ftsIndex is an inverted index over all notes in a vault.
+type ftsIndex struct {
+	Version  int
+	Docs     map[string]*ftsDoc                       // keyed by vault-relative path
+	Postings map[string]map[string][ftsFieldCount]int // term -> path -> per-field term frequency
+	TotalLen [ftsFieldCount]int
+}
+
+func newFTSIndex() *ftsIndex {
+	return &ftsIndex{
+		Version:  ftsIndexVersion,
+		Docs:     make(map[string]*ftsDoc),
+		Postings: make(map[string]map[string][ftsFieldCount]int),
+	}
+}
+
+// ftsStore owns the full-text index of the active vault, loading it from disk
+// on first use and persisting it after incremental updates.
+type ftsStore struct {
+	mu      sync.Mutex
+	root    string
+	idx     *ftsIndex
+	unsaved bool // the last save failed, so retry on the next search
+}
+
+// reset forgets the loaded index so the next search reloads it for the new root.
+func (s *ftsStore) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.root = ""
+	s.idx = nil
+}
+
+// ftsIndexPath returns the on-disk location of the full-text index for root.
+func ftsIndexPath(root string) string {
+	return filepath.Join(root, filepath.FromSlash(ftsIndexDir), ftsIndexFile)
+}
+
+// loadFTSIndex reads a persisted index, returning an empty one when it is
+// missing, unreadable or from an older version.
+func loadFTSIndex(root string) *ftsIndex {
+	data, err := os.ReadFile(ftsIndexPath(root))
+	if err != nil {
+		return newFTSIndex()
+	}
+
+	idx := newFTSIndex()
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(idx); err != nil || idx.Version != ftsIndexVersion {
+		return newFTSIndex()
+	}
+	if idx.Docs == nil || idx.Postings == nil {
+		return newFTSIndex()
+	}
+	return idx
+}
+
+// saveFTSIndex persists the index, writing a temp file and renaming it so a
+// crash never leaves a truncated index behind.
+func saveFTSIndex(root string, idx *ftsIndex) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(idx); err != nil {
+		return err
+	}
+
+	path := ftsIndexPath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ftsFields splits a note into the text of each indexed field.
+func ftsFields(note *indexedNote) [ftsFieldCount]string {
+	var fields [ftsFieldCount]string
+	fields[ftsFieldBody] = note.Body
+	fields[ftsFieldTitle] = strings.TrimSuffix(filepath.Base(note.RelPath), ".md")
+
+	headings := make([]string, 0, len(note.Headings))
+	for _, h := range note.Headings {
+		headings = append(headings, h.Text)
+	}
+	fields[ftsFieldHeadings] = strings.Join(headings, "\n")
+
+	keys := sortedFrontmatterKeys(note.Frontmatter)
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, frontmatterText(note.Frontmatter[k]))
+	}
+	fields[ftsFieldFrontmatter] = strings.Join(values, "\n")
+	return fields
+}
+
+// add indexes a note. The caller must have removed any previous version.
+func (idx *ftsIndex) add(note *indexedNote) {
+	doc := &ftsDoc{ModTime: note.ModTime, Size: note.Size}
+	freqs := make(map[string][ftsFieldCount]int)
+
+	for field, text := range ftsFields(note) {
+		terms := tokenize(text)
+		doc.Lengths[field] = len(terms)
+		idx.TotalLen[field] += len(terms)
+		for _, term := range terms {
+			tf := freqs[term]
+			tf[field]++
+			freqs[term] = tf
+		}
+	}
+
+	doc.Terms = make([]string, 0, len(freqs))
+	for term, tf := range freqs {
+		doc.Terms = append(doc.Terms, term)
+		postings := idx.Postings[term]
+		if postings == nil {
+			postings = make(map[string][ftsFieldCount]int)
+			idx.Postings[term] = postings
+		}
+		postings[note.RelPath] = tf
+	}
+	idx.Docs[note.RelPath] = doc
+}
+
+// remove drops a note and its postings from the index.
+func (idx *ftsIndex) remove(relPath string) {
+	doc, ok := idx.Docs[relPath]
+	if !ok {
+		return
+	}
+	for _, term := range doc.Terms {
+		postings := idx.Postings[term]
+		delete(postings, relPath)
+		if len(postings) == 0 {
+			delete(idx.Postings, term)
+		}
+	}
+	for field, n := range doc.Lengths {
+		idx.TotalLen[field] -= n
+	}
+	delete(idx.Docs, relPath)
+}
+
+// sync brings the index up to date with notes, re-indexing only notes whose
+// mtime or size changed. It reports whether anything changed.
+func (idx *ftsIndex) sync(notes []*indexedNote) bool {
+	changed := false
+	seen := make(map[string]bool, len(notes))
+	for _, note := range notes {
+		seen[note.RelPath] = true
+		if doc, ok := idx.Docs[note.RelPath]; ok && doc.ModTime.Equal(note.ModTime) && doc.Size == note.Size {
+			continue
+		}
+		idx.remove(note.RelPath)
+		idx.add(note)
+		changed = true
+	}
+	for relPath := range idx.Docs {
+		if !seen[relPath] {
+			idx.remove(relPath)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// ftsHit is a scored note.
+type ftsHit struct {
+	path  string
+	score float64
+}
+
+// search scores every note containing at least one query term with BM25F,
+// optionally restricted to paths under prefix, best first.
+func (idx *ftsIndex) search(terms []string, prefix string) []ftsHit {
+	n := float64(len(idx.Docs))
+	if n == 0 || len(terms) == 0 {
+		return nil
+	}
+	var avgLen [ftsFieldCount]float64
+	for field, total := range idx.TotalLen {
+		avgLen[field] = math.Max(float64(total)/n, 1)
+	}
+
+	scores := make(map[string]float64)
+	seenTerm := make(map[string]bool)
+	for _, term := range terms {
+		if seenTerm[term] {
+			continue
+		}
+		seenTerm[term] = true
+
+		postings := idx.Postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		for path, tf := range postings {
+			if !pathHasPrefix(path, prefix) {
+				continue
+			}
+			doc := idx.Docs[path]
+			termScore := 0.0
+			for field := 0; field < ftsFieldCount; field++ {
+				if tf[field] == 0 {
+					continue
+				}
+				f := float64(tf[field])
+				norm := 1 - bm25B + bm25B*float64(doc.Lengths[field])/avgLen[field]
+				termScore += ftsBoosts[field] * f * (bm25K1 + 1) / (f + bm25K1*norm)
+			}
+			scores[path] += idf * termScore
+		}
+	}
+
+	hits := make([]ftsHit, 0, len(scores))
+	for path, score := range scores {
+		hits = append(hits, ftsHit{path: path, score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score == hits[j].score {
+			return hits[i].path < hits[j].path
+		}
+		return hits[i].score > hits[j].score
+	})
+	return hits
+}
+
+// pathHasPrefix reports whether a vault-relative path lies under dir.
+func pathHasPrefix(path, dir string) bool {
+	if dir == "" || dir == "." {
+		return true
+	}
+	return path == dir || strings.HasPrefix(path, dir+string(os.PathSeparator))
+}
+
+// searchFullText syncs the vault's full-text index with the note index,
+// persisting it when anything changed, and runs a ranked query against it.
+// It also reports how many notes are indexed. Saving is best-effort: a vault
+// that cannot be written is still searched with the in-memory index.
+func (v *Vault) searchFullText(terms []string, prefix string) (hits []ftsHit, indexed int, err error) {
+	root := v.GetPath()
+	notes, err := v.indexedNotes(root)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	v.fts.mu.Lock()
+	defer v.fts.mu.Unlock()
+	if v.fts.idx == nil || v.fts.root != root {
+		v.fts.root = root
+		v.fts.idx = loadFTSIndex(root)
+	}
+	if v.fts.idx.sync(notes) || v.fts.unsaved {
+		v.fts.unsaved = saveFTSIndex(root, v.fts.idx) != nil
+	}
+	return v.fts.idx.search(terms, prefix), len(v.fts.idx.Docs), nil
+}
+
+// snippetFor picks the line of a note with the most distinct query terms and
+// returns it with matches wrapped in ** for highlighting.
+func snippetFor(note *indexedNote, terms map[string]bool, maxLen int) (line int, snippet string) {
+	bestCount := 0
+	bestLine := -1
+	for i, text := range note.Lines {
+		found := make(map[string]bool)
+		for _, tok := range scanTokens(text) {
+			if term := indexTerm(tok.word); terms[term] {
+				found[term] = true
+			}
+		}
+		if len(found) > bestCount {
+			bestCount = len(found)
+			bestLine = i
+		}
+	}
+	if bestLine < 0 {
+		return 0, ""
+	}
+	return bestLine + 1, highlightTerms(strings.TrimSpace(note.Lines[bestLine]), terms, maxLen)
+}
+
+// highlightTerms wraps words whose stems are in terms with **, trimming the
+// text to about maxLen bytes around the first match.
+func highlightTerms(text string, terms map[string]bool, maxLen int) string {
+	tokens := scanTokens(text)
+	var matched []textToken
+	for _, tok := range tokens {
+		if terms[indexTerm(tok.word)] {
+			matched = append(matched, tok)
+		}
+	}
+	if len(matched) == 0 {
+		return truncate(text, maxLen)
+	}
+
+	start, end := 0, len(text)
+	if maxLen > 0 && len(text) > maxLen {
+		start = matched[0].start - maxLen/4
+		if start < 0 {
+			start = 0
+		}
+		end = start + maxLen
+		if end > len(text) {
+			end = len(text)
+		}
+		// Avoid cutting through a word or multi-byte rune.
+		for start > 0 && (!isTokenBoundary(tokens, start) || !utf8.RuneStart(text[start])) {
+			start--
+		}
+		for end < len(text) && (!isTokenBoundary(tokens, end) || !utf8.RuneStart(text[end])) {
+			end++
+		}
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("...")
+	}
+	pos := start
+	for _, tok := range matched {
+		if tok.start < start || tok.end > end {
+			continue
+		}
+		sb.WriteString(text[pos:tok.start])
+		sb.WriteString("**")
+		sb.WriteString(text[tok.start:tok.end])
+		sb.WriteString("**")
+		pos = tok.end
+	}
+	sb.WriteString(text[pos:end])
+	if end < len(text) {
+		sb.WriteString("...")
+	}
+	return sb.String()
+}
+
+// isTokenBoundary reports whether byte offset i does not fall inside a token.
+func isTokenBoundary(tokens []textToken, i int) bool {
+	for _, tok := range tokens {
+		if i > tok.start && i < tok.end {
+			return false
+		}
+	}
+	return true
+}
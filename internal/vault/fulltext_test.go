@@ -0,0 +1,207 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestStemWord(t *testing.T) {
+	tests := map[string]string{
+		"caresses":       "caress",
+		"ponies":         "poni",
+		"relational":     "relat",
+		"generalization": "gener",
+		"running":        "run",
+		"hopping":        "hop",
+		"meetings":       "meet",
+		"searching":      "search",
+		"happy":          "happi",
+		"goodness":       "good",
+		"adjustment":     "adjust",
+		"café":           "café",
+	}
+	for word, want := range tests {
+		if got := stemWord(word); got != want {
+			t.Errorf("stemWord(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestTokenizeDropsStopWords(t *testing.T) {
+	got := strings.Join(tokenize("The Meetings of the Planning-Committee"), " ")
+	if got != "meet plan committe" {
+		t.Errorf("unexpected tokens: %q", got)
+	}
+}
+
+func TestHighlightTerms(t *testing.T) {
+	terms := map[string]bool{"meet": true}
+	got := highlightTerms("Notes from the weekly meeting.", terms, 0)
+	if got != "Notes from the weekly **meeting**." {
+		t.Errorf("unexpected highlight: %q", got)
+	}
+
+	long := strings.Repeat("filler words ", 30) + "the meeting happened " + strings.Repeat("more text ", 30)
+	got = highlightTerms(long, terms, 60)
+	if !strings.Contains(got, "**meeting**") || !strings.HasPrefix(got, "...") || !strings.HasSuffix(got, "...") {
+		t.Errorf("expected trimmed snippet around match, got %q", got)
+	}
+}
+
+func rankedResults(t *testing.T, result *mcp.CallToolResult) []RankedResult {
+	t.Helper()
+	var envelope struct {
+		Data struct {
+			Results []RankedResult `json:"results"`
+		} `json:"data"`
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if err := json.Unmarshal([]byte(text), &envelope); err != nil {
+		t.Fatalf("invalid compact response: %v\n%s", err, text)
+	}
+	return envelope.Data.Results
+}
+
+func TestSearchRankedBoostsTitleAndHeadings(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "Gardening.md", "# Tomatoes\n\nWater them daily.\n")
+	writeTestFile(t, dir, "journal.md", "Today I thought briefly about gardening while cooking dinner and reading a long book about history.\n")
+	writeTestFile(t, dir, "unrelated.md", "Nothing to see here.\n")
+	ctx := context.Background()
+
+	result, _, err := v.SearchRankedHandler(ctx, nil, SearchRankedArgs{Query: "gardens"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := rankedResults(t, result)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 ranked results, got %+v", results)
+	}
+	if results[0].File != "Gardening.md" {
+		t.Errorf("expected title match to rank first, got %+v", results)
+	}
+	if results[1].Snippet == "" || !strings.Contains(results[1].Snippet, "**gardening**") {
+		t.Errorf("expected highlighted snippet, got %q", results[1].Snippet)
+	}
+
+	result, _, err = v.SearchRankedHandler(ctx, nil, SearchRankedArgs{Query: "tomato"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results := rankedResults(t, result); len(results) != 1 || results[0].File != "Gardening.md" {
+		t.Errorf("expected heading match, got %+v", results)
+	}
+}
+
+func TestSearchRankedPersistsAndUpdatesIncrementally(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "a.md", "alpha content")
+	writeTestFile(t, dir, "sub/b.md", "bravo content")
+	ctx := context.Background()
+
+	if _, _, err := v.SearchRankedHandler(ctx, nil, SearchRankedArgs{Query: "content"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(ftsIndexPath(dir)); err != nil {
+		t.Fatalf("expected persisted index: %v", err)
+	}
+
+	// A fresh vault instance loads the persisted index and picks up edits.
+	writeTestFile(t, dir, "a.md", "charlie content")
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "a.md"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "sub", "b.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh := New(dir)
+	loaded := loadFTSIndex(dir)
+	if len(loaded.Docs) != 2 {
+		t.Fatalf("expected 2 persisted docs, got %d", len(loaded.Docs))
+	}
+
+	result, _, err := fresh.SearchRankedHandler(ctx, nil, SearchRankedArgs{Query: "charlie"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results := rankedResults(t, result); len(results) != 1 || results[0].File != "a.md" {
+		t.Errorf("expected edited note to be re-indexed, got %+v", results)
+	}
+	result, _, err = fresh.SearchRankedHandler(ctx, nil, SearchRankedArgs{Query: "bravo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results := rankedResults(t, result); len(results) != 0 {
+		t.Errorf("expected deleted note to be dropped, got %+v", results)
+	}
+}
+
+func TestSearchRankedWithUnwritableIndex(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "a.md", "alpha content")
+	indexDir := filepath.Join(dir, ".obx", "index")
+	// A directory in place of the temp file stops the save even as root
+	if err := os.MkdirAll(ftsIndexPath(dir)+".tmp", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(indexDir, 0o500); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(indexDir, 0o755) })
+
+	result, _, err := v.SearchRankedHandler(context.Background(), nil, SearchRankedArgs{Query: "alpha"})
+	if err != nil {
+		t.Fatalf("search should not fail when the index cannot be saved: %v", err)
+	}
+	if results := rankedResults(t, result); len(results) != 1 || results[0].File != "a.md" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if _, err := os.Stat(ftsIndexPath(dir)); !os.IsNotExist(err) {
+		t.Fatalf("expected no saved index, got %v", err)
+	}
+
+	// The save is retried once the index can be written
+	if err := os.Chmod(indexDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(ftsIndexPath(dir) + ".tmp"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := v.SearchRankedHandler(context.Background(), nil, SearchRankedArgs{Query: "alpha"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(ftsIndexPath(dir)); err != nil {
+		t.Errorf("expected the index to be saved on retry: %v", err)
+	}
+}
+
+func TestSearchRankedDirectoryAndPagination(t *testing.T) {
+	v, dir := setupTestVault(t)
+	for _, name := range []string{"work/a.md", "work/b.md", "work/c.md", "home/d.md"} {
+		writeTestFile(t, dir, name, "project status")
+	}
+	ctx := context.Background()
+
+	result, _, err := v.SearchRankedHandler(ctx, nil, SearchRankedArgs{Query: "project", Directory: "work", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, `"offset":2`) {
+		t.Errorf("expected next page hint, got %s", text)
+	}
+	for _, r := range rankedResults(t, result) {
+		if !strings.HasPrefix(r.File, "work"+string(os.PathSeparator)) {
+			t.Errorf("result outside directory: %s", r.File)
+		}
+	}
+}
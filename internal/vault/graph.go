@@ -24,7 +24,7 @@ func (v *Vault) ForwardLinksHandler(ctx context.Context, req *mcp.CallToolReques
 		return nil, nil, fmt.Errorf("path must be within vault")
 	}
 
-	content, err := os.ReadFile(fullPath)
+	note, err := v.indexedNote(notePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil, fmt.Errorf("note not found: %s", notePath)
@@ -32,7 +32,7 @@ func (v *Vault) ForwardLinksHandler(ctx context.Context, req *mcp.CallToolReques
 		return nil, nil, fmt.Errorf("failed to read note: %v", err)
 	}
 
-	links := ExtractWikilinks(string(content))
+	links := note.Links
 
 	if len(links) == 0 {
 		return &mcp.CallToolResult{
@@ -42,32 +42,67 @@ func (v *Vault) ForwardLinksHandler(ctx context.Context, req *mcp.CallToolReques
 		}, nil, nil
 	}
 
-	// Check which links exist
-	var existing, broken []string
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve links: %v", err)
+	}
+
+	embeds := make(map[string]bool, len(note.Embeds))
+	for _, embed := range note.Embeds {
+		embeds[embed] = true
+	}
+
+	// Check which links exist. Embedded images, PDFs and other non-note
+	// files are listed separately as attachments.
+	var existing, broken, attachments []linkResolution
 	for _, link := range links {
-		if v.noteExists(link) {
-			existing = append(existing, link)
-		} else {
-			broken = append(broken, link)
+		res := resolver.resolve(link, note.RelPath)
+		switch {
+		case embeds[link] && isAttachmentLink(res):
+			attachments = append(attachments, res)
+		case res.broken():
+			broken = append(broken, res)
+		default:
+			existing = append(existing, res)
 		}
 	}
 
 	var sb strings.Builder
 	fmt.Fprintf(&sb, "# Forward Links from %s\n\n", notePath)
-	fmt.Fprintf(&sb, "Total: %d links (%d existing, %d broken)\n\n", len(links), len(existing), len(broken))
+	fmt.Fprintf(&sb, "Total: %d links (%d existing, %d broken", len(links), len(existing), len(broken))
+	if len(attachments) > 0 {
+		fmt.Fprintf(&sb, ", %d attachments", len(attachments))
+	}
+	sb.WriteString(")\n\n")
 
 	if len(existing) > 0 {
 		sb.WriteString("## Existing Notes\n")
-		for _, link := range existing {
-			fmt.Fprintf(&sb, "- [[%s]]\n", link)
+		for _, res := range existing {
+			fmt.Fprintf(&sb, "- %s[[%s]] → %s", embedMark(embeds[res.Raw]), res.Raw, res.Target)
+			if res.ByAlias {
+				sb.WriteString(" (alias)")
+			}
+			sb.WriteString("\n")
 		}
 		sb.WriteString("\n")
 	}
 
 	if len(broken) > 0 {
-		sb.WriteString("## Broken Links (no matching note)\n")
-		for _, link := range broken {
-			fmt.Fprintf(&sb, "- [[%s]] ⚠️\n", link)
+		sb.WriteString("## Broken Links (no matching note or anchor)\n")
+		for _, res := range broken {
+			fmt.Fprintf(&sb, "- %s[[%s]] ⚠️%s\n", embedMark(embeds[res.Raw]), res.Raw, res.anchorProblem())
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(attachments) > 0 {
+		sb.WriteString("## Attachments\n")
+		for _, res := range attachments {
+			if res.Target == "" {
+				fmt.Fprintf(&sb, "- ![[%s]] ⚠️ (missing)\n", res.Raw)
+			} else {
+				fmt.Fprintf(&sb, "- ![[%s]] → %s\n", res.Raw, res.Target)
+			}
 		}
 	}
 
@@ -78,73 +113,90 @@ func (v *Vault) ForwardLinksHandler(ctx context.Context, req *mcp.CallToolReques
 	}, nil, nil
 }
 
+// isAttachmentLink reports whether a link points at a file other than a
+// note, going by the resolved file or, if missing, the written extension.
+func isAttachmentLink(res linkResolution) bool {
+	target := res.Target
+	if target == "" {
+		target = res.Ref.Path
+	}
+	ext := strings.ToLower(filepath.Ext(target))
+	return ext != "" && ext != ".md"
+}
+
+func embedMark(embed bool) string {
+	if embed {
+		return "!"
+	}
+	return ""
+}
+
 // noteLinks represents a note and its link relationships
 type noteLinks struct {
 	path     string
-	outgoing []string
+	outgoing []string   // link targets as written, embeds included
+	edges    []linkEdge // resolved links to other notes in the graph
 	incoming int
 }
 
+// linkEdge is a resolved link between two notes in the graph. Embeds are
+// kept as a distinct kind of edge.
+type linkEdge struct {
+	target string // graph key of the linked note
+	embed  bool
+}
+
 // linkGraph represents the full vault link structure
 type linkGraph struct {
 	notes map[string]*noteLinks
 }
 
-// buildLinkGraph scans the vault and builds the link graph
+// buildLinkGraph scans the vault and builds the link graph. Links are
+// resolved against the whole vault; only targets under searchPath count.
 func (v *Vault) buildLinkGraph(searchPath string) (*linkGraph, error) {
 	graph := &linkGraph{notes: make(map[string]*noteLinks)}
 
 	// Collect all notes and their outgoing links
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		relPath, _ := filepath.Rel(v.GetPath(), path)
-		noteName := strings.TrimSuffix(relPath, ".md")
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, err
+	}
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, err
+	}
 
+	embeds := make(map[string]map[string]bool, len(notes))
+	for _, note := range notes {
+		noteName := strings.TrimSuffix(note.RelPath, ".md")
 		graph.notes[noteName] = &noteLinks{
-			path:     relPath,
-			outgoing: ExtractWikilinks(string(content)),
+			path:     note.RelPath,
+			outgoing: note.Links,
+		}
+		embeds[noteName] = make(map[string]bool, len(note.Embeds))
+		for _, embed := range note.Embeds {
+			embeds[noteName][embed] = true
 		}
-		return nil
-	})
-
-	if err != nil {
-		return nil, err
 	}
 
-	// Count incoming links
-	for _, note := range graph.notes {
+	// Resolve edges and count incoming links
+	for name, note := range graph.notes {
 		for _, link := range note.outgoing {
-			graph.incrementIncoming(link)
+			res := resolver.resolve(link, note.path)
+			if res.Target == "" || res.Target == note.path {
+				continue
+			}
+			targetName := strings.TrimSuffix(res.Target, ".md")
+			if target, exists := graph.notes[targetName]; exists {
+				target.incoming++
+				note.edges = append(note.edges, linkEdge{target: targetName, embed: embeds[name][link]})
+			}
 		}
 	}
 
 	return graph, nil
 }
 
-// incrementIncoming increments the incoming count for a target note
-func (g *linkGraph) incrementIncoming(link string) {
-	target := normalizeNoteName(link)
-	if targetNote, exists := g.notes[target]; exists {
-		targetNote.incoming++
-		return
-	}
-	// Try basename match
-	for name, n := range g.notes {
-		if filepath.Base(name) == link || name == link {
-			n.incoming++
-			return
-		}
-	}
-}
-
 // orphanResult holds categorized orphan notes
 type orphanResult struct {
 	trueOrphans []string // No incoming AND no outgoing
@@ -245,44 +297,27 @@ func (v *Vault) OrphanNotesHandler(ctx context.Context, req *mcp.CallToolRequest
 	}, nil, nil
 }
 
-// brokenLink represents a wikilink that doesn't resolve
+// brokenLink represents a link that doesn't resolve
 type brokenLink struct {
 	source string
-	target string
+	text   string // the link as written
 	line   int
+	reason string
 }
 
-// buildExistingNotesSet creates a set of all existing note names
-func (v *Vault) buildExistingNotesSet() (map[string]bool, error) {
-	existing := make(map[string]bool)
-	err := filepath.Walk(v.GetPath(), func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-		relPath, _ := filepath.Rel(v.GetPath(), path)
-		noteName := strings.TrimSuffix(relPath, ".md")
-		existing[noteName] = true
-		existing[filepath.Base(noteName)] = true
-		return nil
-	})
-	return existing, err
-}
-
-// findBrokenLinksInNote finds broken links in a single note
-func findBrokenLinksInNote(relPath, content string, existing map[string]bool) []brokenLink {
+// findBrokenLinksInNote finds links in a single note that point at a missing
+// note, heading or block
+func findBrokenLinksInNote(relPath, content string, resolver *linkResolver) []brokenLink {
 	var broken []brokenLink
 	lines := strings.Split(content, "\n")
 
 	for i, line := range lines {
-		matches := wikilinkRegex.FindAllStringSubmatch(line, -1)
-		for _, match := range matches {
-			link := strings.TrimSpace(match[1])
-			if link == "" || strings.HasPrefix(link, "http") || strings.Contains(link, "#") {
+		for _, link := range parseNoteLinks(line) {
+			if strings.HasPrefix(link.Target, "http") {
 				continue
 			}
-			normalized := normalizeNoteName(link)
-			if !existing[normalized] && !existing[link] {
-				broken = append(broken, brokenLink{source: relPath, target: link, line: i + 1})
+			if res := resolver.resolve(link.Target, relPath); res.broken() {
+				broken = append(broken, brokenLink{source: relPath, text: line[link.Start:link.End], line: i + 1, reason: res.anchorProblem()})
 			}
 		}
 	}
@@ -292,9 +327,13 @@ func findBrokenLinksInNote(relPath, content string, existing map[string]bool) []
 // formatBrokenLinks formats broken links as markdown
 func formatBrokenLinks(broken []brokenLink) string {
 	if len(broken) == 0 {
-		return "No broken links found! All wikilinks resolve to existing notes."
+		return "No broken links found! All links resolve to existing notes and anchors."
 	}
+	return formatLinkGroups("Broken Links", broken)
+}
 
+// formatLinkGroups lists links under a title, grouped by the note they are in
+func formatLinkGroups(title string, broken []brokenLink) string {
 	// Group by source
 	bySource := make(map[string][]brokenLink)
 	for _, bl := range broken {
@@ -308,13 +347,13 @@ func formatBrokenLinks(broken []brokenLink) string {
 	sort.Strings(sources)
 
 	var sb strings.Builder
-	fmt.Fprintf(&sb, "# Broken Links (%d total in %d files)\n\n", len(broken), len(sources))
+	fmt.Fprintf(&sb, "# %s (%d total in %d files)\n\n", title, len(broken), len(sources))
 
 	for _, source := range sources {
 		links := bySource[source]
 		fmt.Fprintf(&sb, "## %s (%d broken)\n", source, len(links))
 		for _, bl := range links {
-			fmt.Fprintf(&sb, "- L%d: [[%s]]\n", bl.line, bl.target)
+			fmt.Fprintf(&sb, "- L%d: %s%s\n", bl.line, bl.text, bl.reason)
 		}
 		sb.WriteString("\n")
 	}
@@ -322,7 +361,7 @@ func formatBrokenLinks(broken []brokenLink) string {
 	return sb.String()
 }
 
-// BrokenLinksHandler finds wikilinks pointing to non-existent notes
+// BrokenLinksHandler finds links pointing to non-existent notes
 func (v *Vault) BrokenLinksHandler(ctx context.Context, req *mcp.CallToolRequest, args BrokenLinksArgs) (*mcp.CallToolResult, any, error) {
 	dir := args.Directory
 
@@ -334,29 +373,24 @@ func (v *Vault) BrokenLinksHandler(ctx context.Context, req *mcp.CallToolRequest
 		return nil, nil, fmt.Errorf("search path must be within vault")
 	}
 
-	existing, err := v.buildExistingNotesSet()
+	resolver, err := v.newLinkResolver()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to scan vault: %v", err)
 	}
 
-	var broken []brokenLink
-	err = filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-		relPath, _ := filepath.Rel(v.GetPath(), path)
-		broken = append(broken, findBrokenLinksInNote(relPath, string(content), existing)...)
-		return nil
-	})
-
+	notes, err := v.indexedNotes(searchPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to scan for broken links: %v", err)
 	}
 
+	var broken []brokenLink
+	for _, note := range notes {
+		if len(note.Links) == 0 {
+			continue
+		}
+		broken = append(broken, findBrokenLinksInNote(note.RelPath, note.Content, resolver)...)
+	}
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: formatBrokenLinks(broken)},
@@ -364,28 +398,6 @@ func (v *Vault) BrokenLinksHandler(ctx context.Context, req *mcp.CallToolRequest
 	}, nil, nil
 }
 
-// noteExists checks if a note exists (handles path normalization)
-func (v *Vault) noteExists(link string) bool {
-	fullPath := filepath.Join(v.GetPath(), link+".md")
-	if _, err := os.Stat(fullPath); err == nil {
-		return true
-	}
-
-	var found bool
-	_ = filepath.Walk(v.GetPath(), func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-		name := strings.TrimSuffix(filepath.Base(path), ".md")
-		if name == link {
-			found = true
-			return filepath.SkipAll
-		}
-		return nil
-	})
-	return found
-}
-
 // normalizeNoteName normalizes a note name for comparison
 func normalizeNoteName(link string) string {
 	link = strings.TrimSpace(link)
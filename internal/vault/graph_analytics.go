@@ -0,0 +1,712 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultGraphLimit = 20
+	maxGroupNotes     = 50
+	pageRankDamping   = 0.85
+)
+
+// analyticsGraph is the link graph as adjacency lists over node indexes, for
+// the graph algorithms. Repeated links collapse into one edge, and adj holds
+// each note's neighbors in either direction.
+type analyticsGraph struct {
+	paths []string // note paths, sorted
+	index map[string]int
+	out   [][]int // distinct link and embed targets
+	in    [][]int // distinct link and embed sources
+	adj   [][]int
+}
+
+func newAnalyticsGraph(links *linkGraph) *analyticsGraph {
+	g := &analyticsGraph{index: make(map[string]int, len(links.notes))}
+	keys := make([]string, 0, len(links.notes))
+	for key := range links.notes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		g.index[key] = i
+		g.paths = append(g.paths, links.notes[key].path)
+	}
+
+	n := len(keys)
+	g.out, g.in, g.adj = make([][]int, n), make([][]int, n), make([][]int, n)
+	for i, key := range keys {
+		for _, e := range links.notes[key].edges {
+			j := g.index[e.target]
+			if !slices.Contains(g.out[i], j) {
+				g.out[i] = append(g.out[i], j)
+				g.in[j] = append(g.in[j], i)
+			}
+		}
+	}
+	for i := range n {
+		g.adj[i] = append(append([]int(nil), g.out[i]...), g.in[i]...)
+		slices.Sort(g.adj[i])
+		g.adj[i] = slices.Compact(g.adj[i])
+		slices.Sort(g.out[i])
+	}
+	return g
+}
+
+// loadAnalyticsGraph builds the analytics graph of the notes under dir.
+func (v *Vault) loadAnalyticsGraph(dir string) (*analyticsGraph, error) {
+	searchPath := v.GetPath()
+	if dir != "" {
+		searchPath = filepath.Join(v.GetPath(), dir)
+	}
+	if !v.isPathSafe(searchPath) {
+		return nil, fmt.Errorf("search path must be within vault")
+	}
+	links, err := v.buildLinkGraph(searchPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan vault: %v", err)
+	}
+	return newAnalyticsGraph(links), nil
+}
+
+// node finds a note in the graph the way a link to it would resolve.
+func (g *analyticsGraph) node(resolver *linkResolver, note string) (int, error) {
+	path, _ := resolver.resolvePath(note, "")
+	if path == "" {
+		path = note
+	}
+	if i, ok := g.index[strings.TrimSuffix(path, ".md")]; ok {
+		return i, nil
+	}
+	return 0, fmt.Errorf("note not found in graph: %s", note)
+}
+
+// pageRank scores notes by the links pointing at them, weighted by the
+// score of the linking note. Notes without links share their rank evenly.
+func (g *analyticsGraph) pageRank() []float64 {
+	n := len(g.paths)
+	rank := make([]float64, n)
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+	for range 100 {
+		next := make([]float64, n)
+		dangling := 0.0
+		for i, targets := range g.out {
+			if len(targets) == 0 {
+				dangling += rank[i]
+				continue
+			}
+			share := rank[i] / float64(len(targets))
+			for _, j := range targets {
+				next[j] += share
+			}
+		}
+		base := (1-pageRankDamping)/float64(n) + pageRankDamping*dangling/float64(n)
+		diff := 0.0
+		for i := range next {
+			next[i] = base + pageRankDamping*next[i]
+			diff += math.Abs(next[i] - rank[i])
+		}
+		rank = next
+		if diff < 1e-9 {
+			break
+		}
+	}
+	return rank
+}
+
+// betweenness scores notes by the share of shortest paths between other
+// notes that pass through them, ignoring link direction (Brandes'
+// algorithm, normalized to 0-1).
+func (g *analyticsGraph) betweenness() []float64 {
+	n := len(g.paths)
+	scores := make([]float64, n)
+	sigma := make([]float64, n)
+	dist := make([]int, n)
+	delta := make([]float64, n)
+	preds := make([][]int, n)
+
+	for s := range n {
+		for i := range n {
+			sigma[i], dist[i], delta[i], preds[i] = 0, -1, 0, preds[i][:0]
+		}
+		sigma[s], dist[s] = 1, 0
+		queue := []int{s}
+		var visited []int
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			visited = append(visited, u)
+			for _, w := range g.adj[u] {
+				if dist[w] < 0 {
+					dist[w] = dist[u] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[u]+1 {
+					sigma[w] += sigma[u]
+					preds[w] = append(preds[w], u)
+				}
+			}
+		}
+		for i := len(visited) - 1; i > 0; i-- {
+			w := visited[i]
+			for _, u := range preds[w] {
+				delta[u] += sigma[u] / sigma[w] * (1 + delta[w])
+			}
+			scores[w] += delta[w]
+		}
+	}
+
+	// Every pair is counted from both ends, which the undirected
+	// normalization of 2/((n-1)(n-2)) cancels out.
+	if n > 2 {
+		for i := range scores {
+			scores[i] /= float64((n - 1) * (n - 2))
+		}
+	}
+	return scores
+}
+
+// components labels each note with its connected component, ignoring link
+// direction.
+func (g *analyticsGraph) components() []int {
+	labels := make([]int, len(g.paths))
+	for i := range labels {
+		labels[i] = -1
+	}
+	for s := range labels {
+		if labels[s] >= 0 {
+			continue
+		}
+		labels[s] = s
+		queue := []int{s}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, w := range g.adj[u] {
+				if labels[w] < 0 {
+					labels[w] = s
+					queue = append(queue, w)
+				}
+			}
+		}
+	}
+	return labels
+}
+
+// communities labels each note with its community using the Louvain
+// method: notes move to the neighboring community that most improves
+// modularity, then each community is merged into a single node and the
+// process repeats until nothing moves. Link direction is ignored.
+func (g *analyticsGraph) communities() []int {
+	members := make([]int, len(g.paths))
+	weights := make([]map[int]float64, len(g.paths))
+	for i, neighbors := range g.adj {
+		members[i] = i
+		weights[i] = make(map[int]float64, len(neighbors))
+		for _, j := range neighbors {
+			weights[i][j] = 1
+		}
+	}
+
+	for {
+		labels, moved := louvainPass(weights)
+		if !moved {
+			return members
+		}
+		ids := make(map[int]int)
+		for i, label := range labels {
+			if _, ok := ids[label]; !ok {
+				ids[label] = len(ids)
+			}
+			labels[i] = ids[label]
+		}
+		for i := range members {
+			members[i] = labels[members[i]]
+		}
+		merged := make([]map[int]float64, len(ids))
+		for i := range merged {
+			merged[i] = make(map[int]float64)
+		}
+		for i, row := range weights {
+			for j, w := range row {
+				merged[labels[i]][labels[j]] += w
+			}
+		}
+		weights = merged
+	}
+}
+
+// louvainPass moves each node of a weighted graph into the neighboring
+// community with the largest modularity gain until no move helps. Nodes are
+// visited in order and ties go to the lowest community, so the result is
+// deterministic.
+func louvainPass(weights []map[int]float64) (labels []int, moved bool) {
+	n := len(weights)
+	degree := make([]float64, n)
+	totals := make([]float64, n) // summed degree of each community
+	labels = make([]int, n)
+	total := 0.0
+	for i, row := range weights {
+		for _, w := range row {
+			degree[i] += w
+		}
+		labels[i], totals[i] = i, degree[i]
+		total += degree[i]
+	}
+	if total == 0 {
+		return labels, false
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for i := range n {
+			links := make(map[int]float64)
+			for j, w := range weights[i] {
+				if j != i {
+					links[labels[j]] += w
+				}
+			}
+			current := labels[i]
+			totals[current] -= degree[i]
+			best, bestGain := current, links[current]-totals[current]*degree[i]/total
+			candidates := make([]int, 0, len(links))
+			for c := range links {
+				candidates = append(candidates, c)
+			}
+			slices.Sort(candidates)
+			for _, c := range candidates {
+				if gain := links[c] - totals[c]*degree[i]/total; gain > bestGain+1e-12 {
+					best, bestGain = c, gain
+				}
+			}
+			totals[best] += degree[i]
+			if best != current {
+				labels[i] = best
+				changed, moved = true, true
+			}
+		}
+	}
+	return labels, moved
+}
+
+// modularity measures how much more densely notes link within their groups
+// than a random graph with the same degrees would, from -0.5 to 1.
+func (g *analyticsGraph) modularity(labels []int) float64 {
+	edges := 0
+	for _, neighbors := range g.adj {
+		edges += len(neighbors)
+	}
+	if edges == 0 {
+		return 0
+	}
+	// Both ends of every edge are counted, so edges is twice the edge count.
+	internal := make(map[int]float64)
+	degree := make(map[int]float64)
+	for i, neighbors := range g.adj {
+		degree[labels[i]] += float64(len(neighbors))
+		for _, j := range neighbors {
+			if labels[j] == labels[i] {
+				internal[labels[i]]++
+			}
+		}
+	}
+	q := 0.0
+	for label, d := range degree {
+		q += internal[label]/float64(edges) - math.Pow(d/float64(edges), 2)
+	}
+	return q
+}
+
+// noteGroup is a connected component or community.
+type noteGroup struct {
+	Size  int      `json:"size"`
+	Notes []string `json:"notes"`
+}
+
+// groupNotes collects the notes sharing a label, largest group first.
+// Notes alone in their group are only counted.
+func (g *analyticsGraph) groupNotes(labels []int) (groups []noteGroup, isolated int) {
+	byLabel := make(map[int][]string)
+	for i, label := range labels {
+		byLabel[label] = append(byLabel[label], g.paths[i])
+	}
+	for _, notes := range byLabel {
+		if len(notes) == 1 {
+			isolated++
+			continue
+		}
+		groups = append(groups, noteGroup{Size: len(notes), Notes: notes})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Size != groups[j].Size {
+			return groups[i].Size > groups[j].Size
+		}
+		return groups[i].Notes[0] < groups[j].Notes[0]
+	})
+	return groups, isolated
+}
+
+// shortestPath returns the notes on a shortest path from one note to
+// another, following links in either direction unless directed is set.
+// It returns nil if there is no path.
+func (g *analyticsGraph) shortestPath(from, to int, directed bool) []int {
+	next := g.adj
+	if directed {
+		next = g.out
+	}
+	parent := make([]int, len(g.paths))
+	for i := range parent {
+		parent[i] = -1
+	}
+	parent[from] = from
+	queue := []int{from}
+	for len(queue) > 0 && parent[to] < 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, w := range next[u] {
+			if parent[w] < 0 {
+				parent[w] = u
+				queue = append(queue, w)
+			}
+		}
+	}
+	if parent[to] < 0 {
+		return nil
+	}
+	path := []int{to}
+	for path[0] != from {
+		path = slices.Insert(path, 0, parent[path[0]])
+	}
+	return path
+}
+
+// neighborhood returns the hop distance of every note within depth hops of
+// a note, ignoring link direction. The note itself is left out.
+func (g *analyticsGraph) neighborhood(from, depth int) map[int]int {
+	hops := map[int]int{from: 0}
+	frontier := []int{from}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		var next []int
+		for _, u := range frontier {
+			for _, w := range g.adj[u] {
+				if _, seen := hops[w]; !seen {
+					hops[w] = d
+					next = append(next, w)
+				}
+			}
+		}
+		frontier = next
+	}
+	delete(hops, from)
+	return hops
+}
+
+// linkDirection describes the links between two adjacent notes.
+func (g *analyticsGraph) linkDirection(from, to int) string {
+	forward := slices.Contains(g.out[from], to)
+	backward := slices.Contains(g.out[to], from)
+	switch {
+	case forward && backward:
+		return "both"
+	case forward:
+		return "forward"
+	default:
+		return "backward"
+	}
+}
+
+// noteScore is a note ranked by a centrality measure.
+type noteScore struct {
+	Path     string  `json:"path"`
+	Score    float64 `json:"score"`
+	Incoming int     `json:"incoming"`
+	Outgoing int     `json:"outgoing"`
+}
+
+// GraphCentralityHandler ranks notes by PageRank or betweenness centrality
+func (v *Vault) GraphCentralityHandler(ctx context.Context, req *mcp.CallToolRequest, args GraphCentralityArgs) (*mcp.CallToolResult, any, error) {
+	g, err := v.loadAnalyticsGraph(args.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metric := strings.ToLower(args.Metric)
+	var scores []float64
+	switch metric {
+	case "", "pagerank":
+		metric = "pagerank"
+		scores = g.pageRank()
+	case "betweenness":
+		scores = g.betweenness()
+	default:
+		return nil, nil, fmt.Errorf("unknown metric: %s (use pagerank or betweenness)", args.Metric)
+	}
+
+	ranked := make([]noteScore, len(scores))
+	for i, score := range scores {
+		ranked[i] = noteScore{Path: g.paths[i], Score: math.Round(score*1e6) / 1e6, Incoming: len(g.in[i]), Outgoing: len(g.out[i])}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultGraphLimit
+	}
+	truncated := len(ranked) > limit
+	if truncated {
+		ranked = ranked[:limit]
+	}
+
+	if !isDetailedMode(args.Mode) {
+		return compactResult(fmt.Sprintf("Top %d of %d notes by %s", len(ranked), len(g.paths), metric), truncated, map[string]any{
+			"metric": metric,
+			"notes":  len(g.paths),
+			"ranked": ranked,
+		}, nil)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Top Notes by %s (%d of %d)\n\n", metric, len(ranked), len(g.paths))
+	sb.WriteString("| # | Note | Score | In | Out |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+	for i, r := range ranked {
+		fmt.Fprintf(&sb, "| %d | [[%s]] | %.6f | %d | %d |\n", i+1, strings.TrimSuffix(r.Path, ".md"), r.Score, r.Incoming, r.Outgoing)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: sb.String()},
+		},
+	}, nil, nil
+}
+
+// GraphComponentsHandler lists groups of notes connected by links
+func (v *Vault) GraphComponentsHandler(ctx context.Context, req *mcp.CallToolRequest, args GraphClustersArgs) (*mcp.CallToolResult, any, error) {
+	g, err := v.loadAnalyticsGraph(args.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+	groups, isolated := g.groupNotes(g.components())
+	return clusterResult("connected components", groups, isolated, args, nil)
+}
+
+// GraphCommunitiesHandler detects communities of densely linked notes
+func (v *Vault) GraphCommunitiesHandler(ctx context.Context, req *mcp.CallToolRequest, args GraphClustersArgs) (*mcp.CallToolResult, any, error) {
+	g, err := v.loadAnalyticsGraph(args.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+	labels := g.communities()
+	groups, isolated := g.groupNotes(labels)
+	modularity := math.Round(g.modularity(labels)*1000) / 1000
+	return clusterResult("communities", groups, isolated, args, map[string]any{"modularity": modularity})
+}
+
+// clusterResult formats components or communities, largest first
+func clusterResult(title string, groups []noteGroup, isolated int, args GraphClustersArgs, extra map[string]any) (*mcp.CallToolResult, any, error) {
+	total := len(groups)
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultGraphLimit
+	}
+	truncated := total > limit
+	if truncated {
+		groups = groups[:limit]
+	}
+	for i := range groups {
+		if len(groups[i].Notes) > maxGroupNotes {
+			groups[i].Notes = groups[i].Notes[:maxGroupNotes]
+			truncated = true
+		}
+	}
+
+	if !isDetailedMode(args.Mode) {
+		data := map[string]any{
+			"total":    total,
+			"isolated": isolated,
+			"groups":   groups,
+		}
+		for key, value := range extra {
+			data[key] = value
+		}
+		return compactResult(fmt.Sprintf("Found %d %s and %d isolated notes", total, title, isolated), truncated, data, nil)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %d %s (%d isolated notes)\n\n", total, title, isolated)
+	if modularity, ok := extra["modularity"]; ok {
+		fmt.Fprintf(&sb, "Modularity: %v\n\n", modularity)
+	}
+	for i, group := range groups {
+		fmt.Fprintf(&sb, "## %d. %d notes\n", i+1, group.Size)
+		for _, note := range group.Notes {
+			fmt.Fprintf(&sb, "- [[%s]]\n", strings.TrimSuffix(note, ".md"))
+		}
+		if group.Size > len(group.Notes) {
+			fmt.Fprintf(&sb, "- ... and %d more\n", group.Size-len(group.Notes))
+		}
+		sb.WriteString("\n")
+	}
+	if total > len(groups) {
+		fmt.Fprintf(&sb, "... and %d more\n", total-len(groups))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: sb.String()},
+		},
+	}, nil, nil
+}
+
+// pathStep is one link on a path between notes. Direction is "forward"
+// when From links to To, "backward" when To links to From, or "both".
+type pathStep struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Direction string `json:"direction"`
+}
+
+// ShortestPathHandler finds how two notes are connected through links
+func (v *Vault) ShortestPathHandler(ctx context.Context, req *mcp.CallToolRequest, args ShortestPathArgs) (*mcp.CallToolResult, any, error) {
+	g, err := v.loadAnalyticsGraph(args.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve notes: %v", err)
+	}
+	from, err := g.node(resolver, args.Source)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := g.node(resolver, args.Target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path := g.shortestPath(from, to, args.Directed)
+	steps := make([]pathStep, 0, len(path))
+	for i := 1; i < len(path); i++ {
+		steps = append(steps, pathStep{From: g.paths[path[i-1]], To: g.paths[path[i]], Direction: g.linkDirection(path[i-1], path[i])})
+	}
+
+	summary := fmt.Sprintf("%s is %d hops from %s", g.paths[to], len(steps), g.paths[from])
+	if path == nil {
+		summary = fmt.Sprintf("No path from %s to %s", g.paths[from], g.paths[to])
+	}
+	if !isDetailedMode(args.Mode) {
+		return compactResult(summary, false, map[string]any{
+			"from":     g.paths[from],
+			"to":       g.paths[to],
+			"found":    path != nil,
+			"hops":     len(steps),
+			"directed": args.Directed,
+			"steps":    steps,
+		}, nil)
+	}
+	if path == nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: summary},
+			},
+		}, nil, nil
+	}
+
+	arrows := map[string]string{"forward": "→", "backward": "←", "both": "↔"}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n[[%s]]", summary, strings.TrimSuffix(g.paths[from], ".md"))
+	for _, step := range steps {
+		fmt.Fprintf(&sb, " %s [[%s]]", arrows[step.Direction], strings.TrimSuffix(step.To, ".md"))
+	}
+	sb.WriteString("\n")
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: sb.String()},
+		},
+	}, nil, nil
+}
+
+// neighbor is a note within some hops of another.
+type neighbor struct {
+	Path string `json:"path"`
+	Hops int    `json:"hops"`
+}
+
+// NeighborhoodHandler lists the notes within N hops of a note
+func (v *Vault) NeighborhoodHandler(ctx context.Context, req *mcp.CallToolRequest, args NeighborhoodArgs) (*mcp.CallToolResult, any, error) {
+	g, err := v.loadAnalyticsGraph(args.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve notes: %v", err)
+	}
+	from, err := g.node(resolver, args.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	depth := args.Depth
+	if depth <= 0 {
+		depth = 1
+	}
+
+	var neighbors []neighbor
+	for i, hops := range g.neighborhood(from, depth) {
+		neighbors = append(neighbors, neighbor{Path: g.paths[i], Hops: hops})
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		if neighbors[i].Hops != neighbors[j].Hops {
+			return neighbors[i].Hops < neighbors[j].Hops
+		}
+		return neighbors[i].Path < neighbors[j].Path
+	})
+	total := len(neighbors)
+	limit := args.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	truncated := total > limit
+	if truncated {
+		neighbors = neighbors[:limit]
+	}
+
+	summary := fmt.Sprintf("Found %d notes within %d hops of %s", total, depth, g.paths[from])
+	if !isDetailedMode(args.Mode) {
+		return compactResult(summary, truncated, map[string]any{
+			"path":      g.paths[from],
+			"depth":     depth,
+			"total":     total,
+			"neighbors": neighbors,
+		}, nil)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", summary)
+	for i, n := range neighbors {
+		if i == 0 || n.Hops != neighbors[i-1].Hops {
+			fmt.Fprintf(&sb, "\n## %d hops\n", n.Hops)
+		}
+		fmt.Fprintf(&sb, "- [[%s]]\n", strings.TrimSuffix(n.Path, ".md"))
+	}
+	if truncated {
+		fmt.Fprintf(&sb, "\n... and %d more\n", total-len(neighbors))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: sb.String()},
+		},
+	}, nil, nil
+}
@@ -0,0 +1,214 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// writeGraphVault writes two linked triangles joined by a3 -> b1, a
+// separate pair and an isolated note.
+func writeGraphVault(t *testing.T, dir string) {
+	t.Helper()
+	files := map[string]string{
+		"a/a1.md": "[[a2]] [[a3]]",
+		"a/a2.md": "[[a3]]",
+		"a/a3.md": "[[a1]] [[b1]]",
+		"b/b1.md": "[[b2]]",
+		"b/b2.md": "[[b3]]",
+		"b/b3.md": "[[b1]] ![[b2]]",
+		"p.md":    "[[q]]",
+		"q.md":    "",
+		"lone.md": "nothing here",
+	}
+	for name, content := range files {
+		writeTestFile(t, dir, name, content)
+	}
+}
+
+// compactData decodes the data of a compact response.
+func compactData(t *testing.T, result *mcp.CallToolResult, data any) string {
+	t.Helper()
+	var resp struct {
+		Summary string          `json:"summary"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(resp.Data, data); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Summary
+}
+
+func TestGraphCentrality(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeGraphVault(t, dir)
+	ctx := context.Background()
+
+	var data struct {
+		Ranked []noteScore `json:"ranked"`
+	}
+	result, _, err := v.GraphCentralityHandler(ctx, nil, GraphCentralityArgs{Metric: "betweenness", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compactData(t, result, &data)
+	if len(data.Ranked) != 2 || data.Ranked[0].Path != "a/a3.md" || data.Ranked[1].Path != "b/b1.md" {
+		t.Errorf("expected the bridge notes first, got %+v", data.Ranked)
+	}
+	if data.Ranked[0].Incoming != 2 || data.Ranked[0].Outgoing != 2 {
+		t.Errorf("unexpected degree: %+v", data.Ranked[0])
+	}
+
+	result, _, err = v.GraphCentralityHandler(ctx, nil, GraphCentralityArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compactData(t, result, &data)
+	// The b triangle only receives links, so it collects the most rank.
+	if data.Ranked[0].Path != "b/b2.md" || data.Ranked[len(data.Ranked)-1].Path != "p.md" {
+		t.Errorf("unexpected pagerank order: %+v", data.Ranked)
+	}
+	sum := 0.0
+	for _, r := range data.Ranked {
+		sum += r.Score
+	}
+	if sum < 0.99 || sum > 1.01 {
+		t.Errorf("pagerank sums to %f", sum)
+	}
+
+	if _, _, err := v.GraphCentralityHandler(ctx, nil, GraphCentralityArgs{Metric: "closeness"}); err == nil {
+		t.Error("expected unknown metric to fail")
+	}
+}
+
+func TestGraphClusters(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeGraphVault(t, dir)
+	ctx := context.Background()
+
+	var data struct {
+		Total      int         `json:"total"`
+		Isolated   int         `json:"isolated"`
+		Groups     []noteGroup `json:"groups"`
+		Modularity float64     `json:"modularity"`
+	}
+	result, _, err := v.GraphComponentsHandler(ctx, nil, GraphClustersArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compactData(t, result, &data)
+	if data.Total != 2 || data.Isolated != 1 || data.Groups[0].Size != 6 || strings.Join(data.Groups[1].Notes, ",") != "p.md,q.md" {
+		t.Errorf("unexpected components: %+v", data)
+	}
+
+	result, _, err = v.GraphCommunitiesHandler(ctx, nil, GraphClustersArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compactData(t, result, &data)
+	var groups []string
+	for _, g := range data.Groups {
+		groups = append(groups, strings.Join(g.Notes, ","))
+	}
+	if got := strings.Join(groups, " | "); got != "a/a1.md,a/a2.md,a/a3.md | b/b1.md,b/b2.md,b/b3.md | p.md,q.md" {
+		t.Errorf("communities = %s", got)
+	}
+	if data.Modularity <= 0.3 {
+		t.Errorf("modularity = %f", data.Modularity)
+	}
+
+	result, _, err = v.GraphCommunitiesHandler(ctx, nil, GraphClustersArgs{Limit: 1, Mode: modeDetailed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"# 3 communities (1 isolated notes)", "## 1. 3 notes", "- [[a/a1]]", "... and 2 more"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("detailed output missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeGraphVault(t, dir)
+	ctx := context.Background()
+
+	var data struct {
+		Found bool       `json:"found"`
+		Hops  int        `json:"hops"`
+		Steps []pathStep `json:"steps"`
+	}
+	result, _, err := v.ShortestPathHandler(ctx, nil, ShortestPathArgs{Source: "b2", Target: "a2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := compactData(t, result, &data)
+	if !data.Found || data.Hops != 3 || summary != "a/a2.md is 3 hops from b/b2.md" {
+		t.Fatalf("unexpected path %q: %+v", summary, data)
+	}
+	for _, step := range data.Steps {
+		if step.Direction != "backward" {
+			t.Errorf("expected every link to point back towards b2: %+v", data.Steps)
+		}
+	}
+
+	result, _, err = v.ShortestPathHandler(ctx, nil, ShortestPathArgs{Source: "b2", Target: "a2", Directed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary := compactData(t, result, &data); data.Found || summary != "No path from b/b2.md to a/a2.md" {
+		t.Errorf("expected no directed path, got %q", summary)
+	}
+
+	result, _, err = v.ShortestPathHandler(ctx, nil, ShortestPathArgs{Source: "a1", Target: "b1", Mode: modeDetailed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "[[a/a1]] ↔ [[a/a3]] → [[b/b1]]") {
+		t.Errorf("unexpected detailed path:\n%s", text)
+	}
+
+	if _, _, err := v.ShortestPathHandler(ctx, nil, ShortestPathArgs{Source: "a1", Target: "missing"}); err == nil {
+		t.Error("expected missing note to fail")
+	}
+}
+
+func TestNeighborhood(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeGraphVault(t, dir)
+	ctx := context.Background()
+
+	var data struct {
+		Total     int        `json:"total"`
+		Neighbors []neighbor `json:"neighbors"`
+	}
+	result, _, err := v.NeighborhoodHandler(ctx, nil, NeighborhoodArgs{Path: "a/a3.md", Depth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compactData(t, result, &data)
+	var got []string
+	for _, n := range data.Neighbors {
+		got = append(got, fmt.Sprintf("%s:%d", n.Path, n.Hops))
+	}
+	if strings.Join(got, " ") != "a/a1.md:1 a/a2.md:1 b/b1.md:1 b/b2.md:2 b/b3.md:2" {
+		t.Errorf("neighborhood = %v", got)
+	}
+
+	result, _, err = v.NeighborhoodHandler(ctx, nil, NeighborhoodArgs{Path: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compactData(t, result, &data)
+	if data.Total != 1 || data.Neighbors[0].Path != "q.md" {
+		t.Errorf("unexpected neighborhood: %+v", data)
+	}
+}
@@ -0,0 +1,332 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// graphExportFormats lists the formats export-graph can write.
+var graphExportFormats = []string{"json", "graphml", "gexf", "dot"}
+
+// exportNode is a note or tag in an exported graph.
+type exportNode struct {
+	ID       string
+	Label    string
+	Kind     string // "note" or "tag"
+	Folder   string
+	Tags     []string
+	Words    int
+	Modified time.Time
+}
+
+// exportEdge is a directed edge in an exported graph. Repeated links between
+// the same notes are merged and counted in Weight.
+type exportEdge struct {
+	Source string
+	Target string
+	Kind   string // "link", "embed" or "tag"
+	Weight int
+}
+
+// exportGraph is the note and tag graph in a form that serializes to
+// standard graph formats.
+type exportGraph struct {
+	Nodes []exportNode
+	Edges []exportEdge
+}
+
+// buildExportGraph builds the graph of notes under searchPath: link and embed
+// edges between notes, and an edge from each note to each of its tags.
+func (v *Vault) buildExportGraph(searchPath string) (*exportGraph, error) {
+	links, err := v.buildLinkGraph(searchPath)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &exportGraph{}
+	tags := make(map[string]bool)
+	edges := make(map[exportEdge]int)
+	for _, note := range notes {
+		// Node IDs keep .md, like the graph analytics and local graph tools
+		id := note.RelPath
+		key := strings.TrimSuffix(note.RelPath, ".md")
+		noteTags := append([]string(nil), note.Tags...)
+		sort.Strings(noteTags)
+
+		folder := filepath.ToSlash(filepath.Dir(note.RelPath))
+		if folder == "." {
+			folder = "/"
+		}
+		graph.Nodes = append(graph.Nodes, exportNode{
+			ID:       id,
+			Label:    strings.TrimSuffix(filepath.Base(note.RelPath), ".md"),
+			Kind:     "note",
+			Folder:   folder,
+			Tags:     noteTags,
+			Words:    len(strings.Fields(note.Body)),
+			Modified: note.ModTime.UTC(),
+		})
+
+		if n := links.notes[key]; n != nil {
+			for _, e := range n.edges {
+				edges[exportEdge{Source: id, Target: e.target + ".md", Kind: linkEdgeKind(e)}]++
+			}
+		}
+		for _, tag := range noteTags {
+			tags[tag] = true
+			edges[exportEdge{Source: id, Target: "#" + tag, Kind: "tag"}]++
+		}
+	}
+
+	for tag := range tags {
+		graph.Nodes = append(graph.Nodes, exportNode{ID: "#" + tag, Label: "#" + tag, Kind: "tag"})
+	}
+	for edge, weight := range edges {
+		edge.Weight = weight
+		graph.Edges = append(graph.Edges, edge)
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		if graph.Nodes[i].Kind != graph.Nodes[j].Kind {
+			return graph.Nodes[i].Kind == "note"
+		}
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Kind < b.Kind
+	})
+	return graph, nil
+}
+
+func linkEdgeKind(e linkEdge) string {
+	if e.embed {
+		return "embed"
+	}
+	return "link"
+}
+
+// encode serializes the graph in one of graphExportFormats.
+func (g *exportGraph) encode(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", "json":
+		return g.nodeLinkJSON()
+	case "graphml":
+		return g.graphML(), nil
+	case "gexf":
+		return g.gexf(), nil
+	case "dot":
+		return g.dot(), nil
+	default:
+		return "", fmt.Errorf("unknown graph format: %s (use %s)", format, strings.Join(graphExportFormats, ", "))
+	}
+}
+
+// nodeLinkJSON writes the node-link JSON read by networkx.node_link_graph
+// and d3-force.
+func (g *exportGraph) nodeLinkJSON() (string, error) {
+	nodes := make([]map[string]any, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		node := map[string]any{"id": n.ID, "label": n.Label, "kind": n.Kind}
+		if n.Kind == "note" {
+			node["folder"] = n.Folder
+			node["tags"] = append([]string{}, n.Tags...)
+			node["words"] = n.Words
+			node["mtime"] = n.Modified.Format(time.RFC3339)
+		}
+		nodes = append(nodes, node)
+	}
+	links := make([]map[string]any, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		links = append(links, map[string]any{"source": e.Source, "target": e.Target, "kind": e.Kind, "weight": e.Weight})
+	}
+
+	data, err := json.MarshalIndent(map[string]any{
+		"directed":   true,
+		"multigraph": false,
+		"graph":      map[string]any{"name": "obx vault graph"},
+		"nodes":      nodes,
+		"links":      links,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode graph: %v", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// graphML writes GraphML, read by Gephi, yEd, Cytoscape and networkx.
+func (g *exportGraph) graphML() string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	for _, key := range []struct{ id, target, name, typ string }{
+		{"label", "node", "label", "string"},
+		{"kind", "node", "kind", "string"},
+		{"folder", "node", "folder", "string"},
+		{"tags", "node", "tags", "string"},
+		{"words", "node", "words", "int"},
+		{"mtime", "node", "mtime", "string"},
+		{"ekind", "edge", "kind", "string"},
+		{"weight", "edge", "weight", "int"},
+	} {
+		fmt.Fprintf(&sb, `  <key id="%s" for="%s" attr.name="%s" attr.type="%s"/>`+"\n", key.id, key.target, key.name, key.typ)
+	}
+	sb.WriteString(`  <graph id="vault" edgedefault="directed">` + "\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, `    <node id="%s">`+"\n", xmlEscape(n.ID))
+		writeGraphMLData(&sb, "label", n.Label)
+		writeGraphMLData(&sb, "kind", n.Kind)
+		if n.Kind == "note" {
+			writeGraphMLData(&sb, "folder", n.Folder)
+			writeGraphMLData(&sb, "tags", strings.Join(n.Tags, ","))
+			writeGraphMLData(&sb, "words", fmt.Sprint(n.Words))
+			writeGraphMLData(&sb, "mtime", n.Modified.Format(time.RFC3339))
+		}
+		sb.WriteString("    </node>\n")
+	}
+	for i, e := range g.Edges {
+		fmt.Fprintf(&sb, `    <edge id="e%d" source="%s" target="%s">`+"\n", i, xmlEscape(e.Source), xmlEscape(e.Target))
+		writeGraphMLData(&sb, "ekind", e.Kind)
+		writeGraphMLData(&sb, "weight", fmt.Sprint(e.Weight))
+		sb.WriteString("    </edge>\n")
+	}
+	sb.WriteString("  </graph>\n</graphml>\n")
+	return sb.String()
+}
+
+func writeGraphMLData(sb *strings.Builder, key, value string) {
+	fmt.Fprintf(sb, `      <data key="%s">%s</data>`+"\n", key, xmlEscape(value))
+}
+
+// gexf writes GEXF 1.3, Gephi's native format.
+func (g *exportGraph) gexf() string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<gexf xmlns="http://gexf.net/1.3" version="1.3">` + "\n")
+	sb.WriteString(`  <graph defaultedgetype="directed" mode="static">` + "\n")
+	sb.WriteString(`    <attributes class="node">` + "\n")
+	for i, attr := range [][2]string{{"kind", "string"}, {"folder", "string"}, {"tags", "liststring"}, {"words", "integer"}, {"mtime", "string"}} {
+		fmt.Fprintf(&sb, `      <attribute id="%d" title="%s" type="%s"/>`+"\n", i, attr[0], attr[1])
+	}
+	sb.WriteString("    </attributes>\n")
+	sb.WriteString(`    <attributes class="edge">` + "\n")
+	sb.WriteString(`      <attribute id="0" title="kind" type="string"/>` + "\n")
+	sb.WriteString("    </attributes>\n")
+
+	sb.WriteString("    <nodes>\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, `      <node id="%s" label="%s">`+"\n", xmlEscape(n.ID), xmlEscape(n.Label))
+		sb.WriteString("        <attvalues>\n")
+		values := []string{n.Kind}
+		if n.Kind == "note" {
+			values = append(values, n.Folder, "["+strings.Join(n.Tags, ",")+"]", fmt.Sprint(n.Words), n.Modified.Format(time.RFC3339))
+		}
+		for i, value := range values {
+			fmt.Fprintf(&sb, `          <attvalue for="%d" value="%s"/>`+"\n", i, xmlEscape(value))
+		}
+		sb.WriteString("        </attvalues>\n      </node>\n")
+	}
+	sb.WriteString("    </nodes>\n    <edges>\n")
+	for i, e := range g.Edges {
+		fmt.Fprintf(&sb, `      <edge id="%d" source="%s" target="%s" weight="%d">`+"\n", i, xmlEscape(e.Source), xmlEscape(e.Target), e.Weight)
+		fmt.Fprintf(&sb, `        <attvalues><attvalue for="0" value="%s"/></attvalues>`+"\n", e.Kind)
+		sb.WriteString("      </edge>\n")
+	}
+	sb.WriteString("    </edges>\n  </graph>\n</gexf>\n")
+	return sb.String()
+}
+
+// dot writes Graphviz DOT. Embeds are dashed and tag edges dotted.
+func (g *exportGraph) dot() string {
+	var sb strings.Builder
+	sb.WriteString("digraph vault {\n")
+	for _, n := range g.Nodes {
+		if n.Kind == "tag" {
+			fmt.Fprintf(&sb, "  %s [label=%s, kind=tag, shape=box];\n", dotQuote(n.ID), dotQuote(n.Label))
+			continue
+		}
+		fmt.Fprintf(&sb, "  %s [label=%s, kind=note, folder=%s, tags=%s, words=%d, mtime=%s];\n",
+			dotQuote(n.ID), dotQuote(n.Label), dotQuote(n.Folder), dotQuote(strings.Join(n.Tags, ",")), n.Words, dotQuote(n.Modified.Format(time.RFC3339)))
+	}
+	styles := map[string]string{"link": "solid", "embed": "dashed", "tag": "dotted"}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "  %s -> %s [kind=%s, weight=%d, style=%s];\n", dotQuote(e.Source), dotQuote(e.Target), e.Kind, e.Weight, styles[e.Kind])
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// ExportGraphHandler exports the note and tag graph as JSON, GraphML, GEXF
+// or DOT, returning it or writing it to a file in the vault
+func (v *Vault) ExportGraphHandler(ctx context.Context, req *mcp.CallToolRequest, args ExportGraphArgs) (*mcp.CallToolResult, any, error) {
+	searchPath := v.GetPath()
+	if args.Directory != "" {
+		searchPath = filepath.Join(v.GetPath(), args.Directory)
+	}
+	if !v.isPathSafe(searchPath) {
+		return nil, nil, fmt.Errorf("search path must be within vault")
+	}
+
+	graph, err := v.buildExportGraph(searchPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build graph: %v", err)
+	}
+	data, err := graph.encode(args.Format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if args.Output == "" {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: data},
+			},
+		}, nil, nil
+	}
+
+	fullPath := filepath.Join(v.GetPath(), args.Output)
+	if !v.isPathSafe(fullPath) {
+		return nil, nil, fmt.Errorf("output path must be within vault")
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(fullPath, []byte(data), 0o600); err != nil {
+		return nil, nil, fmt.Errorf("failed to write graph: %v", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Exported %d nodes and %d edges to %s", len(graph.Nodes), len(graph.Edges), args.Output)},
+		},
+	}, nil, nil
+}
@@ -0,0 +1,120 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestBuildExportGraph(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "Home.md", "---\ntags: [hub]\n---\nSee [[projects/Plan]] and [[projects/Plan#Goals]].\n![[Ideas]]\n#review\n")
+	writeTestFile(t, dir, "projects/Plan.md", "# Goals\nBack to [[Home]].\n")
+	writeTestFile(t, dir, "Ideas.md", "one two three #review\n")
+
+	graph, err := v.buildExportGraph(v.GetPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	nodes := make(map[string]exportNode)
+	for _, n := range graph.Nodes {
+		ids = append(ids, n.ID)
+		nodes[n.ID] = n
+	}
+	if got := strings.Join(ids, ","); got != "Home.md,Ideas.md,projects/Plan.md,#hub,#review" {
+		t.Errorf("nodes = %s", got)
+	}
+	if plan := nodes["projects/Plan.md"]; plan.Folder != "projects" || plan.Label != "Plan" || plan.Words != 5 {
+		t.Errorf("unexpected note attributes: %+v", plan)
+	}
+	if home := nodes["Home.md"]; home.Folder != "/" || strings.Join(home.Tags, ",") != "hub,review" {
+		t.Errorf("unexpected note attributes: %+v", home)
+	}
+
+	var edges []string
+	for _, e := range graph.Edges {
+		edges = append(edges, fmt.Sprintf("%s -%s-> %s (%d)", e.Source, e.Kind, e.Target, e.Weight))
+	}
+	want := []string{
+		"Home.md -tag-> #hub (1)",
+		"Home.md -tag-> #review (1)",
+		"Home.md -embed-> Ideas.md (1)",
+		"Home.md -link-> projects/Plan.md (2)",
+		"Ideas.md -tag-> #review (1)",
+		"projects/Plan.md -link-> Home.md (1)",
+	}
+	if strings.Join(edges, "\n") != strings.Join(want, "\n") {
+		t.Errorf("edges =\n%s\nwant\n%s", strings.Join(edges, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestExportGraphHandler(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "A & B.md", "Links to [[C]] #topic\n")
+	writeTestFile(t, dir, "C.md", "Back to [[A & B]]\n")
+	ctx := context.Background()
+
+	export := func(format string) string {
+		t.Helper()
+		result, _, err := v.ExportGraphHandler(ctx, nil, ExportGraphArgs{Format: format})
+		if err != nil {
+			t.Fatalf("export %s: %v", format, err)
+		}
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	var nodeLink struct {
+		Directed bool             `json:"directed"`
+		Nodes    []map[string]any `json:"nodes"`
+		Links    []map[string]any `json:"links"`
+	}
+	if err := json.Unmarshal([]byte(export("json")), &nodeLink); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !nodeLink.Directed || len(nodeLink.Nodes) != 3 || len(nodeLink.Links) != 3 {
+		t.Errorf("unexpected node-link graph: %+v", nodeLink)
+	}
+
+	for _, format := range []string{"graphml", "gexf"} {
+		text := export(format)
+		var doc struct {
+			XMLName xml.Name
+		}
+		if err := xml.Unmarshal([]byte(text), &doc); err != nil {
+			t.Errorf("invalid %s: %v", format, err)
+		}
+		if !strings.Contains(text, `"A &amp; B.md"`) || !strings.Contains(text, `#topic`) {
+			t.Errorf("%s missing nodes:\n%s", format, text)
+		}
+	}
+
+	dot := export("DOT")
+	if !strings.HasPrefix(dot, "digraph vault {") || !strings.Contains(dot, `"A & B.md" -> "#topic" [kind=tag, weight=1, style=dotted];`) {
+		t.Errorf("unexpected dot:\n%s", dot)
+	}
+
+	if _, _, err := v.ExportGraphHandler(ctx, nil, ExportGraphArgs{Format: "svg"}); err == nil {
+		t.Error("expected unknown format to fail")
+	}
+
+	result, _, err := v.ExportGraphHandler(ctx, nil, ExportGraphArgs{Format: "graphml", Output: "exports/vault.graphml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; text != "Exported 3 nodes and 3 edges to exports/vault.graphml" {
+		t.Errorf("unexpected output: %s", text)
+	}
+	if got := readTestFile(t, dir, "exports/vault.graphml"); !strings.HasPrefix(got, "<?xml") {
+		t.Errorf("unexpected file:\n%s", got)
+	}
+	if _, _, err := v.ExportGraphHandler(ctx, nil, ExportGraphArgs{Output: "../out.json"}); err == nil {
+		t.Error("expected output outside vault to fail")
+	}
+}
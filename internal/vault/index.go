@@ -0,0 +1,221 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// indexedNote holds the parsed state of a single note. Entries are treated as
+// immutable: when a file changes on disk a fresh entry replaces the old one, so
+// callers may keep references without holding the index lock.
+type indexedNote struct {
+	RelPath      string
+	ModTime      time.Time
+	Size         int64
+	Content      string
+	Lines        []string
+	Body         string // content without frontmatter (see RemoveFrontmatter)
+	Frontmatter  Frontmatter
+	Tags         []string
+	Links        []string // every link target, embeds included
+	Embeds       []string
+	Headings     []Heading // line numbers are relative to Body, like SearchHeadingsHandler
+	Tasks        []Task    // line numbers are relative to Content
+	InlineFields []InlineField
+	BlockIDs     []string
+}
+
+// parseIndexedNote parses raw note content into an index entry.
+func parseIndexedNote(relPath string, info os.FileInfo, content string) *indexedNote {
+	lines := strings.Split(content, "\n")
+	body := RemoveFrontmatter(content)
+
+	var tasks []Task
+	for i, line := range lines {
+		if task := ParseTask(line, i+1); task != nil {
+			task.File = relPath
+			tasks = append(tasks, *task)
+		}
+	}
+
+	tags := ExtractTags(content)
+	sort.Strings(tags)
+
+	return &indexedNote{
+		RelPath:      relPath,
+		ModTime:      info.ModTime(),
+		Size:         info.Size(),
+		Content:      content,
+		Lines:        lines,
+		Body:         body,
+		Frontmatter:  ParseFrontmatter(content),
+		Tags:         tags,
+		Links:        ExtractLinks(content),
+		Embeds:       ExtractEmbeds(content),
+		Headings:     extractHeadings(body),
+		Tasks:        tasks,
+		InlineFields: ExtractInlineFields(content),
+		BlockIDs:     extractBlockIDs(body),
+	}
+}
+
+// noteIndex caches parsed notes keyed by vault-relative path. Entries are
+// invalidated per file by comparing modification time and size.
+type noteIndex struct {
+	mu    sync.RWMutex
+	root  string
+	notes map[string]*indexedNote
+}
+
+func newNoteIndex(root string) *noteIndex {
+	return &noteIndex{root: root, notes: make(map[string]*indexedNote)}
+}
+
+// reset drops every cached entry and rebinds the index to a new vault root.
+func (idx *noteIndex) reset(root string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.root = root
+	idx.notes = make(map[string]*indexedNote)
+}
+
+// lookup returns the cached entry for relPath if it is still fresh for info.
+func (idx *noteIndex) lookup(root, relPath string, info os.FileInfo) *indexedNote {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if idx.root != root {
+		return nil
+	}
+	note, ok := idx.notes[relPath]
+	if !ok || !note.ModTime.Equal(info.ModTime()) || note.Size != info.Size() {
+		return nil
+	}
+	return note
+}
+
+// store records a freshly parsed entry, ignoring it if the root has moved on.
+func (idx *noteIndex) store(root string, note *indexedNote) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.root != root {
+		idx.root = root
+		idx.notes = make(map[string]*indexedNote)
+	}
+	idx.notes[note.RelPath] = note
+}
+
+// remove drops a single entry.
+func (idx *noteIndex) remove(relPath string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.notes, relPath)
+}
+
+// prune drops entries under prefix that were not seen during a scan.
+func (idx *noteIndex) prune(root, prefix string, seen map[string]bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.root != root {
+		return
+	}
+	for relPath := range idx.notes {
+		if seen[relPath] {
+			continue
+		}
+		if prefix == "." || relPath == prefix || strings.HasPrefix(relPath, prefix+string(os.PathSeparator)) {
+			delete(idx.notes, relPath)
+		}
+	}
+}
+
+// len reports the number of cached entries.
+func (idx *noteIndex) len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.notes)
+}
+
+// load returns the entry for a note, parsing it again only when it changed.
+func (idx *noteIndex) load(root, relPath string, info os.FileInfo) (*indexedNote, error) {
+	if note := idx.lookup(root, relPath, info); note != nil {
+		return note, nil
+	}
+	content, err := os.ReadFile(filepath.Join(root, relPath))
+	if err != nil {
+		return nil, err
+	}
+	note := parseIndexedNote(relPath, info, string(content))
+	idx.store(root, note)
+	return note, nil
+}
+
+// vaultScan is the result of walking a directory through the index.
+type vaultScan struct {
+	notes []*indexedNote // in walk order
+	dirs  []string       // vault-relative directories below the scanned root
+}
+
+// scanIndexed walks searchPath and returns parsed notes from the index,
+// refreshing only files whose mtime or size changed since the last scan.
+func (v *Vault) scanIndexed(searchPath string) (*vaultScan, error) {
+	root := v.GetPath()
+	scan := &vaultScan{}
+	seen := make(map[string]bool)
+
+	err := v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		relPath, _ := filepath.Rel(root, path)
+		if info.IsDir() {
+			if path != searchPath {
+				scan.dirs = append(scan.dirs, relPath)
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		note, err := v.index.load(root, relPath, info)
+		if err != nil {
+			return nil
+		}
+		seen[relPath] = true
+		scan.notes = append(scan.notes, note)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, _ := filepath.Rel(root, searchPath)
+	v.index.prune(root, prefix, seen)
+	return scan, nil
+}
+
+// indexedNotes returns the parsed notes under searchPath in walk order.
+func (v *Vault) indexedNotes(searchPath string) ([]*indexedNote, error) {
+	scan, err := v.scanIndexed(searchPath)
+	if err != nil {
+		return nil, err
+	}
+	return scan.notes, nil
+}
+
+// indexedNote returns the parsed entry for a single vault-relative note path.
+func (v *Vault) indexedNote(relPath string) (*indexedNote, error) {
+	root := v.GetPath()
+	relPath = filepath.Clean(relPath)
+	info, err := os.Stat(filepath.Join(root, relPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			v.index.remove(relPath)
+		}
+		return nil, err
+	}
+	return v.index.load(root, relPath, info)
+}
@@ -0,0 +1,133 @@
+package vault
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestIndexedNoteParsesOnce(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "note.md", "---\nstatus: draft\ntags: [alpha]\n---\n# Title\n\n- [ ] Task #beta\nowner:: alice\nSee [[Other]].\n")
+
+	first, err := v.indexedNote("note.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Frontmatter["status"] != "draft" {
+		t.Errorf("expected status frontmatter, got %v", first.Frontmatter)
+	}
+	if strings.Join(first.Tags, ",") != "alpha,beta" {
+		t.Errorf("expected sorted tags [alpha beta], got %v", first.Tags)
+	}
+	if len(first.Links) != 1 || first.Links[0] != "Other" {
+		t.Errorf("expected link to Other, got %v", first.Links)
+	}
+	if len(first.Headings) != 1 || first.Headings[0].Text != "Title" {
+		t.Errorf("expected Title heading, got %v", first.Headings)
+	}
+	if len(first.Tasks) != 1 || first.Tasks[0].File != "note.md" || first.Tasks[0].Line != 7 {
+		t.Errorf("unexpected tasks: %+v", first.Tasks)
+	}
+	if len(first.InlineFields) != 1 || first.InlineFields[0].Key != "owner" {
+		t.Errorf("unexpected inline fields: %+v", first.InlineFields)
+	}
+
+	second, err := v.indexedNote("note.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("expected unchanged note to be served from the index")
+	}
+}
+
+func TestIndexInvalidatesByMtime(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "note.md", "alpha")
+
+	if _, err := v.indexedNote("note.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestFile(t, dir, "note.md", "bravo")
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "note.md"), future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	note, err := v.indexedNote("note.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Content != "bravo" {
+		t.Errorf("expected refreshed content, got %q", note.Content)
+	}
+}
+
+func TestIndexPrunesDeletedNotes(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "a.md", "a")
+	writeTestFile(t, dir, "sub/b.md", "b")
+
+	notes, err := v.indexedNotes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 notes, got %d", len(notes))
+	}
+
+	if err := os.Remove(filepath.Join(dir, "sub", "b.md")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.indexedNotes(filepath.Join(dir, "sub")); err != nil {
+		t.Fatal(err)
+	}
+	if got := v.index.len(); got != 1 {
+		t.Errorf("expected deleted note to be pruned, index has %d entries", got)
+	}
+}
+
+func TestIndexResetOnSwitchVault(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "a.md", "a")
+	if _, err := v.indexedNotes(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	other := t.TempDir()
+	v.SetPath(other)
+	if got := v.index.len(); got != 0 {
+		t.Errorf("expected index to be empty after switching vaults, got %d", got)
+	}
+}
+
+func TestSearchVaultSeesEdits(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "note.md", "nothing here")
+	ctx := context.Background()
+
+	if _, _, err := v.SearchVaultHandler(ctx, nil, SearchArgs{Query: "needle", Mode: modeDetailed}); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestFile(t, dir, "note.md", "a needle in the haystack")
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "note.md"), future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	result, _, err := v.SearchVaultHandler(ctx, nil, SearchArgs{Query: "needle", Mode: modeDetailed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "note.md") {
+		t.Errorf("expected edited note to match, got %q", text)
+	}
+}
@@ -202,36 +202,26 @@ func (v *Vault) searchInlineFields(dir string, query *inlineFieldQuery) ([]inlin
 		return nil, fmt.Errorf("search path must be within vault")
 	}
 
-	var results []inlineFieldResult
-
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, err
+	}
 
-		fields := ExtractInlineFields(string(content))
+	var results []inlineFieldResult
+	for _, note := range notes {
 		var matched []InlineField
-
-		for _, f := range fields {
+		for _, f := range note.InlineFields {
 			if query.matches(f) {
 				matched = append(matched, f)
 			}
 		}
 
 		if len(matched) > 0 {
-			relPath, _ := filepath.Rel(v.GetPath(), path)
-			results = append(results, inlineFieldResult{path: relPath, fields: matched})
+			results = append(results, inlineFieldResult{path: note.RelPath, fields: matched})
 		}
+	}
 
-		return nil
-	})
-
-	return results, err
+	return results, nil
 }
 
 // QueryInlineFieldsHandler searches notes by inline field values
@@ -0,0 +1,227 @@
+package vault
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// linkRewriter updates links after notes or files move. It resolves links
+// against the vault as it was before the move, so create it first, move the
+// files, then call apply.
+type linkRewriter struct {
+	v        *Vault
+	resolver *linkResolver
+}
+
+func (v *Vault) newLinkRewriter() (*linkRewriter, error) {
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, err
+	}
+	return &linkRewriter{v: v, resolver: resolver}, nil
+}
+
+// apply rewrites every link that resolves to a moved path, and relative links
+// inside moved notes, keeping each link's syntax and path style. moves maps
+// old to new vault-relative paths. Notes are read from their new location,
+// or from the old one when dryRun is set and nothing has moved. It returns
+// the number of notes that changed.
+func (w *linkRewriter) apply(moves map[string]string, dryRun bool) int {
+	root := w.v.GetPath()
+	sources := make([]string, 0, len(w.resolver.notes))
+	for relPath := range w.resolver.notes {
+		sources = append(sources, relPath)
+	}
+	sort.Strings(sources)
+
+	updated := 0
+	for _, oldSource := range sources {
+		newSource := oldSource
+		if moved, ok := moves[oldSource]; ok {
+			newSource = moved
+		}
+		readPath := filepath.Join(root, newSource)
+		if dryRun {
+			readPath = filepath.Join(root, oldSource)
+		}
+
+		content, err := os.ReadFile(readPath)
+		if err != nil {
+			continue
+		}
+		newContent := w.rewrite(string(content), oldSource, newSource, moves)
+		if newContent == string(content) {
+			continue
+		}
+		if !dryRun {
+			if err := os.WriteFile(readPath, []byte(newContent), 0o600); err != nil {
+				continue
+			}
+		}
+		updated++
+	}
+	return updated
+}
+
+// rewrite returns content with its links updated for the moves.
+func (w *linkRewriter) rewrite(content, oldSource, newSource string, moves map[string]string) string {
+	var sb strings.Builder
+	last, changed := 0, false
+	for _, link := range parseNoteLinks(content) {
+		written := link.Path(content)
+		if written == "" {
+			continue // same-note link
+		}
+		res := w.resolver.resolve(link.Target, oldSource)
+		if res.Target == "" || res.ByAlias {
+			continue
+		}
+		newTarget := res.Target
+		if moved, ok := moves[res.Target]; ok {
+			newTarget = moved
+		}
+		if newTarget == res.Target && newSource == oldSource {
+			continue
+		}
+
+		replacement := w.linkPath(link, written, oldSource, newSource, res.Target, newTarget)
+		if replacement == written {
+			continue
+		}
+		sb.WriteString(content[last:link.PathStart])
+		sb.WriteString(replacement)
+		last, changed = link.PathEnd, true
+	}
+	if !changed {
+		return content
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
+}
+
+// linkPath writes the path for newTarget in the style of the original link:
+// a bare file name stays a bare file name (unless that would become
+// ambiguous), a path relative to the linking note stays relative, and a vault
+// path stays a vault path. A link that left out .md still leaves it out, and
+// markdown links stay URL-encoded.
+func (w *linkRewriter) linkPath(link noteLink, written, oldSource, newSource, oldTarget, newTarget string) string {
+	decoded := written
+	if link.Markdown {
+		decoded = decodeLinkPart(written)
+	}
+	slashTarget := filepath.ToSlash(newTarget)
+	newSourceDir := path.Dir(filepath.ToSlash(newSource))
+
+	joined := path.Join(path.Dir(filepath.ToSlash(oldSource)), decoded)
+	relative := strings.HasPrefix(decoded, "./") || strings.HasPrefix(decoded, "../") ||
+		(strings.Contains(decoded, "/") && !strings.HasPrefix(decoded, "/") && sameLinkPath(joined, filepath.ToSlash(oldTarget)))
+	if newTarget == oldTarget && !relative {
+		return written
+	}
+
+	var p string
+	switch {
+	case relative:
+		p = relativeLinkPath(newSourceDir, slashTarget)
+		if strings.HasPrefix(decoded, "./") && !strings.HasPrefix(p, "../") {
+			p = "./" + p
+		}
+	case strings.HasPrefix(decoded, "/"):
+		p = "/" + slashTarget
+	case !strings.Contains(decoded, "/"):
+		p = path.Base(slashTarget)
+		if w.ambiguous(p, oldTarget) {
+			p = slashTarget
+		}
+	default:
+		p = slashTarget
+	}
+
+	if strings.HasSuffix(strings.ToLower(slashTarget), ".md") && !strings.HasSuffix(strings.ToLower(decoded), ".md") {
+		p = strings.TrimSuffix(p, path.Ext(p))
+	}
+	if link.Markdown && !link.Angle {
+		p = (&url.URL{Path: p}).EscapedPath()
+	}
+	return p
+}
+
+// sameLinkPath compares a written link path with a vault path, ignoring case
+// and a missing .md extension.
+func sameLinkPath(written, target string) bool {
+	written, target = strings.ToLower(written), strings.ToLower(target)
+	return written == target || written+".md" == target
+}
+
+// ambiguous reports whether a bare file name would match a file other than
+// the one being moved.
+func (w *linkRewriter) ambiguous(name, movedFrom string) bool {
+	for _, candidate := range w.resolver.byName[strings.ToLower(name)] {
+		if candidate != movedFrom {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeLinkPath returns target relative to dir, both slash-separated.
+func relativeLinkPath(dir, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(dir), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
+
+// rewriteHeadingLinks points every link from sourcePath to the heading
+// oldHeading in target at newHeading instead, keeping the rest of each link
+// as written. It returns the new content and the number of links changed.
+func (r *linkResolver) rewriteHeadingLinks(content, sourcePath, target, oldHeading, newHeading string) (string, int) {
+	want := normalizeHeadingRef(oldHeading)
+	written := strings.Join(strings.Fields(headingRefReplacer.Replace(newHeading)), " ")
+
+	var sb strings.Builder
+	last, changed := 0, 0
+	for _, link := range parseNoteLinks(content) {
+		if link.SubEnd <= link.SubStart || strings.HasPrefix(content[link.SubStart:], "^") {
+			continue
+		}
+		if r.resolve(link.Target, sourcePath).Target != target {
+			continue
+		}
+
+		// A nested subpath such as #Parent#Child may name the heading at any level.
+		segments := strings.Split(content[link.SubStart:link.SubEnd], "#")
+		replaced := false
+		for i, segment := range segments {
+			text := segment
+			if link.Markdown {
+				text = decodeLinkPart(segment)
+			}
+			if normalizeHeadingRef(text) != want {
+				continue
+			}
+			segments[i] = written
+			if link.Markdown && !link.Angle {
+				segments[i] = (&url.URL{Path: written}).EscapedPath()
+			}
+			replaced = true
+		}
+		if !replaced {
+			continue
+		}
+		sb.WriteString(content[last:link.SubStart])
+		sb.WriteString(strings.Join(segments, "#"))
+		last = link.SubEnd
+		changed++
+	}
+	if changed == 0 {
+		return content, 0
+	}
+	sb.WriteString(content[last:])
+	return sb.String(), changed
+}
@@ -0,0 +1,125 @@
+package vault
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestParseNoteLinks(t *testing.T) {
+	content := "[[Wiki#Head|alias]] [a](Note%20Name.md#My%20Heading) [b](<sub/With Space.md>) " +
+		"[c](https://example.com) [d](mailto:x@y.z) ![img](assets/pic.png \"title\") [e](#Local)"
+
+	links := parseNoteLinks(content)
+	want := []struct {
+		target   string
+		path     string
+		markdown bool
+	}{
+		{"Wiki#Head", "Wiki", false},
+		{"Note Name.md#My Heading", "Note%20Name.md", true},
+		{"sub/With Space.md", "sub/With Space.md", true},
+		{"assets/pic.png", "assets/pic.png", true},
+		{"#Local", "", true},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("got %d links, want %d: %+v", len(links), len(want), links)
+	}
+	for i, w := range want {
+		l := links[i]
+		if l.Target != w.target || l.Path(content) != w.path || l.Markdown != w.markdown {
+			t.Errorf("link %d = {%q %q %v}, want {%q %q %v}", i, l.Target, l.Path(content), l.Markdown, w.target, w.path, w.markdown)
+		}
+	}
+}
+
+func TestMarkdownLinksResolve(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "docs/Note Name.md", "# Note Name\n\n## My Heading\n")
+	writeTestFile(t, dir, "docs/guide.md", "[rel](Note%20Name.md#My%20Heading) [bad](Note%20Name.md#Nope) [gone](missing.md)\n")
+	ctx := context.Background()
+
+	result, _, err := v.BacklinksHandler(ctx, nil, GetBacklinksArgs{Path: "docs/Note Name.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "## "+filepath.Join("docs", "guide.md")) {
+		t.Errorf("markdown link not counted as backlink:\n%s", text)
+	}
+
+	result, _, err = v.BrokenLinksHandler(ctx, nil, BrokenLinksArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "[bad](Note%20Name.md#Nope) (no heading") || !strings.Contains(text, "[gone](missing.md)") || strings.Contains(text, "[rel]") {
+		t.Errorf("unexpected broken links:\n%s", text)
+	}
+}
+
+func TestRenameKeepsLinkStyle(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "projects/Old Name.md", "# Old\n\nSee [up](../index.md) and [[./Sibling]].\n")
+	writeTestFile(t, dir, "projects/Sibling.md", "# Sibling\n")
+	writeTestFile(t, dir, "index.md", strings.Join([]string{
+		"[[Old Name]]",
+		"[[projects/Old Name#Old|alias]]",
+		"[md](projects/Old%20Name.md#Old)",
+		"[angle](<projects/Old Name.md>)",
+		"[bare](Old%20Name.md)",
+		"[[Sibling]]",
+	}, "\n"))
+	writeTestFile(t, dir, "projects/sub/deep.md", "[rel](../Old%20Name.md) [[../Old Name]]\n")
+
+	_, _, err := v.RenameNoteHandler(context.Background(), nil, RenameNoteArgs{OldPath: "projects/Old Name.md", NewPath: "archive/New Name.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantIndex := strings.Join([]string{
+		"[[New Name]]",
+		"[[archive/New Name#Old|alias]]",
+		"[md](archive/New%20Name.md#Old)",
+		"[angle](<archive/New Name.md>)",
+		"[bare](New%20Name.md)",
+		"[[Sibling]]",
+	}, "\n")
+	if got := readTestFile(t, dir, "index.md"); got != wantIndex {
+		t.Errorf("index.md =\n%s\nwant\n%s", got, wantIndex)
+	}
+	if got, want := readTestFile(t, dir, "projects/sub/deep.md"), "[rel](../../archive/New%20Name.md) [[../../archive/New Name]]\n"; got != want {
+		t.Errorf("deep.md = %q, want %q", got, want)
+	}
+	// Relative links inside the moved note follow it.
+	if got, want := readTestFile(t, dir, "archive/New Name.md"), "# Old\n\nSee [up](../index.md) and [[../projects/Sibling]].\n"; got != want {
+		t.Errorf("moved note = %q, want %q", got, want)
+	}
+}
+
+func TestBulkMoveUpdatesLinks(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "a.md", "# A\n")
+	writeTestFile(t, dir, "b.md", "# B\n")
+	writeTestFile(t, dir, "index.md", "[[a]] [B](b.md) [[inbox/b]]\n")
+	writeTestFile(t, dir, "inbox/b.md", "# Other B\n")
+	ctx := context.Background()
+
+	result, _, err := v.BulkMoveHandler(ctx, nil, BulkMoveArgs{Paths: "a.md,b.md", Destination: "done", UpdateLinks: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "Would update links in 1 files") {
+		t.Errorf("unexpected dry run output:\n%s", text)
+	}
+
+	if _, _, err := v.BulkMoveHandler(ctx, nil, BulkMoveArgs{Paths: "a.md,b.md", Destination: "done", UpdateLinks: true}); err != nil {
+		t.Fatal(err)
+	}
+	// b.md becomes ambiguous with inbox/b.md, so its link gains a folder.
+	if got, want := readTestFile(t, dir, "index.md"), "[[a]] [B](done/b.md) [[inbox/b]]\n"; got != want {
+		t.Errorf("index.md = %q, want %q", got, want)
+	}
+}
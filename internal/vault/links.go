@@ -3,9 +3,11 @@ package vault
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -14,8 +16,145 @@ import (
 var (
 	// Matches wikilinks: [[Note Name]] or [[path/to/note|Alias]]
 	wikilinkRegex = regexp.MustCompile(`\[\[([^\]|]+)(?:\|[^\]]+)?\]\]`)
+
+	// Matches markdown links: [text](path/to/Note%20Name.md#heading),
+	// [text](<path with spaces.md>) and [text](path.md "title")
+	markdownLinkRegex = regexp.MustCompile(`\[([^\]]*)\]\((<[^>\n]*>|[^)\s]*)(?:\s+"[^"\n]*")?\)`)
+
+	// Matches a URL scheme such as https: or mailto:, which marks a markdown
+	// link as external
+	urlSchemeRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
 )
 
+// noteLink is one internal link in a note, in either syntax.
+type noteLink struct {
+	Target    string // path with optional #subpath, URL-decoded for markdown links
+	Markdown  bool
+	Embed     bool // ![[...]] or ![...](...)
+	Angle     bool // markdown target written as <path>
+	Start     int  // byte offset of the whole link
+	End       int
+	PathStart int // byte range of the path as written, before any #subpath
+	PathEnd   int
+	SubStart  int // byte range of the subpath as written, after the #; empty without one
+	SubEnd    int
+}
+
+// Path returns the link path as written, without the subpath.
+func (l noteLink) Path(content string) string {
+	return content[l.PathStart:l.PathEnd]
+}
+
+// parseNoteLinks returns the wikilinks and markdown links in content in
+// document order. External markdown links (anything with a URL scheme) are
+// skipped.
+func parseNoteLinks(content string) []noteLink {
+	var links []noteLink
+
+	for _, m := range wikilinkRegex.FindAllStringSubmatchIndex(content, -1) {
+		target := strings.TrimSpace(content[m[2]:m[3]])
+		if target == "" {
+			continue
+		}
+		start, end := m[2], m[3]
+		subStart, subEnd := 0, 0
+		if i := strings.Index(content[start:end], "#"); i >= 0 {
+			end = start + i
+			subStart, subEnd = end+1, m[3]
+			for subEnd > subStart && strings.ContainsRune(" \t\\", rune(content[subEnd-1])) {
+				subEnd--
+			}
+		}
+		// Trim surrounding spaces and the backslash of a pipe escaped in a table.
+		for end > start && strings.ContainsRune(" \t\\", rune(content[end-1])) {
+			end--
+		}
+		for start < end && (content[start] == ' ' || content[start] == '\t') {
+			start++
+		}
+		links = append(links, noteLink{
+			Target:    strings.TrimSuffix(target, `\`),
+			Embed:     isEmbed(content, m[0]),
+			Start:     linkStart(content, m[0]),
+			End:       m[1],
+			PathStart: start,
+			PathEnd:   end,
+			SubStart:  subStart,
+			SubEnd:    subEnd,
+		})
+	}
+
+	for _, m := range markdownLinkRegex.FindAllStringSubmatchIndex(content, -1) {
+		start, end := m[4], m[5]
+		angle := end-start >= 2 && content[start] == '<'
+		if angle {
+			start, end = start+1, end-1
+		}
+		raw := content[start:end]
+		if raw == "" || urlSchemeRegex.MatchString(raw) {
+			continue
+		}
+		pathPart, subpath, hasSubpath := strings.Cut(raw, "#")
+		target := decodeLinkPart(pathPart)
+		subStart, subEnd := 0, 0
+		if hasSubpath {
+			target += "#" + decodeLinkPart(subpath)
+			subStart, subEnd = start+len(pathPart)+1, end
+		}
+		links = append(links, noteLink{
+			Target:    target,
+			Markdown:  true,
+			Embed:     isEmbed(content, m[0]),
+			Angle:     angle,
+			Start:     linkStart(content, m[0]),
+			End:       m[1],
+			PathStart: start,
+			PathEnd:   start + len(pathPart),
+			SubStart:  subStart,
+			SubEnd:    subEnd,
+		})
+	}
+
+	sort.Slice(links, func(i, j int) bool { return links[i].Start < links[j].Start })
+	return links
+}
+
+// isEmbed reports whether the link starting at offset is preceded by "!".
+func isEmbed(content string, offset int) bool {
+	return offset > 0 && content[offset-1] == '!'
+}
+
+// linkStart moves a link's start offset back over an embed's "!".
+func linkStart(content string, offset int) int {
+	if isEmbed(content, offset) {
+		return offset - 1
+	}
+	return offset
+}
+
+// decodeLinkPart URL-decodes part of a markdown link, keeping it as written
+// if it is not valid percent-encoding.
+func decodeLinkPart(s string) string {
+	if decoded, err := url.PathUnescape(s); err == nil {
+		return decoded
+	}
+	return s
+}
+
+// ExtractLinks extracts the targets of all internal wikilinks and markdown
+// links from content, embeds included, without duplicates
+func ExtractLinks(content string) []string {
+	var links []string
+	seen := make(map[string]bool)
+	for _, link := range parseNoteLinks(content) {
+		if !seen[link.Target] {
+			links = append(links, link.Target)
+			seen[link.Target] = true
+		}
+	}
+	return links
+}
+
 // ExtractWikilinks extracts all wikilinks from content
 func ExtractWikilinks(content string) []string {
 	matches := wikilinkRegex.FindAllStringSubmatch(content, -1)
@@ -31,19 +170,45 @@ func ExtractWikilinks(content string) []string {
 	return links
 }
 
+// ExtractEmbeds extracts the targets of all ![[embeds]] and ![markdown](embeds)
+// from content, without duplicates
+func ExtractEmbeds(content string) []string {
+	var embeds []string
+	seen := make(map[string]bool)
+	for _, link := range parseNoteLinks(content) {
+		if link.Embed && !seen[link.Target] {
+			embeds = append(embeds, link.Target)
+			seen[link.Target] = true
+		}
+	}
+	return embeds
+}
+
+// linkedNoteNames returns the lowercased note names that links point at,
+// both as written and as bare file names, for "already linked" checks
+func linkedNoteNames(links []string) map[string]bool {
+	names := make(map[string]bool, len(links)*2)
+	for _, link := range links {
+		name := strings.ToLower(strings.TrimSuffix(normalizeNoteName(link), ".md"))
+		names[name] = true
+		names[filepath.Base(name)] = true
+	}
+	return names
+}
+
 // BacklinksHandler finds all notes that link to a given note
 func (v *Vault) BacklinksHandler(ctx context.Context, req *mcp.CallToolRequest, args GetBacklinksArgs) (*mcp.CallToolResult, any, error) {
 	target := args.Path
 
-	// Normalize target: remove .md extension for matching
-	targetName := strings.TrimSuffix(target, ".md")
-	targetBase := filepath.Base(targetName)
-
-	// Build regex patterns for wikilinks
-	// Matches: [[target]], [[target|alias]], [[path/to/target]], [[path/to/target|alias]]
-	patterns := []*regexp.Regexp{
-		regexp.MustCompile(`\[\[` + regexp.QuoteMeta(targetName) + `(\|[^\]]+)?\]\]`),
-		regexp.MustCompile(`\[\[` + regexp.QuoteMeta(targetBase) + `(\|[^\]]+)?\]\]`),
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search backlinks: %v", err)
+	}
+	// Resolve the target the same way a link to it would be, so "Note",
+	// "folder/Note" and "folder/Note.md" all find the same file.
+	targetPath, _ := resolver.resolvePath(target, "")
+	if targetPath == "" {
+		targetPath = target
 	}
 
 	type backlink struct {
@@ -54,42 +219,37 @@ func (v *Vault) BacklinksHandler(ctx context.Context, req *mcp.CallToolRequest,
 
 	var backlinks []backlink
 
-	err := filepath.Walk(v.GetPath(), func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
+	notes, err := v.indexedNotes(v.GetPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search backlinks: %v", err)
+	}
 
-		relPath, _ := filepath.Rel(v.GetPath(), path)
+	for _, note := range notes {
+		relPath := note.RelPath
 		// Skip the target note itself
-		if relPath == target || strings.TrimSuffix(relPath, ".md") == targetName {
-			return nil
+		if relPath == targetPath {
+			continue
 		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
+		if len(note.Links) == 0 {
+			continue
 		}
 
-		contentStr := string(content)
-		lines := strings.Split(contentStr, "\n")
-
 		var matches []string
 		matchCount := 0
 
-		for _, pattern := range patterns {
-			for i, line := range lines {
-				if pattern.MatchString(line) {
-					matchCount++
-					// Add context (truncated line)
-					ctxLine := strings.TrimSpace(line)
-					if len(ctxLine) > 100 {
-						ctxLine = ctxLine[:100] + "..."
-					}
-					matches = append(matches, fmt.Sprintf("L%d: %s", i+1, ctxLine))
+		for i, line := range note.Lines {
+			for _, link := range parseNoteLinks(line) {
+				if resolver.resolve(link.Target, relPath).Target != targetPath {
+					continue
+				}
+				matchCount++
+				// Add context (truncated line)
+				ctxLine := strings.TrimSpace(line)
+				if len(ctxLine) > 100 {
+					ctxLine = ctxLine[:100] + "..."
 				}
+				matches = append(matches, fmt.Sprintf("L%d: %s", i+1, ctxLine))
+				break
 			}
 		}
 
@@ -100,12 +260,6 @@ func (v *Vault) BacklinksHandler(ctx context.Context, req *mcp.CallToolRequest,
 				context: matches,
 			})
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to search backlinks: %v", err)
 	}
 
 	if len(backlinks) == 0 {
@@ -134,54 +288,6 @@ func (v *Vault) BacklinksHandler(ctx context.Context, req *mcp.CallToolRequest,
 	}, nil, nil
 }
 
-// updateLinksInVault updates wikilinks from oldName to newName across the vault
-func (v *Vault) updateLinksInVault(oldName, newName string) error {
-	// Prepare link patterns
-	// Handle [[oldName]] and [[oldName|alias]]
-	patterns := []struct {
-		pattern *regexp.Regexp
-		replace string
-	}{
-		{
-			pattern: regexp.MustCompile(`\[\[` + regexp.QuoteMeta(oldName) + `\]\]`),
-			replace: "[[" + newName + "]]",
-		},
-		{
-			pattern: regexp.MustCompile(`\[\[` + regexp.QuoteMeta(oldName) + `\|([^\]]+)\]\]`),
-			replace: "[[" + newName + "|$1]]",
-		},
-	}
-
-	return filepath.Walk(v.GetPath(), func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		contentStr := string(content)
-		newContent := contentStr
-
-		for _, r := range patterns {
-			newContent = r.pattern.ReplaceAllString(newContent, r.replace)
-		}
-
-		if newContent != contentStr {
-			if err := os.WriteFile(path, []byte(newContent), 0o600); err != nil {
-				return nil // Continue on error
-			}
-		}
-
-		return nil
-	})
-}
-
 // RenameNoteHandler renames a note and updates all links to it
 func (v *Vault) RenameNoteHandler(ctx context.Context, req *mcp.CallToolRequest, args RenameNoteArgs) (*mcp.CallToolResult, any, error) {
 	oldPath := args.OldPath
@@ -208,70 +314,8 @@ func (v *Vault) RenameNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 		return nil, nil, fmt.Errorf("destination already exists: %s", newPath)
 	}
 
-	// Prepare link patterns
-	oldName := strings.TrimSuffix(oldPath, ".md")
-	oldBase := strings.TrimSuffix(filepath.Base(oldPath), ".md")
-	newName := strings.TrimSuffix(newPath, ".md")
-	newBase := strings.TrimSuffix(filepath.Base(newPath), ".md")
-
-	// Patterns to find and replace
-	replacements := []struct {
-		pattern *regexp.Regexp
-		replace string
-	}{
-		{
-			pattern: regexp.MustCompile(`\[\[` + regexp.QuoteMeta(oldName) + `\]\]`),
-			replace: "[[" + newName + "]]",
-		},
-		{
-			pattern: regexp.MustCompile(`\[\[` + regexp.QuoteMeta(oldName) + `\|([^\]]+)\]\]`),
-			replace: "[[" + newName + "|$1]]",
-		},
-		{
-			pattern: regexp.MustCompile(`\[\[` + regexp.QuoteMeta(oldBase) + `\]\]`),
-			replace: "[[" + newBase + "]]",
-		},
-		{
-			pattern: regexp.MustCompile(`\[\[` + regexp.QuoteMeta(oldBase) + `\|([^\]]+)\]\]`),
-			replace: "[[" + newBase + "|$1]]",
-		},
-	}
-
-	// Update all notes that link to the old path
-	updatedFiles := 0
-	err := filepath.Walk(v.GetPath(), func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-		if path == oldFullPath {
-			return nil // Skip the file we're renaming
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		contentStr := string(content)
-		newContent := contentStr
-
-		for _, r := range replacements {
-			newContent = r.pattern.ReplaceAllString(newContent, r.replace)
-		}
-
-		if newContent != contentStr {
-			if err := os.WriteFile(path, []byte(newContent), 0o600); err != nil {
-				return nil // Continue on error
-			}
-			updatedFiles++
-		}
-
-		return nil
-	})
-
+	// Resolve links against the vault before anything moves
+	rewriter, err := v.newLinkRewriter()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to update links: %v", err)
 	}
@@ -287,27 +331,12 @@ func (v *Vault) RenameNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 		return nil, nil, fmt.Errorf("failed to rename note: %v", err)
 	}
 
+	// Update all notes that link to the old path
+	updatedFiles := rewriter.apply(map[string]string{filepath.Clean(oldPath): filepath.Clean(newPath)}, false)
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: fmt.Sprintf("Renamed %s -> %s\nUpdated links in %d files", oldPath, newPath, updatedFiles)},
 		},
 	}, nil, nil
 }
-
-// updateWikilinks replaces wikilinks from oldName to newName
-func updateWikilinks(content, oldName, newName string) string {
-	// Handle [[oldName]] and [[oldName|alias]]
-	patterns := []struct {
-		old string
-		new string
-	}{
-		{"[[" + oldName + "]]", "[[" + newName + "]]"},
-		{"[[" + oldName + "|", "[[" + newName + "|"},
-	}
-
-	for _, p := range patterns {
-		content = strings.ReplaceAll(content, p.old, p.new)
-	}
-
-	return content
-}
@@ -0,0 +1,337 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultLocalGraphLimit = 100
+
+// Direction bits recording how a local graph node was first reached.
+const (
+	reachedForward = 1 << iota
+	reachedBackward
+	reachedByTag
+)
+
+// localGraphNode is a note or tag in a local graph. Direction says how it
+// was reached from the layer before it: "outgoing" when that note links to
+// it, "incoming" when it links back, "both", or "tag" through a shared tag.
+type localGraphNode struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Depth     int    `json:"depth"`
+	Direction string `json:"direction"`
+	Incoming  int    `json:"incoming"`
+	Outgoing  int    `json:"outgoing"`
+}
+
+// localGraphEdge is a link, embed or tag edge between two local graph nodes.
+// Count is the number of separate links it stands for.
+type localGraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+	Count  int    `json:"count"`
+}
+
+// localGraph returns the nodes within depth hops of center, following edges
+// in both directions, and the edges between them. Tags are traversed like
+// notes, so notes sharing a tag with center are two hops away.
+func (g *exportGraph) localGraph(center string, depth int, embeds, tags bool) ([]localGraphNode, []localGraphEdge) {
+	var edges []exportEdge
+	for _, e := range g.Edges {
+		if e.Kind == "link" || (e.Kind == "embed" && embeds) || (e.Kind == "tag" && tags) {
+			edges = append(edges, e)
+		}
+	}
+	kinds := make(map[string]string, len(g.Nodes))
+	for _, n := range g.Nodes {
+		kinds[n.ID] = n.Kind
+	}
+	outEdges := make(map[string][]exportEdge)
+	inEdges := make(map[string][]exportEdge)
+	for _, e := range edges {
+		outEdges[e.Source] = append(outEdges[e.Source], e)
+		inEdges[e.Target] = append(inEdges[e.Target], e)
+	}
+
+	depths := map[string]int{center: 0}
+	reached := make(map[string]int)
+	frontier := []string{center}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		var next []string
+		visit := func(id string, how int) {
+			if prev, seen := depths[id]; seen && prev < d {
+				return
+			}
+			if _, seen := depths[id]; !seen {
+				depths[id] = d
+				next = append(next, id)
+			}
+			reached[id] |= how
+		}
+		for _, u := range frontier {
+			for _, e := range outEdges[u] {
+				visit(e.Target, edgeReach(e, reachedForward))
+			}
+			for _, e := range inEdges[u] {
+				visit(e.Source, edgeReach(e, reachedBackward))
+			}
+		}
+		frontier = next
+	}
+
+	var nodes []localGraphNode
+	for id, d := range depths {
+		node := localGraphNode{ID: id, Kind: kinds[id], Depth: d, Direction: reachDirection(reached[id])}
+		if d == 0 {
+			node.Direction = "center"
+		}
+		for _, e := range inEdges[id] {
+			node.Incoming += e.Weight
+		}
+		for _, e := range outEdges[id] {
+			if e.Kind != "tag" {
+				node.Outgoing += e.Weight
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Depth != nodes[j].Depth {
+			return nodes[i].Depth < nodes[j].Depth
+		}
+		if nodes[i].Kind != nodes[j].Kind {
+			return nodes[i].Kind == "note"
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	local := []localGraphEdge{}
+	for _, e := range edges {
+		if _, ok := depths[e.Source]; !ok {
+			continue
+		}
+		if _, ok := depths[e.Target]; ok {
+			local = append(local, localGraphEdge{Source: e.Source, Target: e.Target, Kind: e.Kind, Count: e.Weight})
+		}
+	}
+	return nodes, local
+}
+
+// edgeReach is the direction bit for reaching a node over e.
+func edgeReach(e exportEdge, direction int) int {
+	if e.Kind == "tag" {
+		return reachedByTag
+	}
+	return direction
+}
+
+func reachDirection(reached int) string {
+	switch {
+	case reached&reachedForward != 0 && reached&reachedBackward != 0:
+		return "both"
+	case reached&reachedForward != 0:
+		return "outgoing"
+	case reached&reachedBackward != 0:
+		return "incoming"
+	default:
+		return "tag"
+	}
+}
+
+// localGraphCanvas lays a local graph out as a canvas: the center note in
+// the middle and each further depth on a wider ring around it. Notes become
+// file nodes and tags text nodes.
+func localGraphCanvas(nodes []localGraphNode, edges []localGraphEdge) Canvas {
+	canvas := Canvas{Nodes: []CanvasNode{}, Edges: []CanvasEdge{}}
+	rings := make(map[int][]localGraphNode)
+	for _, n := range nodes {
+		rings[n.Depth] = append(rings[n.Depth], n)
+	}
+
+	ids := make(map[string]string, len(nodes))
+	for depth := 0; len(rings[depth]) > 0; depth++ {
+		ring := rings[depth]
+		radius := float64(depth) * 600
+		for i, n := range ring {
+			angle := 2 * math.Pi * float64(i) / float64(len(ring))
+			node := CanvasNode{ID: fmt.Sprintf("node-%d", len(canvas.Nodes)+1), Type: "file", File: n.ID, Width: 400, Height: 240}
+			switch {
+			case n.Kind == "tag":
+				node.Type, node.File, node.Text, node.Width, node.Height, node.Color = "text", "", n.ID, 200, 60, "6"
+			case depth == 0:
+				node.Color = "4"
+			}
+			node.X = int(math.Round(radius*math.Cos(angle))) - node.Width/2
+			node.Y = int(math.Round(radius*math.Sin(angle))) - node.Height/2
+			ids[n.ID] = node.ID
+			canvas.Nodes = append(canvas.Nodes, node)
+		}
+	}
+
+	for _, e := range edges {
+		edge := CanvasEdge{ID: fmt.Sprintf("edge-%d", len(canvas.Edges)+1), FromNode: ids[e.Source], ToNode: ids[e.Target]}
+		if e.Kind == "embed" {
+			edge.Label = "embed"
+		}
+		canvas.Edges = append(canvas.Edges, edge)
+	}
+	return canvas
+}
+
+// LocalGraphHandler returns the notes and tags around a note, like
+// Obsidian's local graph, optionally writing it to a canvas
+func (v *Vault) LocalGraphHandler(ctx context.Context, req *mcp.CallToolRequest, args LocalGraphArgs) (*mcp.CallToolResult, any, error) {
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve note: %v", err)
+	}
+	notePath, _ := resolver.resolvePath(args.Path, "")
+	if notePath == "" {
+		return nil, nil, fmt.Errorf("note not found: %s", args.Path)
+	}
+
+	graph, err := v.buildExportGraph(v.GetPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build graph: %v", err)
+	}
+	depth := args.Depth
+	if depth <= 0 {
+		depth = 1
+	}
+	nodes, edges := graph.localGraph(notePath, depth, args.IncludeEmbeds, !args.ExcludeTags)
+
+	total := len(nodes)
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultLocalGraphLimit
+	}
+	truncated := total > limit
+	if truncated {
+		nodes = nodes[:limit]
+		kept := make(map[string]bool, len(nodes))
+		for _, n := range nodes {
+			kept[n.ID] = true
+		}
+		keptEdges := []localGraphEdge{}
+		for _, e := range edges {
+			if kept[e.Source] && kept[e.Target] {
+				keptEdges = append(keptEdges, e)
+			}
+		}
+		edges = keptEdges
+	}
+	summary := fmt.Sprintf("Local graph of %s: %d nodes and %d edges within %d hops", notePath, len(nodes), len(edges), depth)
+	var canvasPath string
+	if args.Canvas != "" {
+		canvasPath, err = v.writeLocalGraphCanvas(args.Canvas, args.Overwrite, nodes, edges)
+		if err != nil {
+			return nil, nil, err
+		}
+		summary += fmt.Sprintf(", written to %s", canvasPath)
+	}
+
+	if !isDetailedMode(args.Mode) {
+		data := map[string]any{
+			"path":  notePath,
+			"depth": depth,
+			"total": total,
+			"nodes": nodes,
+			"edges": edges,
+		}
+		if canvasPath != "" {
+			data["canvas"] = canvasPath
+		}
+		return compactResult(summary, truncated, data, nil)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatLocalGraph(summary, nodes, edges)},
+		},
+	}, nil, nil
+}
+
+// writeLocalGraphCanvas renders a local graph to a canvas file in the
+// vault. An existing file is only replaced when overwrite is set, so a
+// hand-made canvas is never lost by accident.
+func (v *Vault) writeLocalGraphCanvas(canvasPath string, overwrite bool, nodes []localGraphNode, edges []localGraphEdge) (string, error) {
+	if !strings.HasSuffix(canvasPath, ".canvas") {
+		canvasPath += ".canvas"
+	}
+	fullPath := filepath.Join(v.GetPath(), canvasPath)
+	if !v.isPathSafe(fullPath) {
+		return "", fmt.Errorf("canvas path must be within vault")
+	}
+
+	data, err := json.MarshalIndent(localGraphCanvas(nodes, edges), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to create canvas: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %v", err)
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(fullPath, flags, 0o600)
+	if err != nil {
+		if os.IsExist(err) {
+			return "", fmt.Errorf("canvas already exists: %s (pass overwrite to replace it)", canvasPath)
+		}
+		return "", fmt.Errorf("failed to write canvas: %v", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write canvas: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write canvas: %v", err)
+	}
+	return canvasPath, nil
+}
+
+// formatLocalGraph formats a local graph as markdown, one section per depth
+func formatLocalGraph(summary string, nodes []localGraphNode, edges []localGraphEdge) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", summary)
+	for i, n := range nodes {
+		if n.Depth == 0 {
+			continue
+		}
+		if nodes[i-1].Depth != n.Depth {
+			fmt.Fprintf(&sb, "\n## Depth %d\n", n.Depth)
+		}
+		if n.Kind == "tag" {
+			fmt.Fprintf(&sb, "- %s (%d notes)\n", n.ID, n.Incoming)
+			continue
+		}
+		fmt.Fprintf(&sb, "- [[%s]] %s (%d in, %d out)\n", strings.TrimSuffix(n.ID, ".md"), n.Direction, n.Incoming, n.Outgoing)
+	}
+
+	if len(edges) > 0 {
+		fmt.Fprintf(&sb, "\n## Edges (%d)\n", len(edges))
+		for _, e := range edges {
+			fmt.Fprintf(&sb, "- %s → %s", e.Source, e.Target)
+			if e.Kind == "embed" {
+				sb.WriteString(" (embed)")
+			}
+			if e.Count > 1 {
+				fmt.Fprintf(&sb, " ×%d", e.Count)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
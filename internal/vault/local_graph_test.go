@@ -0,0 +1,107 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestLocalGraph(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "Hub.md", "[[Out]] [[Out#Part]] [[Mutual]] ![[Embedded]] #topic\n")
+	writeTestFile(t, dir, "Out.md", "[[Far]]\n")
+	writeTestFile(t, dir, "Mutual.md", "[[Hub]]\n")
+	writeTestFile(t, dir, "In.md", "Points at [[Hub]]\n")
+	writeTestFile(t, dir, "Embedded.md", "content\n")
+	writeTestFile(t, dir, "Far.md", "end\n")
+	writeTestFile(t, dir, "Peer.md", "#topic\n")
+	ctx := context.Background()
+
+	var data struct {
+		Nodes []localGraphNode `json:"nodes"`
+		Edges []localGraphEdge `json:"edges"`
+	}
+	result, _, err := v.LocalGraphHandler(ctx, nil, LocalGraphArgs{Path: "Hub"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compactData(t, result, &data)
+	var nodes []string
+	for _, n := range data.Nodes {
+		nodes = append(nodes, fmt.Sprintf("%s:%d:%s", n.ID, n.Depth, n.Direction))
+	}
+	want := "Hub.md:0:center In.md:1:incoming Mutual.md:1:both Out.md:1:outgoing #topic:1:tag"
+	if got := strings.Join(nodes, " "); got != want {
+		t.Errorf("nodes = %s\nwant %s", got, want)
+	}
+	var edges []string
+	for _, e := range data.Edges {
+		edges = append(edges, fmt.Sprintf("%s-%s->%s(%d)", e.Source, e.Kind, e.Target, e.Count))
+	}
+	if got := strings.Join(edges, " "); got != "Hub.md-tag->#topic(1) Hub.md-link->Mutual.md(1) Hub.md-link->Out.md(2) In.md-link->Hub.md(1) Mutual.md-link->Hub.md(1)" {
+		t.Errorf("edges = %s", got)
+	}
+	if hub := data.Nodes[0]; hub.Incoming != 2 || hub.Outgoing != 3 {
+		t.Errorf("unexpected hub counts: %+v", hub)
+	}
+
+	result, _, err = v.LocalGraphHandler(ctx, nil, LocalGraphArgs{Path: "Hub", Depth: 2, IncludeEmbeds: true, ExcludeTags: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compactData(t, result, &data)
+	nodes = nil
+	for _, n := range data.Nodes {
+		nodes = append(nodes, fmt.Sprintf("%s:%d", n.ID, n.Depth))
+	}
+	if got := strings.Join(nodes, " "); got != "Hub.md:0 Embedded.md:1 In.md:1 Mutual.md:1 Out.md:1 Far.md:2" {
+		t.Errorf("nodes = %s", got)
+	}
+
+	result, _, err = v.LocalGraphHandler(ctx, nil, LocalGraphArgs{Path: "Hub", Depth: 2, Canvas: "graphs/hub", Mode: modeDetailed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"written to graphs/hub.canvas", "## Depth 2", "- [[Peer]] tag", "- [[Mutual]] both (1 in, 1 out)", "- Hub.md → Out.md ×2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("detailed output missing %q:\n%s", want, text)
+		}
+	}
+
+	var canvas Canvas
+	if err := json.Unmarshal([]byte(readTestFile(t, dir, "graphs/hub.canvas")), &canvas); err != nil {
+		t.Fatal(err)
+	}
+	if len(canvas.Nodes) != 7 || canvas.Nodes[0].File != "Hub.md" || canvas.Nodes[0].X != -200 {
+		t.Errorf("unexpected canvas nodes: %+v", canvas.Nodes)
+	}
+	ids := make(map[string]bool)
+	for _, n := range canvas.Nodes {
+		ids[n.ID] = true
+	}
+	for _, e := range canvas.Edges {
+		if !ids[e.FromNode] || !ids[e.ToNode] {
+			t.Errorf("edge %+v points at a missing node", e)
+		}
+	}
+
+	writeTestFile(t, dir, "graphs/mine.canvas", `{"nodes":[],"edges":[]}`)
+	if _, _, err := v.LocalGraphHandler(ctx, nil, LocalGraphArgs{Path: "Hub", Canvas: "graphs/mine"}); err == nil {
+		t.Error("expected an existing canvas to be refused without overwrite")
+	}
+	if got := readTestFile(t, dir, "graphs/mine.canvas"); got != `{"nodes":[],"edges":[]}` {
+		t.Errorf("existing canvas was modified: %s", got)
+	}
+	if _, _, err := v.LocalGraphHandler(ctx, nil, LocalGraphArgs{Path: "Hub", Canvas: "graphs/hub", Overwrite: true}); err != nil {
+		t.Errorf("overwrite should replace the canvas: %v", err)
+	}
+
+	if _, _, err := v.LocalGraphHandler(ctx, nil, LocalGraphArgs{Path: "Missing"}); err == nil {
+		t.Error("expected missing note to fail")
+	}
+}
@@ -59,7 +59,7 @@ func (v *Vault) DiscoverMOCsHandler(ctx context.Context, req *mcp.CallToolReques
 
 	var mocs []MOC
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+	err := v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
@@ -87,7 +87,7 @@ func (v *Vault) DiscoverMOCsHandler(ctx context.Context, req *mcp.CallToolReques
 			Path:        relPath,
 			Title:       title,
 			Tags:        ExtractTags(contentStr),
-			LinkedNotes: ExtractWikilinks(contentStr),
+			LinkedNotes: ExtractLinks(contentStr),
 		})
 
 		return nil
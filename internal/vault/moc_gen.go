@@ -70,13 +70,13 @@ func (v *Vault) collectNotes(dir string, recursive bool) ([]noteInfo, error) {
 			name:     name,
 			title:    title,
 			tags:     ExtractTags(contentStr),
-			hasLinks: len(ExtractWikilinks(contentStr)) > 0,
+			hasLinks: len(ExtractLinks(contentStr)) > 0,
 		})
 
 		return nil
 	}
 
-	if err := filepath.Walk(searchPath, walkFn); err != nil {
+	if err := v.walkVault(searchPath, walkFn); err != nil {
 		return nil, err
 	}
 
@@ -275,11 +275,7 @@ func (v *Vault) UpdateMOCHandler(ctx context.Context, req *mcp.CallToolRequest,
 		return nil, nil, fmt.Errorf("failed to read MOC: %v", err)
 	}
 
-	existingLinks := ExtractWikilinks(string(content))
-	existingSet := make(map[string]bool)
-	for _, link := range existingLinks {
-		existingSet[strings.ToLower(link)] = true
-	}
+	existingSet := linkedNoteNames(ExtractLinks(string(content)))
 
 	notes, err := v.collectNotes(dir, recursive)
 	if err != nil {
@@ -28,6 +28,9 @@ type ManageNotesMultiplexArgs struct {
 	Limit         int    `json:"limit,omitempty" jsonschema:"Maximum number of notes to return (for list action, 0 = no limit)"`
 	Offset        int    `json:"offset,omitempty" jsonschema:"Number of notes to skip for pagination (for list action, default 0)"`
 	Mode          string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
+	RenderQueries bool   `json:"render_queries,omitempty" jsonschema:"Replace dataview and tasks code blocks with their rendered results (for read action)"`
+	ResolveEmbeds bool   `json:"resolve_embeds,omitempty" jsonschema:"Inline embedded notes, sections and blocks, and list embedded attachments (for read action)"`
+	EmbedDepth    int    `json:"embed_depth,omitempty" jsonschema:"How many levels of nested embeds to inline (for read action, default 3, max 10)"`
 }
 
 // ManageNotesMultiplexHandler routes to the specific handler
@@ -35,7 +38,10 @@ func (v *Vault) ManageNotesMultiplexHandler(ctx context.Context, req *mcp.CallTo
 	switch args.Action {
 	case "read":
 		specificArgs := ReadNoteArgs{
-			Path: args.Path,
+			Path:          args.Path,
+			RenderQueries: args.RenderQueries,
+			ResolveEmbeds: args.ResolveEmbeds,
+			EmbedDepth:    args.EmbedDepth,
 		}
 		return v.ReadNoteHandler(ctx, req, specificArgs)
 	case "write":
@@ -98,17 +104,20 @@ func (v *Vault) ManageNotesMultiplexHandler(ctx context.Context, req *mcp.CallTo
 
 // EditNoteMultiplexArgs multiplexed args
 type EditNoteMultiplexArgs struct {
-	Action        string      `json:"action" jsonschema:"Action to perform: 'edit', 'replace-section', 'batch-edit'"`
+	Action        string      `json:"action" jsonschema:"Action to perform: 'edit', 'replace-section', 'batch-edit', 'add-block-id', 'rename-heading'"`
 	Path          string      `json:"path,omitempty" jsonschema:"Path to the note"`
 	OldText       string      `json:"old_text,omitempty" jsonschema:"Text to find and replace"`
 	NewText       string      `json:"new_text,omitempty" jsonschema:"Replacement text"`
 	ReplaceAll    bool        `json:"replace_all,omitempty" jsonschema:"Whether to replace all occurrences (default false)"`
 	ContextLines  int         `json:"context_lines,omitempty" jsonschema:"Number of context lines to return (default 0)"`
 	ExpectedMtime string      `json:"expected_mtime,omitempty" jsonschema:"Expected file modification time (RFC3339Nano) for optimistic concurrency"`
-	Heading       string      `json:"heading,omitempty" jsonschema:"Heading of the section to replace"`
+	Heading       string      `json:"heading,omitempty" jsonschema:"Heading of the section to replace, or to rename"`
 	Content       string      `json:"content,omitempty" jsonschema:"New content for the section"`
 	Edits         []EditEntry `json:"edits,omitempty" jsonschema:"List of edits to apply"`
 	DryRun        bool        `json:"dry_run,omitempty" jsonschema:"Preview edits without modifying files"`
+	Text          string      `json:"text,omitempty" jsonschema:"Text on the line to mark with a block ID (for add-block-id action)"`
+	BlockID       string      `json:"block_id,omitempty" jsonschema:"Block ID to add (for add-block-id action; default generated)"`
+	NewHeading    string      `json:"new_heading,omitempty" jsonschema:"New heading text (for rename-heading action)"`
 }
 
 // EditNoteMultiplexHandler routes to the specific handler
@@ -142,6 +151,23 @@ func (v *Vault) EditNoteMultiplexHandler(ctx context.Context, req *mcp.CallToolR
 			ExpectedMtime: args.ExpectedMtime,
 		}
 		return v.BatchEditNoteHandler(ctx, req, specificArgs)
+	case "add-block-id":
+		specificArgs := AddBlockIDArgs{
+			Path:          args.Path,
+			Text:          args.Text,
+			BlockID:       args.BlockID,
+			ExpectedMtime: args.ExpectedMtime,
+		}
+		return v.AddBlockIDHandler(ctx, req, specificArgs)
+	case "rename-heading":
+		specificArgs := RenameHeadingArgs{
+			Path:          args.Path,
+			Heading:       args.Heading,
+			NewHeading:    args.NewHeading,
+			DryRun:        args.DryRun,
+			ExpectedMtime: args.ExpectedMtime,
+		}
+		return v.RenameHeadingHandler(ctx, req, specificArgs)
 	default:
 		return nil, nil, fmt.Errorf("unknown action: %s", args.Action)
 	}
@@ -149,13 +175,15 @@ func (v *Vault) EditNoteMultiplexHandler(ctx context.Context, req *mcp.CallToolR
 
 // SearchVaultMultiplexArgs multiplexed args
 type SearchVaultMultiplexArgs struct {
-	Action          string `json:"action" jsonschema:"Action to perform: 'search', 'advanced', 'date', 'regex', 'tags', 'headings', 'inline-fields', 'frontmatter'"`
-	Query           string `json:"query,omitempty" jsonschema:"Search query"`
+	Action          string `json:"action" jsonschema:"Action to perform: 'search', 'ranked', 'semantic', 'similar-notes', 'query', 'dataview', 'advanced', 'date', 'regex', 'tags', 'headings', 'inline-fields', 'frontmatter'"`
+	Query           string `json:"query,omitempty" jsonschema:"Search query (for 'semantic': a description in your own words; for 'query': Obsidian search syntax such as tag:#x path:work/ \"phrase\" -draft (a OR b) line:(..) section:(..) task-todo:(..); for 'dataview': a DQL query such as TABLE status FROM #project WHERE due < date(today) SORT due; for 'frontmatter': priority>=2 AND status!=done AND tags contains \"client\")"`
+	Path            string `json:"path,omitempty" jsonschema:"Note to find similar notes for (similar-notes)"`
 	Directory       string `json:"directory,omitempty" jsonschema:"Directory to limit search to"`
 	Mode            string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
 	SearchIn        string `json:"in,omitempty" jsonschema:"Where to search: 'content' (default), 'file', 'heading', 'block'"`
 	Operator        string `json:"operator,omitempty" jsonschema:"Logical operator: 'and' (default), 'or'"`
-	Limit           int    `json:"limit,omitempty" jsonschema:"Maximum results to return (default 50)"`
+	Limit           int    `json:"limit,omitempty" jsonschema:"Maximum results to return (default 50; 20 for ranked and semantic; 10 for similar-notes)"`
+	Offset          int    `json:"offset,omitempty" jsonschema:"Number of results to skip (ranked and query)"`
 	From            string `json:"from,omitempty" jsonschema:"Start date (YYYY-MM-DD)"`
 	To              string `json:"to,omitempty" jsonschema:"End date (YYYY-MM-DD)"`
 	DateType        string `json:"type,omitempty" jsonschema:"Date type to check: 'modified' (default), 'created'"`
@@ -165,6 +193,7 @@ type SearchVaultMultiplexArgs struct {
 	Level           int    `json:"level,omitempty" jsonschema:"Heading level to filter (0 for all)"`
 	Key             string `json:"key,omitempty" jsonschema:"Field key"`
 	Value           string `json:"value,omitempty" jsonschema:"Field value to match (optional)"`
+	Sort            string `json:"sort,omitempty" jsonschema:"Frontmatter key to sort by for 'frontmatter'; append ' desc' or prefix '-' for descending"`
 }
 
 // SearchVaultMultiplexHandler routes to the specific handler
@@ -177,6 +206,46 @@ func (v *Vault) SearchVaultMultiplexHandler(ctx context.Context, req *mcp.CallTo
 			Mode:      args.Mode,
 		}
 		return v.SearchVaultHandler(ctx, req, specificArgs)
+	case "ranked":
+		specificArgs := SearchRankedArgs{
+			Query:     args.Query,
+			Directory: args.Directory,
+			Limit:     args.Limit,
+			Offset:    args.Offset,
+			Mode:      args.Mode,
+		}
+		return v.SearchRankedHandler(ctx, req, specificArgs)
+	case "semantic":
+		specificArgs := SemanticSearchArgs{
+			Query:     args.Query,
+			Directory: args.Directory,
+			Limit:     args.Limit,
+			Mode:      args.Mode,
+		}
+		return v.SemanticSearchHandler(ctx, req, specificArgs)
+	case "similar-notes":
+		specificArgs := SimilarNotesArgs{
+			Path:      args.Path,
+			Directory: args.Directory,
+			Limit:     args.Limit,
+			Mode:      args.Mode,
+		}
+		return v.SimilarNotesHandler(ctx, req, specificArgs)
+	case "query":
+		specificArgs := SearchQueryArgs{
+			Query:     args.Query,
+			Directory: args.Directory,
+			Limit:     args.Limit,
+			Offset:    args.Offset,
+			Mode:      args.Mode,
+		}
+		return v.SearchQueryHandler(ctx, req, specificArgs)
+	case "dataview":
+		specificArgs := DataviewArgs{
+			Query: args.Query,
+			Mode:  args.Mode,
+		}
+		return v.DataviewHandler(ctx, req, specificArgs)
 	case "advanced":
 		specificArgs := SearchAdvancedArgs{
 			Query:     args.Query,
@@ -230,6 +299,7 @@ func (v *Vault) SearchVaultMultiplexHandler(ctx context.Context, req *mcp.CallTo
 		specificArgs := QueryFrontmatterArgs{
 			Query:     args.Query,
 			Directory: args.Directory,
+			Sort:      args.Sort,
 		}
 		return v.QueryFrontmatterHandler(ctx, req, specificArgs)
 	default:
@@ -241,8 +311,8 @@ func (v *Vault) SearchVaultMultiplexHandler(ctx context.Context, req *mcp.CallTo
 type ManagePeriodicNotesMultiplexArgs struct {
 	Action          string `json:"action" jsonschema:"Action to perform: 'daily', 'weekly', 'monthly', 'quarterly', 'yearly', 'list-daily', 'list-periodic'"`
 	Date            string `json:"date,omitempty" jsonschema:"Date string (default: today)"`
-	Folder          string `json:"folder,omitempty" jsonschema:"Folder for daily notes (default: 'daily')"`
-	Format          string `json:"format,omitempty" jsonschema:"Date format (default: '2006-01-02')"`
+	Folder          string `json:"folder,omitempty" jsonschema:"Folder for daily notes (default: from Obsidian settings, else 'daily')"`
+	Format          string `json:"format,omitempty" jsonschema:"Go date layout (default: the Obsidian format setting, else '2006-01-02')"`
 	CreateIfMissing bool   `json:"create,omitempty" jsonschema:"Create if missing (default: true)"`
 	Type            string `json:"type,omitempty" jsonschema:"Type of note: 'daily', 'weekly', 'monthly', 'quarterly', 'yearly'"`
 	Limit           int    `json:"limit,omitempty" jsonschema:"Maximum number of notes to return"`
@@ -346,13 +416,15 @@ func (v *Vault) ManageFoldersMultiplexHandler(ctx context.Context, req *mcp.Call
 
 // ManageFrontmatterMultiplexArgs multiplexed args
 type ManageFrontmatterMultiplexArgs struct {
-	Action        string `json:"action" jsonschema:"Action to perform: 'get', 'set', 'remove', 'add-alias', 'add-tag', 'get-inline-fields', 'set-inline-field'"`
+	Action        string `json:"action" jsonschema:"Action to perform: 'get', 'set', 'remove', 'add-alias', 'add-tag', 'get-inline-fields', 'set-inline-field', 'list-properties'"`
 	Path          string `json:"path,omitempty" jsonschema:"Path to the note"`
 	Key           string `json:"key,omitempty" jsonschema:"Frontmatter key"`
-	Value         string `json:"value,omitempty" jsonschema:"Value to set"`
+	Value         string `json:"value,omitempty" jsonschema:"Value to set; JSON arrays and objects are stored as YAML lists and maps"`
 	ExpectedMtime string `json:"expected_mtime,omitempty" jsonschema:"Expected file modification time (RFC3339Nano) for optimistic concurrency"`
 	Alias         string `json:"alias,omitempty" jsonschema:"Alias to add"`
 	Tag           string `json:"tag,omitempty" jsonschema:"Tag to add"`
+	Directory     string `json:"directory,omitempty" jsonschema:"Directory to limit list-properties to"`
+	Mode          string `json:"mode,omitempty" jsonschema:"Response mode for list-properties: compact (default) or detailed"`
 }
 
 // ManageFrontmatterMultiplexHandler routes to the specific handler
@@ -405,6 +477,12 @@ func (v *Vault) ManageFrontmatterMultiplexHandler(ctx context.Context, req *mcp.
 			ExpectedMtime: args.ExpectedMtime,
 		}
 		return v.SetInlineFieldHandler(ctx, req, specificArgs)
+	case "list-properties":
+		specificArgs := ListPropertiesArgs{
+			Directory: args.Directory,
+			Mode:      args.Mode,
+		}
+		return v.ListPropertiesHandler(ctx, req, specificArgs)
 	default:
 		return nil, nil, fmt.Errorf("unknown action: %s", args.Action)
 	}
@@ -412,8 +490,8 @@ func (v *Vault) ManageFrontmatterMultiplexHandler(ctx context.Context, req *mcp.
 
 // ManageTasksMultiplexArgs multiplexed args
 type ManageTasksMultiplexArgs struct {
-	Action        string `json:"action" jsonschema:"Action to perform: 'list', 'toggle', 'complete'"`
-	Status        string `json:"status,omitempty" jsonschema:"Filter by status: 'all' (default), 'open', 'completed'"`
+	Action        string `json:"action" jsonschema:"Action to perform: 'list', 'toggle', 'complete', 'query'"`
+	Status        string `json:"status,omitempty" jsonschema:"Filter by status: 'all' (default), 'open', 'completed', or a status type: 'todo', 'in_progress', 'done', 'cancelled', 'non_task'"`
 	Directory     string `json:"directory,omitempty" jsonschema:"Directory to limit search to"`
 	Limit         int    `json:"limit,omitempty" jsonschema:"Maximum tasks to return (default: all in detailed mode, 100 in compact mode)"`
 	Mode          string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
@@ -422,6 +500,7 @@ type ManageTasksMultiplexArgs struct {
 	Text          string `json:"text,omitempty" jsonschema:"Text to match the task (partial match, alternative to line number)"`
 	ExpectedMtime string `json:"expected_mtime,omitempty" jsonschema:"Expected file modification time (RFC3339Nano) for optimistic concurrency"`
 	Texts         string `json:"texts,omitempty" jsonschema:"Comma-separated list or JSON array of task text snippets to mark complete"`
+	Query         string `json:"query,omitempty" jsonschema:"Tasks query block for 'query', one instruction per line, e.g. not done / due before tomorrow / group by due / limit 20"`
 }
 
 // ManageTasksMultiplexHandler routes to the specific handler
@@ -450,6 +529,13 @@ func (v *Vault) ManageTasksMultiplexHandler(ctx context.Context, req *mcp.CallTo
 			ExpectedMtime: args.ExpectedMtime,
 		}
 		return v.CompleteTasksHandler(ctx, req, specificArgs)
+	case "query":
+		specificArgs := TasksQueryArgs{
+			Query:     args.Query,
+			Directory: args.Directory,
+			Mode:      args.Mode,
+		}
+		return v.TasksQueryHandler(ctx, req, specificArgs)
 	default:
 		return nil, nil, fmt.Errorf("unknown action: %s", args.Action)
 	}
@@ -457,13 +543,21 @@ func (v *Vault) ManageTasksMultiplexHandler(ctx context.Context, req *mcp.CallTo
 
 // AnalyzeVaultMultiplexArgs multiplexed args
 type AnalyzeVaultMultiplexArgs struct {
-	Action          string `json:"action" jsonschema:"Action to perform: 'stats', 'broken-links', 'orphan-notes', 'unlinked-mentions', 'find-stubs', 'find-outdated'"`
+	Action          string `json:"action" jsonschema:"Action to perform: 'stats', 'broken-links', 'orphan-notes', 'unlinked-mentions', 'find-stubs', 'find-outdated', 'export-graph', 'centrality', 'components', 'communities', 'shortest-path', 'neighborhood'"`
 	Directory       string `json:"directory,omitempty" jsonschema:"Directory to analyze"`
 	IncludeDeadEnds bool   `json:"include_no_outgoing,omitempty" jsonschema:"Include notes with no outgoing links (dead ends)"`
-	Path            string `json:"path,omitempty" jsonschema:"Path to the note to find unlinked mentions of"`
+	Path            string `json:"path,omitempty" jsonschema:"Note to find unlinked mentions of, or the center for neighborhood"`
 	MaxWords        int    `json:"max_words,omitempty" jsonschema:"Maximum word count to qualify as stub (default 100)"`
 	Limit           int    `json:"limit,omitempty" jsonschema:"Maximum results (default 50)"`
 	Days            int    `json:"days,omitempty" jsonschema:"Days since modification to qualify as outdated (default 90)"`
+	Format          string `json:"format,omitempty" jsonschema:"Graph format for export-graph: 'json' (node-link, default), 'graphml', 'gexf' or 'dot'"`
+	Output          string `json:"output,omitempty" jsonschema:"Vault path to write the exported graph to (default: return it)"`
+	Metric          string `json:"metric,omitempty" jsonschema:"Centrality measure: 'pagerank' (default) or 'betweenness'"`
+	Source          string `json:"source,omitempty" jsonschema:"Note to start from for shortest-path"`
+	Target          string `json:"target,omitempty" jsonschema:"Note to reach for shortest-path"`
+	Directed        bool   `json:"directed,omitempty" jsonschema:"For shortest-path, only follow links forward"`
+	Depth           int    `json:"depth,omitempty" jsonschema:"Maximum hops for neighborhood (default 1)"`
+	Mode            string `json:"mode,omitempty" jsonschema:"Response mode for graph analytics: compact (default) or detailed"`
 }
 
 // AnalyzeVaultMultiplexHandler routes to the specific handler
@@ -504,6 +598,53 @@ func (v *Vault) AnalyzeVaultMultiplexHandler(ctx context.Context, req *mcp.CallT
 			Limit:     args.Limit,
 		}
 		return v.FindOutdatedHandler(ctx, req, specificArgs)
+	case "export-graph":
+		specificArgs := ExportGraphArgs{
+			Format:    args.Format,
+			Directory: args.Directory,
+			Output:    args.Output,
+		}
+		return v.ExportGraphHandler(ctx, req, specificArgs)
+	case "centrality":
+		specificArgs := GraphCentralityArgs{
+			Directory: args.Directory,
+			Metric:    args.Metric,
+			Limit:     args.Limit,
+			Mode:      args.Mode,
+		}
+		return v.GraphCentralityHandler(ctx, req, specificArgs)
+	case "components":
+		specificArgs := GraphClustersArgs{
+			Directory: args.Directory,
+			Limit:     args.Limit,
+			Mode:      args.Mode,
+		}
+		return v.GraphComponentsHandler(ctx, req, specificArgs)
+	case "communities":
+		specificArgs := GraphClustersArgs{
+			Directory: args.Directory,
+			Limit:     args.Limit,
+			Mode:      args.Mode,
+		}
+		return v.GraphCommunitiesHandler(ctx, req, specificArgs)
+	case "shortest-path":
+		specificArgs := ShortestPathArgs{
+			Source:    args.Source,
+			Target:    args.Target,
+			Directory: args.Directory,
+			Directed:  args.Directed,
+			Mode:      args.Mode,
+		}
+		return v.ShortestPathHandler(ctx, req, specificArgs)
+	case "neighborhood":
+		specificArgs := NeighborhoodArgs{
+			Path:      args.Path,
+			Depth:     args.Depth,
+			Directory: args.Directory,
+			Limit:     args.Limit,
+			Mode:      args.Mode,
+		}
+		return v.NeighborhoodHandler(ctx, req, specificArgs)
 	default:
 		return nil, nil, fmt.Errorf("unknown action: %s", args.Action)
 	}
@@ -623,12 +764,14 @@ func (v *Vault) ManageMocsMultiplexHandler(ctx context.Context, req *mcp.CallToo
 
 // ReadBatchMultiplexArgs multiplexed args
 type ReadBatchMultiplexArgs struct {
-	Action             string `json:"action" jsonschema:"Action to perform: 'read', 'get-section', 'get-headings', 'get-summary'"`
+	Action             string `json:"action" jsonschema:"Action to perform: 'read', 'get-section', 'get-block', 'get-headings', 'get-summary'"`
 	Paths              string `json:"paths,omitempty" jsonschema:"Comma-separated list or JSON array of paths"`
 	IncludeFrontmatter bool   `json:"include_frontmatter,omitempty" jsonschema:"Include frontmatter in output (default true)"`
 	Path               string `json:"path,omitempty" jsonschema:"Path to the note"`
 	Heading            string `json:"heading,omitempty" jsonschema:"Heading to extract"`
 	Lines              int    `json:"lines,omitempty" jsonschema:"Number of preview lines (default 5)"`
+	RenderQueries      bool   `json:"render_queries,omitempty" jsonschema:"Replace dataview and tasks code blocks with their rendered results (for read action)"`
+	BlockID            string `json:"block_id,omitempty" jsonschema:"Block ID to extract, with or without ^ (for get-block action)"`
 }
 
 // ReadBatchMultiplexHandler routes to the specific handler
@@ -638,6 +781,7 @@ func (v *Vault) ReadBatchMultiplexHandler(ctx context.Context, req *mcp.CallTool
 		specificArgs := ReadNotesArgs{
 			Paths:              args.Paths,
 			IncludeFrontmatter: args.IncludeFrontmatter,
+			RenderQueries:      args.RenderQueries,
 		}
 		return v.ReadNotesHandler(ctx, req, specificArgs)
 	case "get-section":
@@ -646,6 +790,12 @@ func (v *Vault) ReadBatchMultiplexHandler(ctx context.Context, req *mcp.CallTool
 			Heading: args.Heading,
 		}
 		return v.GetSectionHandler(ctx, req, specificArgs)
+	case "get-block":
+		specificArgs := GetBlockArgs{
+			Path:    args.Path,
+			BlockID: args.BlockID,
+		}
+		return v.GetBlockHandler(ctx, req, specificArgs)
 	case "get-headings":
 		specificArgs := GetHeadingsArgs{
 			Path: args.Path,
@@ -664,9 +814,17 @@ func (v *Vault) ReadBatchMultiplexHandler(ctx context.Context, req *mcp.CallTool
 
 // ManageLinksMultiplexArgs multiplexed args
 type ManageLinksMultiplexArgs struct {
-	Action string `json:"action" jsonschema:"Action to perform: 'backlinks', 'forward-links', 'suggest'"`
-	Path   string `json:"path,omitempty" jsonschema:"Path to the note"`
-	Limit  int    `json:"limit,omitempty" jsonschema:"Maximum results (default 10)"`
+	Action        string `json:"action" jsonschema:"Action to perform: 'backlinks', 'forward-links', 'suggest', 'block-refs', 'local-graph'"`
+	Path          string `json:"path,omitempty" jsonschema:"Path to the note"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"Maximum results (default 10; 100 nodes for local-graph)"`
+	BlockID       string `json:"block_id,omitempty" jsonschema:"Block ID to find references to (for block-refs action; default every block in the note)"`
+	Depth         int    `json:"depth,omitempty" jsonschema:"Maximum hops for local-graph (default 1)"`
+	IncludeEmbeds bool   `json:"include_embeds,omitempty" jsonschema:"Follow embeds as well as links (for local-graph)"`
+	ExcludeTags   bool   `json:"exclude_tags,omitempty" jsonschema:"Leave out tags (for local-graph)"`
+	Canvas        string `json:"canvas,omitempty" jsonschema:"Vault path of a .canvas file to render the local graph to"`
+	Overwrite     bool   `json:"overwrite,omitempty" jsonschema:"Replace the local-graph canvas file if it already exists"`
+	Mode          string `json:"mode,omitempty" jsonschema:"Response mode for local-graph: compact (default) or detailed"`
+	Semantic      bool   `json:"semantic,omitempty" jsonschema:"Also suggest notes with related content, building the semantic index if needed (for suggest)"`
 }
 
 // ManageLinksMultiplexHandler routes to the specific handler
@@ -684,10 +842,29 @@ func (v *Vault) ManageLinksMultiplexHandler(ctx context.Context, req *mcp.CallTo
 		return v.ForwardLinksHandler(ctx, req, specificArgs)
 	case "suggest":
 		specificArgs := SuggestLinksArgs{
-			Path:  args.Path,
-			Limit: args.Limit,
+			Path:     args.Path,
+			Limit:    args.Limit,
+			Semantic: args.Semantic,
 		}
 		return v.SuggestLinksHandler(ctx, req, specificArgs)
+	case "block-refs":
+		specificArgs := BlockReferencesArgs{
+			Path:    args.Path,
+			BlockID: args.BlockID,
+		}
+		return v.BlockReferencesHandler(ctx, req, specificArgs)
+	case "local-graph":
+		specificArgs := LocalGraphArgs{
+			Path:          args.Path,
+			Depth:         args.Depth,
+			IncludeEmbeds: args.IncludeEmbeds,
+			ExcludeTags:   args.ExcludeTags,
+			Limit:         args.Limit,
+			Canvas:        args.Canvas,
+			Overwrite:     args.Overwrite,
+			Mode:          args.Mode,
+		}
+		return v.LocalGraphHandler(ctx, req, specificArgs)
 	default:
 		return nil, nil, fmt.Errorf("unknown action: %s", args.Action)
 	}
@@ -703,7 +880,7 @@ type BulkOperationsMultiplexArgs struct {
 	Destination string `json:"destination,omitempty" jsonschema:"Destination folder"`
 	UpdateLinks bool   `json:"update_links,omitempty" jsonschema:"Whether to update links (default true)"`
 	Key         string `json:"key,omitempty" jsonschema:"Frontmatter key"`
-	Value       string `json:"value,omitempty" jsonschema:"Value to set"`
+	Value       string `json:"value,omitempty" jsonschema:"Value to set; JSON arrays and objects are stored as YAML lists and maps"`
 }
 
 // BulkOperationsMultiplexHandler routes to the specific handler
@@ -741,11 +918,11 @@ func (v *Vault) BulkOperationsMultiplexHandler(ctx context.Context, req *mcp.Cal
 // ManageTemplatesMultiplexArgs multiplexed args
 type ManageTemplatesMultiplexArgs struct {
 	Action         string `json:"action" jsonschema:"Action to perform: 'list', 'get', 'apply'"`
-	Folder         string `json:"folder,omitempty" jsonschema:"Templates folder (default: 'templates')"`
+	Folder         string `json:"folder,omitempty" jsonschema:"Templates folder (default: from .obsidian/templates.json, else 'templates')"`
 	Name           string `json:"name,omitempty" jsonschema:"Template name"`
 	Template       string `json:"template,omitempty" jsonschema:"Template name"`
 	Path           string `json:"path,omitempty" jsonschema:"Target note path"`
-	TemplateFolder string `json:"template_folder,omitempty" jsonschema:"Templates folder (default: 'templates')"`
+	TemplateFolder string `json:"template_folder,omitempty" jsonschema:"Templates folder (default: from .obsidian/templates.json, else 'templates')"`
 	Variables      string `json:"variables,omitempty" jsonschema:"JSON string or key=value pairs of variables"`
 }
 
@@ -831,6 +1008,50 @@ func (v *Vault) RefactorNotesMultiplexHandler(ctx context.Context, req *mcp.Call
 	}
 }
 
+// ManageAttachmentsMultiplexArgs multiplexed args
+type ManageAttachmentsMultiplexArgs struct {
+	Action      string `json:"action" jsonschema:"Action to perform: 'list', 'unused', 'broken', 'move', 'import'"`
+	Directory   string `json:"directory,omitempty" jsonschema:"Folder to limit list, unused and broken to"`
+	Extension   string `json:"extension,omitempty" jsonschema:"Comma-separated extensions to filter by (e.g., 'png,pdf')"`
+	Source      string `json:"source,omitempty" jsonschema:"Attachment to move (vault path), or file to import (absolute path in an allowed import path)"`
+	Destination string `json:"destination,omitempty" jsonschema:"New path or folder for the attachment (for move action)"`
+	Note        string `json:"note,omitempty" jsonschema:"Note the imported file is for, used by note-relative attachment folders (for import action)"`
+	Name        string `json:"name,omitempty" jsonschema:"File name for the imported attachment (default: source file name)"`
+	DryRun      bool   `json:"dry_run,omitempty" jsonschema:"Preview move without modifying files"`
+}
+
+// ManageAttachmentsMultiplexHandler routes to the specific handler
+func (v *Vault) ManageAttachmentsMultiplexHandler(ctx context.Context, req *mcp.CallToolRequest, args ManageAttachmentsMultiplexArgs) (*mcp.CallToolResult, any, error) {
+	listArgs := ListAttachmentsArgs{
+		Directory: args.Directory,
+		Extension: args.Extension,
+	}
+	switch args.Action {
+	case "list":
+		return v.ListAttachmentsHandler(ctx, req, listArgs)
+	case "unused":
+		return v.UnusedAttachmentsHandler(ctx, req, listArgs)
+	case "broken":
+		return v.BrokenAttachmentsHandler(ctx, req, listArgs)
+	case "move":
+		specificArgs := MoveAttachmentArgs{
+			Source:      args.Source,
+			Destination: args.Destination,
+			DryRun:      args.DryRun,
+		}
+		return v.MoveAttachmentHandler(ctx, req, specificArgs)
+	case "import":
+		specificArgs := ImportAttachmentArgs{
+			Source: args.Source,
+			Note:   args.Note,
+			Name:   args.Name,
+		}
+		return v.ImportAttachmentHandler(ctx, req, specificArgs)
+	default:
+		return nil, nil, fmt.Errorf("unknown action: %s", args.Action)
+	}
+}
+
 // ManageVaultsMultiplexArgs multiplexed args
 type ManageVaultsMultiplexArgs struct {
 	Action string `json:"action" jsonschema:"Action to perform: 'list', 'switch'"`
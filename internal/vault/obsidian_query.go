@@ -0,0 +1,512 @@
+package vault
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// This file implements Obsidian's search syntax:
+//
+//	tag:#project path:work/ "exact phrase" -draft (foo OR bar)
+//	line:(a b) block:(x) section:(x) task:(x) task-todo:(x) task-done:(x)
+//	file:name content:text match-case:Word ignore-case:word [status:done] /regex/
+//
+// Space-separated terms are ANDed, OR binds looser than AND, a leading "-"
+// negates a term or group, and parentheses group sub-expressions.
+
+// queryOperators are the field prefixes recognized before a colon.
+var queryOperators = map[string]bool{
+	"file": true, "path": true, "content": true, "tag": true,
+	"line": true, "block": true, "section": true,
+	"task": true, "task-todo": true, "task-done": true,
+	"match-case": true, "ignore-case": true,
+}
+
+type queryTokenKind int
+
+const (
+	qtWord queryTokenKind = iota
+	qtPhrase
+	qtRegex
+	qtField
+	qtProperty
+	qtLParen
+	qtRParen
+	qtNot
+	qtOr
+)
+
+type queryToken struct {
+	kind  queryTokenKind
+	value string
+}
+
+// lexQuery splits an Obsidian search query into tokens.
+func lexQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	rs := []rune(input)
+	i := 0
+	for i < len(rs) {
+		r := rs[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: qtLParen})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: qtRParen})
+			i++
+		case r == '-' && i+1 < len(rs) && rs[i+1] != ' ' && startsTerm(tokens):
+			tokens = append(tokens, queryToken{kind: qtNot})
+			i++
+		case r == '"':
+			end := indexRune(rs, '"', i+1)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in query")
+			}
+			tokens = append(tokens, queryToken{kind: qtPhrase, value: string(rs[i+1 : end])})
+			i = end + 1
+		case r == '/':
+			end := indexUnescaped(rs, '/', i+1)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated regex in query")
+			}
+			tokens = append(tokens, queryToken{kind: qtRegex, value: string(rs[i+1 : end])})
+			i = end + 1
+		case r == '[':
+			end := indexRune(rs, ']', i+1)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated property filter in query")
+			}
+			tokens = append(tokens, queryToken{kind: qtProperty, value: string(rs[i+1 : end])})
+			i = end + 1
+		default:
+			start := i
+			for i < len(rs) && !strings.ContainsRune(" \t\n()", rs[i]) {
+				if rs[i] == ':' && queryOperators[strings.ToLower(string(rs[start:i]))] {
+					break
+				}
+				i++
+			}
+			word := string(rs[start:i])
+			if i < len(rs) && rs[i] == ':' {
+				tokens = append(tokens, queryToken{kind: qtField, value: strings.ToLower(word)})
+				i++
+				continue
+			}
+			if word == "OR" {
+				tokens = append(tokens, queryToken{kind: qtOr})
+				continue
+			}
+			tokens = append(tokens, queryToken{kind: qtWord, value: word})
+		}
+	}
+	return tokens, nil
+}
+
+// startsTerm reports whether the next token begins a new term, i.e. it is not
+// glued to a field prefix like "path:" (so "path:-x" searches for "-x").
+func startsTerm(tokens []queryToken) bool {
+	return len(tokens) == 0 || tokens[len(tokens)-1].kind != qtField
+}
+
+func indexRune(rs []rune, target rune, from int) int {
+	for i := from; i < len(rs); i++ {
+		if rs[i] == target {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexUnescaped(rs []rune, target rune, from int) int {
+	for i := from; i < len(rs); i++ {
+		if rs[i] == '\\' {
+			i++
+			continue
+		}
+		if rs[i] == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// queryNode is a node of a parsed search expression.
+type queryNode interface {
+	eval(s *queryScope) bool
+}
+
+type queryAnd struct{ children []queryNode }
+type queryOr struct{ children []queryNode }
+type queryNot struct{ child queryNode }
+
+// queryTerm matches a word, phrase or regex against the scope's text.
+type queryTerm struct {
+	value string
+	re    *regexp.Regexp
+}
+
+// queryField applies an operator such as path: or line: to a sub-expression.
+type queryField struct {
+	op    string
+	child queryNode
+}
+
+// queryProperty matches [key] or [key:value] against frontmatter.
+type queryProperty struct {
+	key   string
+	value string
+}
+
+func (n *queryAnd) eval(s *queryScope) bool {
+	for _, c := range n.children {
+		if !c.eval(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *queryOr) eval(s *queryScope) bool {
+	for _, c := range n.children {
+		if c.eval(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *queryNot) eval(s *queryScope) bool {
+	negated := *s
+	negated.hitLine = nil // lines satisfying a negated term are not hits
+	return !n.child.eval(&negated)
+}
+
+// queryParser is a recursive-descent parser over lexed tokens.
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+// parseObsidianQuery parses an Obsidian search query into an expression tree.
+func parseObsidianQuery(input string) (queryNode, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty search query")
+	}
+	p := &queryParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected ')' in query")
+	}
+	return node, nil
+}
+
+func (p *queryParser) peek() *queryToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	children := []queryNode{first}
+	for tok := p.peek(); tok != nil && tok.kind == qtOr; tok = p.peek() {
+		p.pos++
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, next)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &queryOr{children: children}, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	var children []queryNode
+	for tok := p.peek(); tok != nil && tok.kind != qtOr && tok.kind != qtRParen; tok = p.peek() {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, node)
+	}
+	switch len(children) {
+	case 0:
+		return nil, fmt.Errorf("expected a search term")
+	case 1:
+		return children[0], nil
+	}
+	return &queryAnd{children: children}, nil
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	if tok := p.peek(); tok != nil && tok.kind == qtNot {
+		p.pos++
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &queryNot{child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	tok := p.peek()
+	if tok == nil {
+		return nil, fmt.Errorf("expected a search term")
+	}
+	p.pos++
+	switch tok.kind {
+	case qtLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != qtRParen {
+			return nil, fmt.Errorf("missing ')' in query")
+		}
+		p.pos++
+		return node, nil
+	case qtField:
+		child, err := p.parsePrimary()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", tok.value, err)
+		}
+		return &queryField{op: tok.value, child: child}, nil
+	case qtProperty:
+		key, value, _ := strings.Cut(tok.value, ":")
+		return &queryProperty{key: strings.ToLower(strings.TrimSpace(key)), value: strings.Trim(strings.TrimSpace(value), `"`)}, nil
+	case qtRegex:
+		re, err := regexp.Compile(tok.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex /%s/: %v", tok.value, err)
+		}
+		return &queryTerm{value: tok.value, re: re}, nil
+	case qtWord, qtPhrase:
+		return &queryTerm{value: tok.value}, nil
+	default:
+		return nil, fmt.Errorf("unexpected token in query")
+	}
+}
+
+// queryScopeKind selects how terms are compared within a scope.
+type queryScopeKind int
+
+const (
+	scopeText queryScopeKind = iota
+	scopeTag
+)
+
+// queryScope is the evaluation context: the note, the text that plain terms
+// match against, and how to compare them.
+type queryScope struct {
+	note          *indexedNote
+	kind          queryScopeKind
+	text          string
+	caseSensitive bool
+	hitLine       *int // first line that satisfied a line-level operator
+}
+
+func (n *queryTerm) eval(s *queryScope) bool {
+	if s.kind == scopeTag {
+		want := strings.ToLower(strings.TrimPrefix(n.value, "#"))
+		for _, tag := range s.note.Tags {
+			tag = strings.ToLower(tag)
+			if tag == want || strings.HasPrefix(tag, want+"/") {
+				return true
+			}
+		}
+		return false
+	}
+	if n.re != nil {
+		return n.re.MatchString(s.text)
+	}
+	if s.caseSensitive {
+		return strings.Contains(s.text, n.value)
+	}
+	return strings.Contains(strings.ToLower(s.text), strings.ToLower(n.value))
+}
+
+func (n *queryProperty) eval(s *queryScope) bool {
+	raw, ok := s.note.Frontmatter[n.key]
+	if !ok {
+		return false
+	}
+	if n.value == "" {
+		return true
+	}
+	value := frontmatterText(raw)
+	if n.value == "null" {
+		return strings.TrimSpace(value) == ""
+	}
+	return strings.Contains(strings.ToLower(value), strings.ToLower(n.value))
+}
+
+// queryUnit is a span of a note evaluated by a line-level operator.
+type queryUnit struct {
+	line int
+	text string
+}
+
+func (n *queryField) eval(s *queryScope) bool {
+	sub := *s
+	sub.kind = scopeText
+	switch n.op {
+	case "file":
+		sub.text = filepath.Base(s.note.RelPath)
+	case "path":
+		sub.text = filepath.ToSlash(s.note.RelPath)
+	case "content":
+		sub.text = s.note.Content
+	case "tag":
+		sub.kind = scopeTag
+	case "match-case":
+		sub.caseSensitive = true
+	case "ignore-case":
+		sub.caseSensitive = false
+	default:
+		return n.evalUnits(s, queryUnits(s.note, n.op))
+	}
+	return n.child.eval(&sub)
+}
+
+// evalUnits reports whether any unit satisfies the child expression on its own.
+func (n *queryField) evalUnits(s *queryScope, units []queryUnit) bool {
+	for _, u := range units {
+		sub := *s
+		sub.kind = scopeText
+		sub.text = u.text
+		if n.child.eval(&sub) {
+			if s.hitLine != nil && *s.hitLine == 0 {
+				*s.hitLine = u.line
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// queryUnits splits a note into lines, blocks, sections or tasks.
+func queryUnits(note *indexedNote, op string) []queryUnit {
+	var units []queryUnit
+	switch op {
+	case "line":
+		for i, line := range note.Lines {
+			units = append(units, queryUnit{line: i + 1, text: line})
+		}
+	case "block":
+		units = splitUnits(note.Lines, func(line string) bool { return strings.TrimSpace(line) == "" })
+	case "section":
+		units = splitUnits(note.Lines, func(line string) bool { return headingRegexOld.MatchString(line) })
+	default: // task, task-todo, task-done
+		for _, t := range note.Tasks {
+			if (op == "task-todo" && !t.isOpen()) || (op == "task-done" && t.isOpen()) {
+				continue
+			}
+			units = append(units, queryUnit{line: t.Line, text: t.Text})
+		}
+	}
+	return units
+}
+
+// splitUnits groups lines into units, starting a new unit at each boundary
+// line. Blank-line boundaries are dropped; heading boundaries open the unit.
+func splitUnits(lines []string, boundary func(string) bool) []queryUnit {
+	var units []queryUnit
+	var current []string
+	start := 0
+	flush := func() {
+		if len(current) > 0 {
+			units = append(units, queryUnit{line: start + 1, text: strings.Join(current, "\n")})
+		}
+		current = nil
+	}
+	for i, line := range lines {
+		if boundary(line) {
+			flush()
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+		}
+		if len(current) == 0 {
+			start = i
+		}
+		current = append(current, line)
+	}
+	flush()
+	return units
+}
+
+// positiveQueryTerms collects the plain text terms of an expression that are
+// not negated, used to point results at a matching line.
+func positiveQueryTerms(node queryNode) []*queryTerm {
+	switch n := node.(type) {
+	case *queryTerm:
+		return []*queryTerm{n}
+	case *queryAnd:
+		var terms []*queryTerm
+		for _, c := range n.children {
+			terms = append(terms, positiveQueryTerms(c)...)
+		}
+		return terms
+	case *queryOr:
+		var terms []*queryTerm
+		for _, c := range n.children {
+			terms = append(terms, positiveQueryTerms(c)...)
+		}
+		return terms
+	case *queryField:
+		switch n.op {
+		case "content", "match-case", "ignore-case":
+			return positiveQueryTerms(n.child)
+		}
+	}
+	return nil
+}
+
+// matchObsidianQuery evaluates a parsed query against a note and returns the
+// line to show for it (0 when the match is on metadata only).
+func matchObsidianQuery(node queryNode, note *indexedNote) (matched bool, line int) {
+	hit := 0
+	scope := &queryScope{
+		note:    note,
+		text:    filepath.Base(note.RelPath) + "\n" + note.Content,
+		hitLine: &hit,
+	}
+	if !node.eval(scope) {
+		return false, 0
+	}
+	if hit > 0 {
+		return true, hit
+	}
+
+	terms := positiveQueryTerms(node)
+	for i, text := range note.Lines {
+		lineScope := &queryScope{note: note, text: text}
+		for _, term := range terms {
+			if term.eval(lineScope) {
+				return true, i + 1
+			}
+		}
+	}
+	return true, 0
+}
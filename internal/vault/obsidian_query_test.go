@@ -0,0 +1,110 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func setupQueryVault(t *testing.T) *Vault {
+	t.Helper()
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "work/plan.md", "---\nstatus: active\ntags: [project]\n---\n# Goals\n\nShip the exact phrase feature.\n\n## Review\n- [ ] review budget\n- [x] review hiring\n")
+	writeTestFile(t, dir, "work/draft.md", "---\nstatus: draft\ntags: [project/alpha]\n---\n#project draft notes about foo\n")
+	writeTestFile(t, dir, "home/list.md", "Buy foo\nand bar later\n- [x] done review\n")
+	writeTestFile(t, dir, "home/Bar Notes.md", "foo and bar on one line\n")
+	return v
+}
+
+func queryFiles(t *testing.T, v *Vault, query string) []string {
+	t.Helper()
+	result, _, err := v.SearchQueryHandler(context.Background(), nil, SearchQueryArgs{Query: query})
+	if err != nil {
+		t.Fatalf("query %q failed: %v", query, err)
+	}
+	var envelope struct {
+		Data struct {
+			Matches []SearchResult `json:"matches"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, m := range envelope.Data.Matches {
+		files = append(files, strings.ReplaceAll(m.File, "\\", "/"))
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestSearchQuerySyntax(t *testing.T) {
+	v := setupQueryVault(t)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`tag:#project`, "work/draft.md,work/plan.md"},
+		{`tag:project/alpha`, "work/draft.md"},
+		{`path:work/ -draft`, "work/plan.md"},
+		{`"exact phrase"`, "work/plan.md"},
+		{`foo bar`, "home/Bar Notes.md,home/list.md"},
+		{`line:(foo bar)`, "home/Bar Notes.md"},
+		{`(budget OR hiring) path:work`, "work/plan.md"},
+		{`task-todo:(review)`, "work/plan.md"},
+		{`task-done:review`, "home/list.md,work/plan.md"},
+		{`section:(review budget)`, "work/plan.md"},
+		{`block:(foo later)`, "home/list.md"},
+		{`file:"Bar Notes"`, "home/Bar Notes.md"},
+		{`[status:draft]`, "work/draft.md"},
+		{`[status] -[status:draft]`, "work/plan.md"},
+		{`match-case:Buy`, "home/list.md"},
+		{`match-case:buy`, ""},
+		{`/rev[a-z]+w budget/`, "work/plan.md"},
+		{`-path:work -path:home`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := strings.Join(queryFiles(t, v, tt.query), ","); got != tt.want {
+				t.Errorf("query %q = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryReportsMatchingLine(t *testing.T) {
+	v := setupQueryVault(t)
+	result, _, err := v.SearchQueryHandler(context.Background(), nil, SearchQueryArgs{Query: "task-todo:budget"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, `"Line":10`) || !strings.Contains(text, "review budget") {
+		t.Errorf("expected task line in result, got %s", text)
+	}
+}
+
+func TestSearchQueryPagination(t *testing.T) {
+	v := setupQueryVault(t)
+	result, _, err := v.SearchQueryHandler(context.Background(), nil, SearchQueryArgs{Query: "foo", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, `"truncated":true`) || !strings.Contains(text, `"action":"query"`) {
+		t.Errorf("expected truncated page with next args, got %s", text)
+	}
+}
+
+func TestParseObsidianQueryErrors(t *testing.T) {
+	for _, q := range []string{``, `"unterminated`, `(foo`, `foo)`, `path:`, `/[/`} {
+		if _, err := parseObsidianQuery(q); err == nil {
+			t.Errorf("expected error for %q", q)
+		}
+	}
+}
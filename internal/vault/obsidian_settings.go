@@ -0,0 +1,399 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ObsidianSettings holds the parts of a vault's .obsidian configuration that
+// obx uses as defaults. Formats are moment.js format strings, as stored by
+// Obsidian.
+type ObsidianSettings struct {
+	NewFileLocation    string // "root", "current" or "folder"
+	NewFileFolder      string // used when NewFileLocation is "folder"
+	AttachmentFolder   string // "/" for the root, "./" or "./sub" relative to the note, or a vault folder
+	UseMarkdownLinks   bool
+	NewLinkFormat      string // "shortest", "relative" or "absolute"
+	TemplatesFolder    string
+	TemplateDateFormat string
+	TemplateTimeFormat string
+	Periodic           map[string]PeriodicSettings // keyed by daily, weekly, monthly, quarterly, yearly
+	TaskStatuses       []TaskStatus                // checkbox statuses from the Tasks plugin
+}
+
+// PeriodicSettings configures one kind of periodic note
+type PeriodicSettings struct {
+	Folder   string
+	Format   string
+	Template string // vault-relative template path, with or without .md
+}
+
+// periodicTypes lists the periodic note kinds in order of period length.
+var periodicTypes = []string{"daily", "weekly", "monthly", "quarterly", "yearly"}
+
+// defaultObsidianSettings returns the settings used when a vault has no
+// .obsidian configuration.
+func defaultObsidianSettings() *ObsidianSettings {
+	return &ObsidianSettings{
+		NewFileLocation:    "root",
+		AttachmentFolder:   "/",
+		NewLinkFormat:      "shortest",
+		TemplatesFolder:    "templates",
+		TemplateDateFormat: "YYYY-MM-DD",
+		TemplateTimeFormat: "HH:mm",
+		Periodic: map[string]PeriodicSettings{
+			"daily":     {Folder: "daily", Format: "YYYY-MM-DD"},
+			"weekly":    {Folder: "weekly", Format: "GGGG-[W]WW"},
+			"monthly":   {Folder: "monthly", Format: "YYYY-MM"},
+			"quarterly": {Folder: "quarterly", Format: "YYYY-[Q]Q"},
+			"yearly":    {Folder: "yearly", Format: "YYYY"},
+		},
+		TaskStatuses: slices.Clone(defaultTaskStatuses),
+	}
+}
+
+// obsidianSettings loads app.json, daily-notes.json, templates.json and the
+// Periodic Notes and Tasks plugin data from the vault's .obsidian folder.
+// Files that are missing or unreadable leave the defaults in place, so a
+// broken config never blocks note creation.
+func (v *Vault) obsidianSettings() *ObsidianSettings {
+	s := defaultObsidianSettings()
+	configDir := filepath.Join(v.GetPath(), ".obsidian")
+
+	var app struct {
+		NewFileLocation      string  `json:"newFileLocation"`
+		NewFileFolderPath    string  `json:"newFileFolderPath"`
+		AttachmentFolderPath *string `json:"attachmentFolderPath"`
+		UseMarkdownLinks     bool    `json:"useMarkdownLinks"`
+		NewLinkFormat        string  `json:"newLinkFormat"`
+	}
+	if readSettingsFile(filepath.Join(configDir, "app.json"), &app) {
+		if app.NewFileLocation != "" {
+			s.NewFileLocation = app.NewFileLocation
+		}
+		s.NewFileFolder = strings.Trim(app.NewFileFolderPath, "/")
+		if app.AttachmentFolderPath != nil && *app.AttachmentFolderPath != "" {
+			s.AttachmentFolder = *app.AttachmentFolderPath
+		}
+		s.UseMarkdownLinks = app.UseMarkdownLinks
+		if app.NewLinkFormat != "" {
+			s.NewLinkFormat = app.NewLinkFormat
+		}
+	}
+
+	var templates struct {
+		Folder     string `json:"folder"`
+		DateFormat string `json:"dateFormat"`
+		TimeFormat string `json:"timeFormat"`
+	}
+	if readSettingsFile(filepath.Join(configDir, "templates.json"), &templates) {
+		if templates.Folder != "" {
+			s.TemplatesFolder = strings.Trim(templates.Folder, "/")
+		}
+		if templates.DateFormat != "" {
+			s.TemplateDateFormat = templates.DateFormat
+		}
+		if templates.TimeFormat != "" {
+			s.TemplateTimeFormat = templates.TimeFormat
+		}
+	}
+
+	var daily periodicSettingsFile
+	if readSettingsFile(filepath.Join(configDir, "daily-notes.json"), &daily) {
+		s.Periodic["daily"] = daily.apply(s.Periodic["daily"])
+	}
+
+	// The Periodic Notes plugin takes over every period it has enabled,
+	// including daily notes.
+	plugin := make(map[string]json.RawMessage)
+	if readSettingsFile(filepath.Join(configDir, "plugins", "periodic-notes", "data.json"), &plugin) {
+		for _, kind := range periodicTypes {
+			var p periodicSettingsFile
+			if raw, ok := plugin[kind]; ok && json.Unmarshal(raw, &p) == nil && p.Enabled {
+				s.Periodic[kind] = p.apply(s.Periodic[kind])
+			}
+		}
+	}
+
+	// The Tasks plugin stores its core statuses (todo and done) and custom
+	// ones separately; together they replace the defaults.
+	var tasks struct {
+		StatusSettings struct {
+			CoreStatuses   []TaskStatus `json:"coreStatuses"`
+			CustomStatuses []TaskStatus `json:"customStatuses"`
+		} `json:"statusSettings"`
+	}
+	if readSettingsFile(filepath.Join(configDir, "plugins", "obsidian-tasks-plugin", "data.json"), &tasks) {
+		if statuses := newTaskStatuses(append(tasks.StatusSettings.CoreStatuses, tasks.StatusSettings.CustomStatuses...)); statuses != nil {
+			s.TaskStatuses = statuses
+		}
+	}
+
+	return s
+}
+
+// periodicSettingsFile is the shape of daily-notes.json and of each period
+// in the Periodic Notes plugin data.
+type periodicSettingsFile struct {
+	Enabled  bool   `json:"enabled"`
+	Folder   string `json:"folder"`
+	Format   string `json:"format"`
+	Template string `json:"template"`
+}
+
+// apply overlays the file's values on defaults. An empty folder means the
+// vault root, as in Obsidian.
+func (p periodicSettingsFile) apply(defaults PeriodicSettings) PeriodicSettings {
+	defaults.Folder = strings.Trim(p.Folder, "/")
+	if p.Format != "" {
+		defaults.Format = p.Format
+	}
+	defaults.Template = strings.Trim(p.Template, "/")
+	return defaults
+}
+
+func readSettingsFile(path string, dest any) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, dest) == nil
+}
+
+// newNotePath places a bare note name according to the "Default location
+// for new notes" setting. Paths that already contain a folder are kept.
+// currentPath is the note the new one is created from, if any.
+func (s *ObsidianSettings) newNotePath(name, currentPath string) string {
+	if strings.ContainsAny(name, `/\`) {
+		return name
+	}
+	switch s.NewFileLocation {
+	case "folder":
+		if s.NewFileFolder != "" {
+			return filepath.Join(s.NewFileFolder, name)
+		}
+	case "current":
+		if currentPath != "" {
+			return filepath.Join(filepath.Dir(currentPath), name)
+		}
+	}
+	return name
+}
+
+// attachmentPath places a new attachment according to the "Default location
+// for new attachments" setting. notePath is the note it is attached to, if
+// any; without one, folders relative to the note fall back to the root.
+func (s *ObsidianSettings) attachmentPath(name, notePath string) string {
+	folder := s.AttachmentFolder
+	switch {
+	case folder == "" || folder == "/":
+		return name
+	case folder == "." || strings.HasPrefix(folder, "./"):
+		dir := ""
+		if notePath != "" {
+			dir = filepath.Dir(notePath)
+		}
+		sub := strings.Trim(strings.TrimPrefix(folder, "."), "/")
+		return filepath.Join(dir, filepath.FromSlash(sub), name)
+	default:
+		return filepath.Join(filepath.FromSlash(strings.Trim(folder, "/")), name)
+	}
+}
+
+// linkTo formats a link from the note at fromPath to the vault file at
+// targetPath, honoring the link style and "New link format" settings.
+func (s *ObsidianSettings) linkTo(fromPath, targetPath string) string {
+	fromPath, targetPath = filepath.ToSlash(fromPath), filepath.ToSlash(targetPath)
+	var ref string
+	switch s.NewLinkFormat {
+	case "absolute":
+		ref = targetPath
+	case "relative":
+		rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(fromPath)), filepath.FromSlash(targetPath))
+		if err != nil {
+			rel = targetPath
+		}
+		ref = filepath.ToSlash(rel)
+	default:
+		ref = filepath.Base(targetPath)
+	}
+
+	if s.UseMarkdownLinks {
+		name := strings.TrimSuffix(filepath.Base(targetPath), ".md")
+		return fmt.Sprintf("[%s](%s)", name, (&url.URL{Path: ref}).EscapedPath())
+	}
+	return "[[" + strings.TrimSuffix(ref, ".md") + "]]"
+}
+
+// formatMoment formats t with a moment.js format string, the syntax used by
+// Obsidian's date settings. Text in [brackets] is literal. Locale weeks
+// (gggg, ww) are treated as ISO weeks.
+func formatMoment(t time.Time, format string) string {
+	isoYear, isoWeek := t.ISOWeek()
+	tokens := []struct {
+		token string
+		value func() string
+	}{
+		{"YYYY", func() string { return fmt.Sprintf("%04d", t.Year()) }},
+		{"GGGG", func() string { return fmt.Sprintf("%04d", isoYear) }},
+		{"gggg", func() string { return fmt.Sprintf("%04d", isoYear) }},
+		{"MMMM", func() string { return t.Month().String() }},
+		{"dddd", func() string { return t.Weekday().String() }},
+		{"DDDD", func() string { return fmt.Sprintf("%03d", t.YearDay()) }},
+		{"MMM", func() string { return t.Month().String()[:3] }},
+		{"ddd", func() string { return t.Weekday().String()[:3] }},
+		{"DDD", func() string { return fmt.Sprint(t.YearDay()) }},
+		{"YY", func() string { return fmt.Sprintf("%02d", t.Year()%100) }},
+		{"GG", func() string { return fmt.Sprintf("%02d", isoYear%100) }},
+		{"MM", func() string { return fmt.Sprintf("%02d", int(t.Month())) }},
+		{"Do", func() string { return ordinal(t.Day()) }},
+		{"DD", func() string { return fmt.Sprintf("%02d", t.Day()) }},
+		{"dd", func() string { return t.Weekday().String()[:2] }},
+		{"WW", func() string { return fmt.Sprintf("%02d", isoWeek) }},
+		{"ww", func() string { return fmt.Sprintf("%02d", isoWeek) }},
+		{"HH", func() string { return fmt.Sprintf("%02d", t.Hour()) }},
+		{"hh", func() string { return fmt.Sprintf("%02d", hour12(t)) }},
+		{"mm", func() string { return fmt.Sprintf("%02d", t.Minute()) }},
+		{"ss", func() string { return fmt.Sprintf("%02d", t.Second()) }},
+		{"M", func() string { return fmt.Sprint(int(t.Month())) }},
+		{"Q", func() string { return fmt.Sprint((int(t.Month())-1)/3 + 1) }},
+		{"D", func() string { return fmt.Sprint(t.Day()) }},
+		{"d", func() string { return fmt.Sprint(int(t.Weekday())) }},
+		{"E", func() string { return fmt.Sprint((int(t.Weekday())+6)%7 + 1) }},
+		{"W", func() string { return fmt.Sprint(isoWeek) }},
+		{"w", func() string { return fmt.Sprint(isoWeek) }},
+		{"H", func() string { return fmt.Sprint(t.Hour()) }},
+		{"h", func() string { return fmt.Sprint(hour12(t)) }},
+		{"m", func() string { return fmt.Sprint(t.Minute()) }},
+		{"s", func() string { return fmt.Sprint(t.Second()) }},
+		{"A", func() string { return t.Format("PM") }},
+		{"a", func() string { return t.Format("pm") }},
+		{"X", func() string { return fmt.Sprint(t.Unix()) }},
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(format); {
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end > 0 {
+				sb.WriteString(format[i+1 : i+end])
+				i += end + 1
+				continue
+			}
+		}
+		matched := false
+		for _, tok := range tokens {
+			if strings.HasPrefix(format[i:], tok.token) {
+				sb.WriteString(tok.value())
+				i += len(tok.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			sb.WriteByte(format[i])
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// momentPattern returns a regexp matching the whole of any string that
+// formatMoment can produce for format, used to recognise periodic notes by
+// name.
+func momentPattern(format string) *regexp.Regexp {
+	months := "January|February|March|April|May|June|July|August|September|October|November|December"
+	days := "Monday|Tuesday|Wednesday|Thursday|Friday|Saturday|Sunday"
+	tokens := []struct{ token, pattern string }{
+		{"YYYY", `\d{4}`},
+		{"GGGG", `\d{4}`},
+		{"gggg", `\d{4}`},
+		{"MMMM", months},
+		{"dddd", days},
+		{"DDDD", `\d{3}`},
+		{"MMM", "Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec"},
+		{"ddd", "Mon|Tue|Wed|Thu|Fri|Sat|Sun"},
+		{"DDD", `\d{1,3}`},
+		{"YY", `\d{2}`},
+		{"GG", `\d{2}`},
+		{"MM", `0[1-9]|1[0-2]`},
+		{"Do", `\d{1,2}(?:st|nd|rd|th)`},
+		{"DD", `0[1-9]|[12]\d|3[01]`},
+		{"dd", "Mo|Tu|We|Th|Fr|Sa|Su"},
+		{"WW", `\d{2}`},
+		{"ww", `\d{2}`},
+		{"HH", `\d{2}`},
+		{"hh", `\d{2}`},
+		{"mm", `\d{2}`},
+		{"ss", `\d{2}`},
+		{"M", `\d{1,2}`},
+		{"Q", `[1-4]`},
+		{"D", `\d{1,2}`},
+		{"d", `[0-6]`},
+		{"E", `[1-7]`},
+		{"W", `\d{1,2}`},
+		{"w", `\d{1,2}`},
+		{"H", `\d{1,2}`},
+		{"h", `\d{1,2}`},
+		{"m", `\d{1,2}`},
+		{"s", `\d{1,2}`},
+		{"A", "AM|PM"},
+		{"a", "am|pm"},
+		{"X", `\d+`},
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(format); {
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end > 0 {
+				sb.WriteString(regexp.QuoteMeta(format[i+1 : i+end]))
+				i += end + 1
+				continue
+			}
+		}
+		matched := false
+		for _, tok := range tokens {
+			if strings.HasPrefix(format[i:], tok.token) {
+				sb.WriteString("(?:" + tok.pattern + ")")
+				i += len(tok.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			sb.WriteString(regexp.QuoteMeta(format[i : i+1]))
+			i++
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+func hour12(t time.Time) int {
+	if h := t.Hour() % 12; h != 0 {
+		return h
+	}
+	return 12
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
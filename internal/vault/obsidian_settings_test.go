@@ -0,0 +1,192 @@
+package vault
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestFormatMoment(t *testing.T) {
+	date := time.Date(2026, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"YYYY-MM-DD", "2026-01-02"},
+		{"GGGG-[W]WW", "2026-W01"},
+		{"YYYY-[Q]Q", "2026-Q1"},
+		{"dddd, MMMM Do YYYY", "Friday, January 2nd 2026"},
+		{"YYYY/MM/DD ddd", "2026/01/02 Fri"},
+		{"HH:mm:ss", "15:04:05"},
+		{"h:mm A", "3:04 PM"},
+		{"[Daily] D.M.YY", "Daily 2.1.26"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := formatMoment(date, tt.format); got != tt.want {
+				t.Errorf("formatMoment(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObsidianSettingsLoad(t *testing.T) {
+	v, dir := setupTestVault(t)
+
+	s := v.obsidianSettings()
+	if s.Periodic["daily"].Folder != "daily" || s.TemplatesFolder != "templates" || s.NewLinkFormat != "shortest" {
+		t.Errorf("unexpected defaults: %+v", s)
+	}
+
+	writeTestFile(t, dir, ".obsidian/app.json", `{"newFileLocation": "folder", "newFileFolderPath": "Inbox/", "attachmentFolderPath": "./assets", "useMarkdownLinks": true, "newLinkFormat": "relative"}`)
+	writeTestFile(t, dir, ".obsidian/daily-notes.json", `{"folder": "Journal/Daily", "format": "DD-MM-YYYY", "template": "Templates/Daily"}`)
+	writeTestFile(t, dir, ".obsidian/templates.json", `{"folder": "Templates", "dateFormat": "DD/MM/YYYY"}`)
+	writeTestFile(t, dir, ".obsidian/plugins/periodic-notes/data.json", `{"weekly": {"enabled": true, "folder": "Journal/Weekly", "format": "gggg-[Week]-ww"}, "monthly": {"enabled": false, "folder": "ignored"}}`)
+
+	s = v.obsidianSettings()
+	if s.NewFileLocation != "folder" || s.NewFileFolder != "Inbox" || s.AttachmentFolder != "./assets" || !s.UseMarkdownLinks || s.NewLinkFormat != "relative" {
+		t.Errorf("app.json not applied: %+v", s)
+	}
+	if want := (PeriodicSettings{Folder: "Journal/Daily", Format: "DD-MM-YYYY", Template: "Templates/Daily"}); s.Periodic["daily"] != want {
+		t.Errorf("daily = %+v, want %+v", s.Periodic["daily"], want)
+	}
+	if s.TemplatesFolder != "Templates" || s.TemplateDateFormat != "DD/MM/YYYY" || s.TemplateTimeFormat != "HH:mm" {
+		t.Errorf("templates.json not applied: %+v", s)
+	}
+	if s.Periodic["weekly"].Folder != "Journal/Weekly" || s.Periodic["monthly"].Folder != "monthly" {
+		t.Errorf("periodic notes plugin not applied: %+v", s.Periodic)
+	}
+
+	writeTestFile(t, dir, ".obsidian/app.json", `{not json`)
+	if s = v.obsidianSettings(); s.NewFileLocation != "root" {
+		t.Errorf("malformed app.json should fall back to defaults, got %+v", s)
+	}
+}
+
+func TestDailyNoteUsesObsidianSettings(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, ".obsidian/daily-notes.json", `{"folder": "Journal", "format": "YYYY/MM/DD-ddd", "template": "Templates/Daily"}`)
+	writeTestFile(t, dir, "Templates/Daily.md", "# {{title}}\n\nDate: {{date}}\nISO: {{date:YYYY-MM-DD}}\nPrev: [[{{yesterday}}]]\n")
+
+	result, _, err := v.DailyNoteHandler(context.Background(), nil, DailyNoteArgs{Date: "2026-03-10", CreateIfMissing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.HasPrefix(text, "Created: "+filepath.Join("Journal", "2026", "03", "10-Tue.md")) {
+		t.Errorf("unexpected daily note path:\n%s", text)
+	}
+	content := readTestFile(t, dir, "Journal/2026/03/10-Tue.md")
+	if want := "# 10-Tue\n\nDate: 2026/03/10-Tue\nISO: 2026-03-10\nPrev: [[2026/03/09-Mon]]\n"; content != want {
+		t.Errorf("daily note content = %q, want %q", content, want)
+	}
+}
+
+func TestPeriodicNotesPluginSettings(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, ".obsidian/plugins/periodic-notes/data.json", `{"weekly": {"enabled": true, "folder": "Weeks", "format": "gggg-[W]ww"}, "yearly": {"enabled": true, "folder": "", "format": "[Year] YYYY"}}`)
+	ctx := context.Background()
+
+	if _, _, err := v.WeeklyNoteHandler(ctx, nil, WeeklyNoteArgs{Date: "2026-03-10", CreateIfMissing: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := v.YearlyNoteHandler(ctx, nil, YearlyNoteArgs{Date: "2026-03-10", CreateIfMissing: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Weeks/2026-W11.md", "Year 2026.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+}
+
+func TestListPeriodicNotesInVaultRoot(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, ".obsidian/daily-notes.json", `{"format": "DD.MM.YYYY"}`)
+	writeTestFile(t, dir, ".obsidian/plugins/periodic-notes/data.json", `{"weekly": {"enabled": true, "folder": "", "format": "gggg-[W]ww"}}`)
+	for _, name := range []string{"09.03.2026.md", "10.03.2026.md", "2026-W11.md", "Project ideas.md", "2026.md", "notes/11.03.2026.md"} {
+		writeTestFile(t, dir, name, "")
+	}
+	ctx := context.Background()
+
+	result, _, err := v.ListDailyNotesHandler(ctx, nil, ListPeriodicArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "Found 2 daily notes") || !strings.Contains(text, "10.03.2026.md") || strings.Contains(text, "Project ideas") {
+		t.Errorf("unexpected daily notes:\n%s", text)
+	}
+
+	result, _, err = v.ListPeriodicNotesHandler(ctx, nil, ListPeriodicArgs{Type: "weekly"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text = result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "Found 1 weekly notes") || !strings.Contains(text, "[[2026-W11]]") {
+		t.Errorf("unexpected weekly notes:\n%s", text)
+	}
+}
+
+func TestApplyTemplateUsesObsidianSettings(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, ".obsidian/app.json", `{"newFileLocation": "folder", "newFileFolderPath": "Inbox"}`)
+	writeTestFile(t, dir, ".obsidian/templates.json", `{"folder": "Meta/Templates", "dateFormat": "DD.MM.YYYY"}`)
+	writeTestFile(t, dir, "Meta/Templates/Meeting.md", "# {{title}} on {{date}}\n")
+
+	_, _, err := v.ApplyTemplateHandler(context.Background(), nil, ApplyTemplateArgs{Template: "Meeting", Path: "Standup"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := readTestFile(t, dir, "Inbox/Standup.md")
+	if want := "# Standup on " + time.Now().Format("02.01.2006") + "\n"; content != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+}
+
+func TestLinkTo(t *testing.T) {
+	tests := []struct {
+		format   string
+		markdown bool
+		want     string
+	}{
+		{"shortest", false, "[[Target Note]]"},
+		{"absolute", false, "[[projects/sub/Target Note]]"},
+		{"relative", false, "[[sub/Target Note]]"},
+		{"relative", true, "[Target Note](sub/Target%20Note.md)"},
+	}
+	for _, tt := range tests {
+		s := defaultObsidianSettings()
+		s.NewLinkFormat, s.UseMarkdownLinks = tt.format, tt.markdown
+		if got := s.linkTo("projects/source.md", "projects/sub/Target Note.md"); got != tt.want {
+			t.Errorf("linkTo(%s, markdown=%v) = %q, want %q", tt.format, tt.markdown, got, tt.want)
+		}
+	}
+}
+
+func TestAttachmentPath(t *testing.T) {
+	tests := []struct {
+		folder string
+		note   string
+		want   string
+	}{
+		{"/", "notes/a.md", "pic.png"},
+		{"assets/img", "notes/a.md", filepath.Join("assets", "img", "pic.png")},
+		{"./", "notes/a.md", filepath.Join("notes", "pic.png")},
+		{"./attachments", "notes/a.md", filepath.Join("notes", "attachments", "pic.png")},
+		{"./attachments", "", filepath.Join("attachments", "pic.png")},
+	}
+	for _, tt := range tests {
+		s := defaultObsidianSettings()
+		s.AttachmentFolder = tt.folder
+		if got := s.attachmentPath("pic.png", tt.note); got != tt.want {
+			t.Errorf("attachmentPath(%q, %q) = %q, want %q", tt.folder, tt.note, got, tt.want)
+		}
+	}
+}
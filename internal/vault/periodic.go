@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -19,11 +20,9 @@ func (v *Vault) WeeklyNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 	format := args.Format
 	createIfMissing := args.CreateIfMissing
 
+	settings := v.obsidianSettings().Periodic["weekly"]
 	if folder == "" {
-		folder = "weekly"
-	}
-	if format == "" {
-		format = "2006-W02"
+		folder = settings.Folder
 	}
 
 	targetDate, err := parseFlexibleDate(dateStr)
@@ -35,15 +34,17 @@ func (v *Vault) WeeklyNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 	year, week := targetDate.ISOWeek()
 	weekStart := weekStartDate(year, week)
 
-	// Build filename using format or default
-	var filename string
-	if format == "2006-W02" {
+	// Build filename from the explicit Go layout or the configured format
+	filename := formatMoment(weekStart, settings.Format) + ".md"
+	switch format {
+	case "":
+	case "2006-W02":
 		filename = fmt.Sprintf("%d-W%02d.md", year, week)
-	} else {
+	default:
 		filename = weekStart.Format(format) + ".md"
 	}
 
-	return v.getOrCreatePeriodicNote(folder, filename, createIfMissing, func() string {
+	return v.getOrCreatePeriodicNote(folder, filename, createIfMissing, v.periodicContent(settings, weekStart, filename, func() string {
 		weekEnd := weekStart.AddDate(0, 0, 6)
 		return fmt.Sprintf(`# Week %d, %d
 
@@ -58,7 +59,7 @@ func (v *Vault) WeeklyNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 ## Review
 
 `, week, year, weekStart.Format("Jan 2"), weekEnd.Format("Jan 2, 2006"))
-	})
+	}))
 }
 
 // MonthlyNoteHandler gets or creates a monthly note
@@ -68,11 +69,9 @@ func (v *Vault) MonthlyNoteHandler(ctx context.Context, req *mcp.CallToolRequest
 	format := args.Format
 	createIfMissing := args.CreateIfMissing
 
+	settings := v.obsidianSettings().Periodic["monthly"]
 	if folder == "" {
-		folder = "monthly"
-	}
-	if format == "" {
-		format = "2006-01"
+		folder = settings.Folder
 	}
 
 	targetDate, err := parseFlexibleDate(dateStr)
@@ -82,9 +81,12 @@ func (v *Vault) MonthlyNoteHandler(ctx context.Context, req *mcp.CallToolRequest
 
 	// Normalize to first of month
 	monthStart := time.Date(targetDate.Year(), targetDate.Month(), 1, 0, 0, 0, 0, targetDate.Location())
-	filename := monthStart.Format(format) + ".md"
+	filename := formatMoment(monthStart, settings.Format) + ".md"
+	if format != "" {
+		filename = monthStart.Format(format) + ".md"
+	}
 
-	return v.getOrCreatePeriodicNote(folder, filename, createIfMissing, func() string {
+	return v.getOrCreatePeriodicNote(folder, filename, createIfMissing, v.periodicContent(settings, monthStart, filename, func() string {
 		return fmt.Sprintf(`# %s
 
 ## Goals
@@ -100,7 +102,7 @@ func (v *Vault) MonthlyNoteHandler(ctx context.Context, req *mcp.CallToolRequest
 ## Month Review
 
 `, monthStart.Format("January 2006"), monthStart.Year(), getISOWeek(monthStart))
-	})
+	}))
 }
 
 // QuarterlyNoteHandler gets or creates a quarterly note
@@ -109,8 +111,9 @@ func (v *Vault) QuarterlyNoteHandler(ctx context.Context, req *mcp.CallToolReque
 	folder := args.Folder
 	createIfMissing := args.CreateIfMissing
 
+	settings := v.obsidianSettings().Periodic["quarterly"]
 	if folder == "" {
-		folder = "quarterly"
+		folder = settings.Folder
 	}
 
 	targetDate, err := parseFlexibleDate(dateStr)
@@ -120,9 +123,10 @@ func (v *Vault) QuarterlyNoteHandler(ctx context.Context, req *mcp.CallToolReque
 
 	quarter := (int(targetDate.Month())-1)/3 + 1
 	year := targetDate.Year()
-	filename := fmt.Sprintf("%d-Q%d.md", year, quarter)
+	quarterStart := time.Date(year, time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, targetDate.Location())
+	filename := formatMoment(quarterStart, settings.Format) + ".md"
 
-	return v.getOrCreatePeriodicNote(folder, filename, createIfMissing, func() string {
+	return v.getOrCreatePeriodicNote(folder, filename, createIfMissing, v.periodicContent(settings, quarterStart, filename, func() string {
 		startMonth := time.Month((quarter-1)*3 + 1)
 		month1 := time.Date(year, startMonth, 1, 0, 0, 0, 0, targetDate.Location()).Format("2006-01")
 		month2 := time.Date(year, startMonth+1, 1, 0, 0, 0, 0, targetDate.Location()).Format("2006-01")
@@ -145,7 +149,7 @@ func (v *Vault) QuarterlyNoteHandler(ctx context.Context, req *mcp.CallToolReque
 ## Quarter Review
 
 `, quarter, year, month1, month2, month3)
-	})
+	}))
 }
 
 // YearlyNoteHandler gets or creates a yearly note
@@ -154,8 +158,9 @@ func (v *Vault) YearlyNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 	folder := args.Folder
 	createIfMissing := args.CreateIfMissing
 
+	settings := v.obsidianSettings().Periodic["yearly"]
 	if folder == "" {
-		folder = "yearly"
+		folder = settings.Folder
 	}
 
 	targetDate, err := parseFlexibleDate(dateStr)
@@ -164,9 +169,10 @@ func (v *Vault) YearlyNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 	}
 
 	year := targetDate.Year()
-	filename := fmt.Sprintf("%d.md", year)
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, targetDate.Location())
+	filename := formatMoment(yearStart, settings.Format) + ".md"
 
-	return v.getOrCreatePeriodicNote(folder, filename, createIfMissing, func() string {
+	return v.getOrCreatePeriodicNote(folder, filename, createIfMissing, v.periodicContent(settings, yearStart, filename, func() string {
 		return fmt.Sprintf(`# %d
 
 ## Theme
@@ -185,7 +191,7 @@ func (v *Vault) YearlyNoteHandler(ctx context.Context, req *mcp.CallToolRequest,
 ## Year Review
 
 `, year, year, year, year, year)
-	})
+	}))
 }
 
 // ListPeriodicNotesHandler lists periodic notes of a given type
@@ -201,16 +207,9 @@ func (v *Vault) ListPeriodicNotesHandler(ctx context.Context, req *mcp.CallToolR
 		limit = 20
 	}
 
-	// Map type to default folder
-	folderMap := map[string]string{
-		"daily":     "daily",
-		"weekly":    "weekly",
-		"monthly":   "monthly",
-		"quarterly": "quarterly",
-		"yearly":    "yearly",
-	}
-
-	folder, ok := folderMap[noteType]
+	// Map type to its configured folder
+	periodic, ok := v.obsidianSettings().Periodic[noteType]
+	folder := periodic.Folder
 	if !ok {
 		return nil, nil, fmt.Errorf("unknown type: %s. Use: daily, weekly, monthly, quarterly, yearly", noteType)
 	}
@@ -241,13 +240,21 @@ func (v *Vault) ListPeriodicNotesHandler(ctx context.Context, req *mcp.CallToolR
 	}
 
 	var notes []noteInfo
+	// Periodic notes kept in the vault root are told apart from other notes by name
+	var names *regexp.Regexp
+	if folder == "" {
+		names = momentPattern(periodic.Format)
+	}
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+	err := v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
 		if !info.IsDir() && strings.HasSuffix(path, ".md") {
 			relPath, _ := filepath.Rel(v.GetPath(), path)
+			if names != nil && !names.MatchString(filepath.ToSlash(strings.TrimSuffix(relPath, ".md"))) {
+				return nil
+			}
 			notes = append(notes, noteInfo{
 				name:    strings.TrimSuffix(filepath.Base(path), ".md"),
 				path:    relPath,
@@ -390,6 +397,59 @@ func weekStartDate(year, week int) time.Time {
 	return week1Monday.AddDate(0, 0, (week-jan4Week)*7)
 }
 
+// periodicDateVarRegex matches the variables Obsidian fills in periodic note
+// templates: {{date}}, {{time}}, {{title}}, {{yesterday}}, {{tomorrow}} and
+// {{date:FORMAT}}.
+var periodicDateVarRegex = regexp.MustCompile(`(?i)\{\{\s*(date|time|title|yesterday|tomorrow)\s*(?::([^}]*))?\}\}`)
+
+// periodicContent returns the content for a new periodic note: the configured
+// template with its date variables filled in, or builtin when there is no
+// template or it cannot be read.
+func (v *Vault) periodicContent(settings PeriodicSettings, date time.Time, filename string, builtin func() string) func() string {
+	return func() string {
+		if settings.Template == "" {
+			return builtin()
+		}
+		templatePath := settings.Template
+		if !strings.HasSuffix(templatePath, ".md") {
+			templatePath += ".md"
+		}
+		fullPath := filepath.Join(v.GetPath(), templatePath)
+		if !v.isPathSafe(fullPath) {
+			return builtin()
+		}
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			return builtin()
+		}
+
+		title := strings.TrimSuffix(filepath.Base(filename), ".md")
+		now := time.Now()
+		return periodicDateVarRegex.ReplaceAllStringFunc(string(content), func(match string) string {
+			parts := periodicDateVarRegex.FindStringSubmatch(match)
+			name, format := strings.ToLower(parts[1]), strings.TrimSpace(parts[2])
+			day := date
+			switch name {
+			case "title":
+				return title
+			case "time":
+				if format == "" {
+					format = "HH:mm"
+				}
+				return formatMoment(now, format)
+			case "yesterday":
+				day = date.AddDate(0, 0, -1)
+			case "tomorrow":
+				day = date.AddDate(0, 0, 1)
+			}
+			if format == "" {
+				format = settings.Format
+			}
+			return formatMoment(day, format)
+		})
+	}
+}
+
 // Helper: get ISO week number
 func getISOWeek(t time.Time) int {
 	_, week := t.ISOWeek()
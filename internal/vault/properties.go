@@ -0,0 +1,390 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// Property types as stored by Obsidian in .obsidian/types.json. "list" is
+// accepted as a synonym for multitext.
+const (
+	propertyText      = "text"
+	propertyMultitext = "multitext"
+	propertyList      = "list"
+	propertyNumber    = "number"
+	propertyCheckbox  = "checkbox"
+	propertyDate      = "date"
+	propertyDatetime  = "datetime"
+	propertyTags      = "tags"
+	propertyAliases   = "aliases"
+)
+
+// builtinPropertyTypes are the types Obsidian assigns without a types.json entry.
+var builtinPropertyTypes = map[string]string{
+	"tags":       propertyTags,
+	"aliases":    propertyAliases,
+	"cssclasses": propertyMultitext,
+}
+
+var propertyDatetimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	time.RFC3339,
+}
+
+// propertyTypes loads the declared property types from .obsidian/types.json,
+// keyed by lower-cased property name. A missing file yields the built-in types.
+func (v *Vault) propertyTypes() (map[string]string, error) {
+	types := make(map[string]string, len(builtinPropertyTypes))
+	for k, t := range builtinPropertyTypes {
+		types[k] = t
+	}
+
+	data, err := os.ReadFile(filepath.Join(v.GetPath(), ".obsidian", "types.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return types, nil
+		}
+		return nil, fmt.Errorf("failed to read property types: %v", err)
+	}
+
+	var file struct {
+		Types map[string]string `json:"types"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid .obsidian/types.json: %v", err)
+	}
+	for k, t := range file.Types {
+		types[strings.ToLower(k)] = strings.ToLower(t)
+	}
+	return types, nil
+}
+
+// propertyValueNode builds the YAML node for a value being set on key,
+// coercing it to the key's declared type when there is one.
+func (v *Vault) propertyValueNode(key, raw string) (*yaml.Node, error) {
+	types, err := v.propertyTypes()
+	if err != nil {
+		return nil, err
+	}
+	typ, ok := types[strings.ToLower(key)]
+	if !ok {
+		return frontmatterValueNode(raw), nil
+	}
+	node, err := coercePropertyValue(typ, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s (%s property): %v", key, typ, err)
+	}
+	return node, nil
+}
+
+// coercePropertyValue converts raw into a node of the given property type.
+// An empty value clears the property, as Obsidian does.
+func coercePropertyValue(typ, raw string) (*yaml.Node, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}, nil
+	}
+
+	switch typ {
+	case propertyNumber:
+		if _, err := strconv.ParseFloat(trimmed, 64); err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: trimmed}, nil
+	case propertyCheckbox:
+		switch strings.ToLower(trimmed) {
+		case "true", "yes", "on", "1", "x":
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}, nil
+		case "false", "no", "off", "0":
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}, nil
+		}
+		return nil, fmt.Errorf("%q is not true or false", raw)
+	case propertyDate:
+		t, ok := parsePropertyTime(trimmed)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a date (YYYY-MM-DD)", raw)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: t.Format("2006-01-02")}, nil
+	case propertyDatetime:
+		t, ok := parsePropertyTime(trimmed)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a date and time (YYYY-MM-DDTHH:MM)", raw)
+		}
+		layout := "2006-01-02T15:04"
+		if t.Second() != 0 {
+			layout = "2006-01-02T15:04:05"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: t.Format(layout)}, nil
+	case propertyMultitext, propertyList, propertyTags, propertyAliases:
+		items, err := propertyListItems(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range items {
+			if typ == propertyTags {
+				item = strings.TrimPrefix(item, "#")
+			}
+			seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+		}
+		return seq, nil
+	case propertyText:
+		if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+			var structured any
+			if json.Unmarshal([]byte(trimmed), &structured) == nil {
+				return nil, fmt.Errorf("expected text, got a JSON list or object")
+			}
+		}
+		var s string
+		if strings.HasPrefix(trimmed, `"`) && json.Unmarshal([]byte(trimmed), &s) == nil {
+			raw = s
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: raw}, nil
+	}
+	return frontmatterValueNode(raw), nil
+}
+
+// propertyListItems reads a JSON array of strings or a comma-separated list.
+func propertyListItems(raw string) ([]string, error) {
+	if strings.HasPrefix(raw, "[") {
+		var values []any
+		if err := json.Unmarshal([]byte(raw), &values); err == nil {
+			items := make([]string, 0, len(values))
+			for _, value := range values {
+				items = append(items, frontmatterText(value))
+			}
+			return items, nil
+		}
+	}
+	if strings.HasPrefix(raw, "{") {
+		return nil, fmt.Errorf("expected a list, got a JSON object")
+	}
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func parsePropertyTime(s string) (time.Time, bool) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, true
+	}
+	for _, layout := range propertyDatetimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// checkPropertyValue reports why value does not fit typ, or "" when it does.
+// Empty values fit every type.
+func checkPropertyValue(typ string, value any) string {
+	if value == nil {
+		return ""
+	}
+	got := propertyValueType(value)
+	switch typ {
+	case propertyText:
+		// Obsidian shows an unquoted scalar like `version: 2` as text
+		if got == propertyList || got == "object" {
+			return "expected text, got " + got
+		}
+	case propertyNumber, propertyCheckbox:
+		if got != typ {
+			return "expected " + typ + ", got " + got
+		}
+	case propertyDate:
+		if got != propertyDate {
+			return "expected date (YYYY-MM-DD), got " + got
+		}
+	case propertyDatetime:
+		if got != propertyDatetime && got != propertyDate {
+			return "expected datetime, got " + got
+		}
+	case propertyMultitext, propertyList, propertyTags, propertyAliases:
+		if got != propertyList {
+			return "expected list, got " + got
+		}
+	}
+	return ""
+}
+
+// propertyValueType infers the property type of a parsed frontmatter value.
+func propertyValueType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		return propertyCheckbox
+	case int, int64, uint64, float64:
+		return propertyNumber
+	case []any:
+		return propertyList
+	case map[string]any:
+		return "object"
+	case string:
+		if _, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+			return propertyDate
+		}
+		if _, ok := parsePropertyTime(v); ok {
+			return propertyDatetime
+		}
+	}
+	return propertyText
+}
+
+// PropertyUsage describes one frontmatter property across the vault
+type PropertyUsage struct {
+	Name       string              `json:"name"`
+	Type       string              `json:"type,omitempty"`
+	Inferred   string              `json:"inferred,omitempty"`
+	Count      int                 `json:"count"`
+	Violations []PropertyViolation `json:"violations,omitempty"`
+}
+
+// PropertyViolation is a note whose value does not match the declared type
+type PropertyViolation struct {
+	Path    string `json:"path"`
+	Value   string `json:"value"`
+	Problem string `json:"problem"`
+}
+
+// ListPropertiesHandler reports every frontmatter property in use with its
+// declared type, usage count and type violations
+func (v *Vault) ListPropertiesHandler(ctx context.Context, req *mcp.CallToolRequest, args ListPropertiesArgs) (*mcp.CallToolResult, any, error) {
+	mode := normalizeMode(args.Mode)
+
+	searchPath := v.GetPath()
+	if args.Directory != "" {
+		searchPath = filepath.Join(v.GetPath(), args.Directory)
+	}
+	if !v.isPathSafe(searchPath) {
+		return nil, nil, fmt.Errorf("search path must be within vault")
+	}
+
+	types, err := v.propertyTypes()
+	if err != nil {
+		return nil, nil, err
+	}
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list properties: %v", err)
+	}
+
+	properties := collectPropertyUsage(notes, types)
+	violations := 0
+	for _, p := range properties {
+		violations += len(p.Violations)
+	}
+
+	if !isDetailedMode(mode) {
+		return compactResult(fmt.Sprintf("Found %d properties with %d type violations", len(properties), violations), false, map[string]any{
+			"properties": properties,
+		}, nil)
+	}
+
+	if len(properties) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: "No frontmatter properties found"},
+			},
+		}, nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Properties (%d)\n\n", len(properties))
+	sb.WriteString("| Property | Type | Notes | Violations |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	for _, p := range properties {
+		typ := p.Type
+		if typ == "" {
+			typ = p.Inferred + " (undeclared)"
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %d | %d |\n", p.Name, typ, p.Count, len(p.Violations))
+	}
+	if violations > 0 {
+		sb.WriteString("\n## Type Violations\n\n")
+		for _, p := range properties {
+			for _, violation := range p.Violations {
+				fmt.Fprintf(&sb, "- %s: `%s: %s` (%s)\n", violation.Path, p.Name, violation.Value, violation.Problem)
+			}
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: sb.String()},
+		},
+	}, nil, nil
+}
+
+// collectPropertyUsage tallies properties across notes, sorted by name.
+func collectPropertyUsage(notes []*indexedNote, types map[string]string) []PropertyUsage {
+	usage := make(map[string]*PropertyUsage)
+	inferred := make(map[string]map[string]int)
+
+	for _, note := range notes {
+		for _, key := range sortedFrontmatterKeys(note.Frontmatter) {
+			value := note.Frontmatter[key]
+			p, ok := usage[key]
+			if !ok {
+				p = &PropertyUsage{Name: key, Type: types[key]}
+				usage[key] = p
+				inferred[key] = make(map[string]int)
+			}
+			p.Count++
+			if t := propertyValueType(value); t != "" {
+				inferred[key][t]++
+			}
+			if p.Type == "" {
+				continue
+			}
+			if problem := checkPropertyValue(p.Type, value); problem != "" {
+				p.Violations = append(p.Violations, PropertyViolation{
+					Path:    note.RelPath,
+					Value:   frontmatterDisplay(value),
+					Problem: problem,
+				})
+			}
+		}
+	}
+
+	properties := make([]PropertyUsage, 0, len(usage))
+	for key, p := range usage {
+		if p.Type == "" {
+			p.Inferred = mostCommonType(inferred[key])
+		}
+		properties = append(properties, *p)
+	}
+	sort.Slice(properties, func(i, j int) bool {
+		return properties[i].Name < properties[j].Name
+	})
+	return properties
+}
+
+func mostCommonType(counts map[string]int) string {
+	best, bestCount := "", 0
+	for t, n := range counts {
+		if n > bestCount || (n == bestCount && t < best) {
+			best, bestCount = t, n
+		}
+	}
+	return best
+}
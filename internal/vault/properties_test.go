@@ -0,0 +1,125 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const testPropertyTypes = `{"types": {"priority": "number", "done": "checkbox", "due": "date", "start": "datetime", "owners": "multitext", "title": "text"}}`
+
+func TestCoercePropertyValue(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, ".obsidian/types.json", testPropertyTypes)
+	writeTestFile(t, dir, "note.md", "---\ntitle: Plan\n---\n# Body")
+
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{"priority", " 2 ", "priority: 2\n"},
+		{"done", "yes", "done: true\n"},
+		{"due", "2026-11-01", "due: 2026-11-01\n"},
+		{"start", "2026-11-01 09:30", "start: 2026-11-01T09:30\n"},
+		{"owners", "Alice, Bob", "owners:\n  - Alice\n  - Bob\n"},
+		{"tags", `["#work", "client"]`, "tags:\n  - work\n  - client\n"},
+		{"title", "2024", "title: \"2024\"\n"},
+		{"untyped", "3", "untyped: 3\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			_, _, err := v.SetFrontmatterHandler(context.Background(), nil, SetFrontmatterArgs{Path: "note.md", Key: tt.key, Value: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := readTestFile(t, dir, "note.md"); !strings.Contains(got, tt.want) {
+				t.Errorf("note = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFrontmatterRejectsMistypedValues(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, ".obsidian/types.json", testPropertyTypes)
+	original := "---\ntitle: Plan\n---\n# Body"
+	writeTestFile(t, dir, "a.md", original)
+	writeTestFile(t, dir, "b.md", original)
+	ctx := context.Background()
+
+	for key, value := range map[string]string{
+		"priority": "high",
+		"done":     "maybe",
+		"due":      "next week",
+		"owners":   `{"a": 1}`,
+		"title":    `["a"]`,
+	} {
+		if _, _, err := v.SetFrontmatterHandler(ctx, nil, SetFrontmatterArgs{Path: "a.md", Key: key, Value: value}); err == nil {
+			t.Errorf("expected error setting %s=%q", key, value)
+		}
+	}
+
+	_, _, err := v.BulkSetFrontmatterHandler(ctx, nil, BulkSetFrontmatterArgs{Paths: "a.md,b.md", Key: "priority", Value: "high"})
+	if err == nil || !strings.Contains(err.Error(), "number") {
+		t.Errorf("expected bulk set to reject a non-number, got %v", err)
+	}
+	for _, name := range []string{"a.md", "b.md"} {
+		if got := readTestFile(t, dir, name); got != original {
+			t.Errorf("%s was modified:\n%s", name, got)
+		}
+	}
+}
+
+func TestListProperties(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, ".obsidian/types.json", testPropertyTypes)
+	writeTestFile(t, dir, "a.md", "---\npriority: 2\ndue: 2026-11-01\ntags: [x]\nrating: 4\ntitle: 2\n---\n")
+	writeTestFile(t, dir, "b.md", "---\npriority: high\ndue: soon\ntags: solo\ntitle: true\n---\n")
+	writeTestFile(t, dir, "c.md", "---\npriority:\n---\n")
+
+	result, _, err := v.ListPropertiesHandler(context.Background(), nil, ListPropertiesArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Data struct {
+			Properties []PropertyUsage `json:"properties"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]PropertyUsage)
+	for _, p := range envelope.Data.Properties {
+		byName[p.Name] = p
+	}
+	if p := byName["priority"]; p.Type != "number" || p.Count != 3 || len(p.Violations) != 1 || p.Violations[0].Path != "b.md" {
+		t.Errorf("unexpected priority usage: %+v", p)
+	}
+	if p := byName["due"]; p.Type != "date" || len(p.Violations) != 1 {
+		t.Errorf("unexpected due usage: %+v", p)
+	}
+	if p := byName["tags"]; p.Type != "tags" || len(p.Violations) != 1 || p.Violations[0].Value != "solo" {
+		t.Errorf("unexpected tags usage: %+v", p)
+	}
+	if p := byName["title"]; p.Type != "text" || len(p.Violations) != 0 {
+		t.Errorf("unquoted scalars should be valid text: %+v", p)
+	}
+	if p := byName["rating"]; p.Type != "" || p.Inferred != "number" || p.Count != 1 {
+		t.Errorf("unexpected rating usage: %+v", p)
+	}
+
+	result, _, err = v.ListPropertiesHandler(context.Background(), nil, ListPropertiesArgs{Mode: "detailed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "| priority | number | 3 | 1 |") || !strings.Contains(text, "- b.md: `priority: high` (expected number, got text)") {
+		t.Errorf("unexpected detailed output:\n%s", text)
+	}
+}
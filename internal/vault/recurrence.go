@@ -0,0 +1,311 @@
+package vault
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recurrenceRule is a parsed Tasks plugin recurrence rule such as
+// "every 2 weeks on Monday, Friday" or "every month on the last Friday".
+type recurrenceRule struct {
+	interval int
+	unit     string         // day, week, month or year
+	weekdays []time.Weekday // week rules limited to these days
+	monthDay int            // day of the month, -1 for the last day
+	nth      int            // nth weekday of the month, -1 for the last
+	nthDay   time.Weekday
+	month    time.Month // year rules limited to this month
+	whenDone bool       // count from the completion date, not the task's dates
+}
+
+var (
+	recurrenceIntervalRegex = regexp.MustCompile(`^(\d+|other)\s+`)
+	ordinalRegex            = regexp.MustCompile(`^(\d+)(?:st|nd|rd|th)?$`)
+	weekdayNames            = map[string]time.Weekday{
+		"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+		"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	}
+	cancelledStampRegex = regexp.MustCompile(`[ \t]*❌\x{FE0F}?\s*\d{4}-\d{2}-\d{2}`)
+	taskIDStampRegex    = regexp.MustCompile(`[ \t]*🆔\x{FE0F}?\s*[a-zA-Z0-9_-]+`)
+	ordinalWords        = map[string]int{
+		"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5, "last": -1,
+	}
+)
+
+// parseRecurrence parses a recurrence rule as written after 🔁.
+func parseRecurrence(text string) (*recurrenceRule, error) {
+	rule := &recurrenceRule{interval: 1}
+	s := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	if trimmed, ok := strings.CutSuffix(s, " when done"); ok {
+		s, rule.whenDone = trimmed, true
+	}
+	rest, ok := strings.CutPrefix(s, "every ")
+	if !ok {
+		return nil, fmt.Errorf("recurrence must start with 'every': %s", text)
+	}
+
+	if m := recurrenceIntervalRegex.FindStringSubmatch(rest); m != nil {
+		rule.interval = 2
+		if m[1] != "other" {
+			rule.interval, _ = strconv.Atoi(m[1])
+		}
+		rest = rest[len(m[0]):]
+	}
+	if rule.interval < 1 {
+		return nil, fmt.Errorf("invalid recurrence interval: %s", text)
+	}
+
+	unit, on, _ := strings.Cut(rest, " on ")
+	unit = strings.TrimSpace(unit)
+	switch {
+	case unit == "day" || unit == "days":
+		rule.unit = "day"
+	case unit == "week" || unit == "weeks":
+		rule.unit = "week"
+	case unit == "weekday":
+		rule.unit = "week"
+		rule.weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	case unit == "month" || unit == "months":
+		rule.unit = "month"
+	case unit == "year" || unit == "years":
+		rule.unit = "year"
+	default:
+		if month, ok := parseMonthName(unit); ok {
+			rule.unit, rule.month = "year", month
+		} else if days, ok := parseWeekdays(unit); ok {
+			rule.unit, rule.weekdays = "week", days
+		} else {
+			return nil, fmt.Errorf("unsupported recurrence: %s", text)
+		}
+	}
+
+	if on == "" {
+		return rule, nil
+	}
+	switch rule.unit {
+	case "week":
+		days, ok := parseWeekdays(on)
+		if !ok {
+			return nil, fmt.Errorf("unsupported recurrence: %s", text)
+		}
+		rule.weekdays = days
+	case "month", "year":
+		if !rule.parseMonthPosition(on) {
+			return nil, fmt.Errorf("unsupported recurrence: %s", text)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported recurrence: %s", text)
+	}
+	return rule, nil
+}
+
+// parseMonthPosition parses the day within a month, such as "the 15th",
+// "the last", "the last day" or "the 2nd tuesday".
+func (r *recurrenceRule) parseMonthPosition(on string) bool {
+	fields := strings.Fields(strings.TrimPrefix(on, "the "))
+	if len(fields) == 0 || len(fields) > 2 {
+		return false
+	}
+	n, ok := ordinalWords[fields[0]]
+	if !ok {
+		m := ordinalRegex.FindStringSubmatch(fields[0])
+		if m == nil {
+			return false
+		}
+		n, _ = strconv.Atoi(m[1])
+	}
+	if len(fields) == 1 || fields[1] == "day" {
+		if n == 0 || n > 31 {
+			return false
+		}
+		r.monthDay = n
+		return true
+	}
+	day, ok := weekdayNames[strings.TrimSuffix(fields[1], "s")]
+	if !ok || n == 0 || n > 5 {
+		return false
+	}
+	r.nth, r.nthDay = n, day
+	return true
+}
+
+// parseWeekdays parses "monday", "monday and friday" or "monday, wednesday
+// and friday".
+func parseWeekdays(s string) ([]time.Weekday, bool) {
+	s = strings.ReplaceAll(s, " and ", ",")
+	var days []time.Weekday
+	for _, name := range strings.Split(s, ",") {
+		day, ok := weekdayNames[strings.TrimSpace(name)]
+		if !ok {
+			return nil, false
+		}
+		days = append(days, day)
+	}
+	return days, len(days) > 0
+}
+
+func parseMonthName(s string) (time.Month, bool) {
+	for m := time.January; m <= time.December; m++ {
+		if strings.EqualFold(s, m.String()) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// next returns the first occurrence of the rule after ref.
+func (r *recurrenceRule) next(ref time.Time) time.Time {
+	switch r.unit {
+	case "day":
+		return ref.AddDate(0, 0, r.interval)
+	case "week":
+		if len(r.weekdays) == 0 {
+			return ref.AddDate(0, 0, 7*r.interval)
+		}
+		// Weeks start on Monday; only every interval-th week counts.
+		refWeek := mondayIndex(ref) / 7
+		for d := ref.AddDate(0, 0, 1); ; d = d.AddDate(0, 0, 1) {
+			if (mondayIndex(d)/7-refWeek)%r.interval == 0 && containsWeekday(r.weekdays, d.Weekday()) {
+				return d
+			}
+		}
+	case "month":
+		if r.monthDay == 0 && r.nth == 0 {
+			return addMonthsClamped(ref, r.interval)
+		}
+		for k := 0; k < 100; k++ {
+			first := time.Date(ref.Year(), ref.Month()+time.Month(k*r.interval), 1, 0, 0, 0, 0, ref.Location())
+			if d, ok := r.dayInMonth(first, ref.Day()); ok && d.After(ref) {
+				return d
+			}
+		}
+	case "year":
+		if r.month == 0 && r.monthDay == 0 && r.nth == 0 {
+			return addMonthsClamped(ref, 12*r.interval)
+		}
+		month := r.month
+		if month == 0 {
+			month = ref.Month()
+		}
+		for k := 0; k < 100; k++ {
+			first := time.Date(ref.Year()+k*r.interval, month, 1, 0, 0, 0, 0, ref.Location())
+			if d, ok := r.dayInMonth(first, ref.Day()); ok && d.After(ref) {
+				return d
+			}
+		}
+	}
+	return ref
+}
+
+// dayInMonth returns the rule's day in the month starting at first, using
+// day when the rule names none. Months too short for the day are skipped.
+func (r *recurrenceRule) dayInMonth(first time.Time, day int) (time.Time, bool) {
+	last := first.AddDate(0, 1, -1).Day()
+	switch {
+	case r.nth > 0:
+		offset := (int(r.nthDay) - int(first.Weekday()) + 7) % 7
+		day = 1 + offset + 7*(r.nth-1)
+	case r.nth < 0:
+		lastDate := first.AddDate(0, 1, -1)
+		day = last - (int(lastDate.Weekday())-int(r.nthDay)+7)%7
+	case r.monthDay < 0:
+		day = last
+	case r.monthDay > 0:
+		day = r.monthDay
+	}
+	if day > last {
+		return time.Time{}, false
+	}
+	return first.AddDate(0, 0, day-1), true
+}
+
+// mondayIndex counts days from a Monday long ago, so that dividing by 7
+// numbers weeks starting on Monday.
+func mondayIndex(t time.Time) int {
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix()/86400) + 3
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// addMonthsClamped adds months to t, moving to the last day of the month
+// when t's day does not exist there (Jan 31 + 1 month = Feb 28 or 29).
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	day := min(t.Day(), first.AddDate(0, 1, -1).Day())
+	return first.AddDate(0, 0, day-1)
+}
+
+// taskShiftedDates are the task dates moved to the next occurrence.
+var taskShiftedDates = []*regexp.Regexp{dueDateRegex, scheduledDateRegex, startDateRegex}
+
+// nextOccurrence returns the line for the next occurrence of a recurring
+// task, as the Tasks plugin creates it: the due date (or scheduled, or start
+// date) moves to the rule's next date, the other dates keep their distance
+// from it, and the new task is open with no done date, 🆔 or block ID. It
+// returns "" when the task does not recur.
+func nextOccurrence(line string, task *Task, today time.Time) (string, error) {
+	if task.Recurrence == nil {
+		return "", nil
+	}
+	rule, err := parseRecurrence(*task.Recurrence)
+	if err != nil {
+		return "", err
+	}
+
+	var ref time.Time
+	for _, date := range []*string{task.DueDate, task.ScheduledDate, task.StartDate} {
+		if date == nil {
+			continue
+		}
+		if t, err := time.ParseInLocation("2006-01-02", *date, time.Local); err == nil {
+			ref = t
+			break
+		}
+	}
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+
+	next := setTaskDoneDate(setTaskStatus(line, " "), "")
+	next = strings.TrimRight(cancelledStampRegex.ReplaceAllString(next, ""), " \t")
+	// IDs must stay unique, so the new task inherits neither its 🆔 nor its
+	// block ID.
+	next = strings.TrimRight(taskIDStampRegex.ReplaceAllString(next, ""), " \t")
+	next = strings.TrimRight(blockIDRegex.ReplaceAllString(next, ""), " \t")
+	next = replaceTaskDate(next, createdDateRegex, func(time.Time) time.Time { return today })
+
+	if ref.IsZero() {
+		return next, nil
+	}
+	from := ref
+	if rule.whenDone {
+		from = today
+	}
+	days := int(math.Round(rule.next(from).Sub(ref).Hours() / 24))
+	for _, re := range taskShiftedDates {
+		next = replaceTaskDate(next, re, func(t time.Time) time.Time { return t.AddDate(0, 0, days) })
+	}
+	return next, nil
+}
+
+// replaceTaskDate rewrites the date captured by re in line.
+func replaceTaskDate(line string, re *regexp.Regexp, shift func(time.Time) time.Time) string {
+	loc := re.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line
+	}
+	t, err := time.ParseInLocation("2006-01-02", line[loc[2]:loc[3]], time.Local)
+	if err != nil {
+		return line
+	}
+	return line[:loc[2]] + shift(t).Format("2006-01-02") + line[loc[3]:]
+}
@@ -0,0 +1,142 @@
+package vault
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRecurrenceNext(t *testing.T) {
+	tests := []struct {
+		rule string
+		ref  string
+		want string
+	}{
+		{"every day", "2024-01-01", "2024-01-02"},
+		{"every 3 days", "2024-01-01", "2024-01-04"},
+		{"every week", "2024-01-01", "2024-01-08"},
+		{"every other week", "2024-01-01", "2024-01-15"},
+		{"every week on Monday", "2024-01-03", "2024-01-08"},
+		{"every week on Tuesday, Thursday", "2024-01-02", "2024-01-04"},
+		{"every Monday and Friday", "2024-01-05", "2024-01-08"},
+		{"every 2 weeks on Friday", "2024-01-05", "2024-01-19"},
+		{"every weekday", "2024-01-05", "2024-01-08"},
+		{"every month", "2024-01-31", "2024-02-29"},
+		{"every 3 months", "2024-01-15", "2024-04-15"},
+		{"every month on the 15th", "2024-01-20", "2024-02-15"},
+		{"every month on the 31st", "2024-01-31", "2024-03-31"},
+		{"every month on the last day", "2024-01-31", "2024-02-29"},
+		{"every month on the 2nd Tuesday", "2024-01-09", "2024-02-13"},
+		{"every month on the last Friday", "2024-01-26", "2024-02-23"},
+		{"every year", "2024-02-29", "2025-02-28"},
+		{"every January on the 15th", "2024-03-01", "2025-01-15"},
+		{"Every 2 Days When Done", "2024-01-01", "2024-01-03"},
+	}
+	for _, tt := range tests {
+		rule, err := parseRecurrence(tt.rule)
+		if err != nil {
+			t.Errorf("parseRecurrence(%q): %v", tt.rule, err)
+			continue
+		}
+		ref, _ := time.Parse("2006-01-02", tt.ref)
+		if got := rule.next(ref).Format("2006-01-02"); got != tt.want {
+			t.Errorf("%q after %s = %s, want %s", tt.rule, tt.ref, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"daily", "every fortnight", "every week on Funday", "every 0 days", "every day on the 3rd"} {
+		if _, err := parseRecurrence(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	today := time.Date(2024, 1, 10, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		name, line, want string
+	}{
+		{
+			name: "due date moves and others keep their offset",
+			line: "- [ ] Report 🔁 every week on Monday ➕ 2023-12-20 🛫 2023-12-29 ⏳ 2024-01-01 📅 2024-01-03 🆔 rep ^blk",
+			want: "- [ ] Report 🔁 every week on Monday ➕ 2024-01-10 🛫 2024-01-03 ⏳ 2024-01-06 📅 2024-01-08",
+		},
+		{
+			name: "scheduled date is the reference without a due date",
+			line: "  - [ ] Plants 🔁 every 3 days ⏳ 2024-01-01 ❌ 2024-01-02",
+			want: "  - [ ] Plants 🔁 every 3 days ⏳ 2024-01-04",
+		},
+		{
+			name: "when done counts from today",
+			line: "- [ ] Haircut 🔁 every 4 weeks when done 📅 2023-11-01",
+			want: "- [ ] Haircut 🔁 every 4 weeks when done 📅 2024-02-07",
+		},
+		{
+			name: "no dates",
+			line: "- [ ] Stretch 🔁 every day #health",
+			want: "- [ ] Stretch 🔁 every day #health",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextOccurrence(tt.line, ParseTask(tt.line, 1), today)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+
+	if got, err := nextOccurrence("- [ ] Once 📅 2024-01-01", ParseTask("- [ ] Once 📅 2024-01-01", 1), today); err != nil || got != "" {
+		t.Errorf("expected no next occurrence for a one-off task, got %q, %v", got, err)
+	}
+}
+
+func TestCompletingRecurringTaskKeepsBlockIDUnique(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "todo.md", "- [ ] Water plants 🔁 every week 📅 2026-10-12 ^plants")
+
+	if _, _, err := v.ToggleTaskHandler(context.Background(), nil, ToggleTaskArgs{Path: "todo.md", Line: 1}); err != nil {
+		t.Fatal(err)
+	}
+	got := readTestFile(t, dir, "todo.md")
+	if n := strings.Count(got, "^plants"); n != 1 {
+		t.Fatalf("block ID appears %d times:\n%s", n, got)
+	}
+	if lines := strings.Split(got, "\n"); lines[0] != "- [ ] Water plants 🔁 every week 📅 2026-10-19" || !strings.HasSuffix(lines[1], " ^plants") {
+		t.Errorf("block ID should stay on the completed task:\n%s", got)
+	}
+}
+
+func TestCompletingRecurringTask(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "todo.md", "# Chores\n- [ ] Bins 🔁 every week 📅 2024-01-01\n- [ ] Bills 🔁 every month on the 1st 📅 2024-01-01 🏁 delete\n- [ ] Odd 🔁 every blue moon 📅 2024-01-01")
+	today := time.Now().Format("2006-01-02")
+
+	result, _, err := v.ToggleTaskHandler(context.Background(), nil, ToggleTaskArgs{Path: "todo.md", Line: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "next occurrence on L2") {
+		t.Errorf("expected next occurrence in result, got %q", text)
+	}
+	if _, _, err := v.CompleteTasksHandler(context.Background(), nil, CompleteTasksArgs{Path: "todo.md", Texts: "Bills,Odd"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"# Chores",
+		"- [ ] Bins 🔁 every week 📅 2024-01-08",
+		"- [x] Bins 🔁 every week 📅 2024-01-01 ✅ " + today,
+		"- [ ] Bills 🔁 every month on the 1st 📅 2024-02-01 🏁 delete",
+		"- [x] Odd 🔁 every blue moon 📅 2024-01-01 ✅ " + today,
+	}, "\n")
+	if got := readTestFile(t, dir, "todo.md"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
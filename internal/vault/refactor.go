@@ -324,8 +324,9 @@ func (v *Vault) ExtractSectionHandler(ctx context.Context, req *mcp.CallToolRequ
 	}
 
 	// Determine output path
+	settings := v.obsidianSettings()
 	if output == "" {
-		output = sanitizeFilename(heading) + ".md"
+		output = settings.newNotePath(sanitizeFilename(heading)+".md", path)
 	}
 	if !strings.HasSuffix(output, ".md") {
 		output += ".md"
@@ -358,8 +359,7 @@ func (v *Vault) ExtractSectionHandler(ctx context.Context, req *mcp.CallToolRequ
 
 		if addLink {
 			// Add link to the extracted note
-			noteName := strings.TrimSuffix(filepath.Base(output), ".md")
-			linkText := fmt.Sprintf("\n\nSee: [[%s]]\n", noteName)
+			linkText := fmt.Sprintf("\n\nSee: %s\n", settings.linkTo(path, output))
 			newOriginal += linkText
 		}
 
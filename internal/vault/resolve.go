@@ -0,0 +1,285 @@
+package vault
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// blockIDRegex matches a block identifier at the end of a line: "text ^id",
+// or "^id" alone on the line after a list or table.
+var blockIDRegex = regexp.MustCompile(`(?:^|\s)\^([A-Za-z0-9-]+)\s*$`)
+
+// extractBlockIDs returns the block identifiers defined in content.
+func extractBlockIDs(content string) []string {
+	var ids []string
+	for _, line := range strings.Split(content, "\n") {
+		if m := blockIDRegex.FindStringSubmatch(line); m != nil {
+			ids = append(ids, m[1])
+		}
+	}
+	return ids
+}
+
+// linkRef is the target of a wikilink split into its parts:
+// [[Path#Heading|Display]] or [[Path#^block|Display]].
+type linkRef struct {
+	Path    string // as written, without subpath; empty for links within the same note
+	Heading string // innermost heading of a #A#B subpath
+	Block   string // block id of a #^id subpath
+	Display string
+}
+
+// parseLinkRef splits the inside of a [[...]] link.
+func parseLinkRef(raw string) linkRef {
+	var ref linkRef
+	if idx := strings.Index(raw, "|"); idx >= 0 {
+		ref.Display = strings.TrimSpace(raw[idx+1:])
+		raw = raw[:idx]
+	}
+	// A pipe escaped inside a table leaves a trailing backslash.
+	raw = strings.TrimSuffix(strings.TrimSpace(raw), `\`)
+
+	if idx := strings.Index(raw, "#"); idx >= 0 {
+		subpath := raw[idx+1:]
+		raw = raw[:idx]
+		if strings.HasPrefix(subpath, "^") {
+			ref.Block = strings.TrimSpace(subpath[1:])
+		} else {
+			parts := strings.Split(subpath, "#")
+			ref.Heading = strings.TrimSpace(parts[len(parts)-1])
+		}
+	}
+	ref.Path = strings.TrimSpace(raw)
+	return ref
+}
+
+// linkResolution is the outcome of resolving one link from a source note.
+type linkResolution struct {
+	Raw     string
+	Ref     linkRef
+	Target  string // vault-relative path of the linked file, empty if unresolved
+	ByAlias bool   // resolved through a frontmatter alias rather than a file name
+	Missing string // why the link is broken: "note", "heading" or "block"
+}
+
+// broken reports whether the link points at a missing file or anchor.
+func (r linkResolution) broken() bool {
+	return r.Missing != ""
+}
+
+// anchorProblem describes a missing heading or block for reports. It is
+// empty when the link is fine or the whole note is missing.
+func (r linkResolution) anchorProblem() string {
+	switch r.Missing {
+	case "heading":
+		return fmt.Sprintf(" (no heading %q in %s)", r.Ref.Heading, r.Target)
+	case "block":
+		return fmt.Sprintf(" (no block ^%s in %s)", r.Ref.Block, r.Target)
+	}
+	return ""
+}
+
+// linkResolver resolves links the way Obsidian does. Matching is
+// case-insensitive; a link may give the full path, a path relative to the
+// linking note, any trailing part of the path (the shortest unique form is
+// just the file name), or a frontmatter alias.
+type linkResolver struct {
+	paths   map[string]string   // lowercased slash path -> vault-relative path
+	byName  map[string][]string // lowercased base name -> candidate paths
+	aliases map[string][]string // lowercased alias -> note paths
+	notes   map[string]*indexedNote
+}
+
+// newLinkResolver indexes every file in the vault that walks do not ignore.
+func (v *Vault) newLinkResolver() (*linkResolver, error) {
+	root := v.GetPath()
+	r := &linkResolver{
+		paths:   make(map[string]string),
+		byName:  make(map[string][]string),
+		aliases: make(map[string][]string),
+		notes:   make(map[string]*indexedNote),
+	}
+
+	err := v.walkVault(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		relPath, _ := filepath.Rel(root, p)
+		r.addFile(relPath)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := v.indexedNotes(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		r.notes[note.RelPath] = note
+		for _, alias := range noteAliases(note.Frontmatter) {
+			key := strings.ToLower(alias)
+			r.aliases[key] = append(r.aliases[key], note.RelPath)
+		}
+	}
+	for _, candidates := range r.byName {
+		sort.Slice(candidates, func(i, j int) bool {
+			if len(candidates[i]) != len(candidates[j]) {
+				return len(candidates[i]) < len(candidates[j])
+			}
+			return candidates[i] < candidates[j]
+		})
+	}
+	return r, nil
+}
+
+func (r *linkResolver) addFile(relPath string) {
+	slash := strings.ToLower(filepath.ToSlash(relPath))
+	r.paths[slash] = relPath
+	name := path.Base(slash)
+	r.byName[name] = append(r.byName[name], relPath)
+}
+
+// noteAliases reads the aliases (or legacy alias) property as a list.
+func noteAliases(fm Frontmatter) []string {
+	var aliases []string
+	for _, key := range []string{"aliases", "alias"} {
+		value, ok := frontmatterLookup(fm, key)
+		if !ok {
+			continue
+		}
+		switch val := value.(type) {
+		case []any:
+			for _, item := range val {
+				if s := strings.TrimSpace(frontmatterText(item)); s != "" {
+					aliases = append(aliases, s)
+				}
+			}
+		case string:
+			for _, item := range strings.Split(val, ",") {
+				if s := strings.TrimSpace(item); s != "" {
+					aliases = append(aliases, s)
+				}
+			}
+		}
+	}
+	return aliases
+}
+
+// resolve resolves the inside of a [[...]] link written in sourcePath.
+func (r *linkResolver) resolve(raw, sourcePath string) linkResolution {
+	res := linkResolution{Raw: raw, Ref: parseLinkRef(raw)}
+	if res.Ref.Path == "" {
+		res.Target = sourcePath
+	} else {
+		res.Target, res.ByAlias = r.resolvePath(res.Ref.Path, sourcePath)
+	}
+
+	switch {
+	case res.Target == "":
+		res.Missing = "note"
+	case res.Ref.Heading != "" && !r.hasHeading(res.Target, res.Ref.Heading):
+		res.Missing = "heading"
+	case res.Ref.Block != "" && !r.hasBlock(res.Target, res.Ref.Block):
+		res.Missing = "block"
+	}
+	return res
+}
+
+// resolvePath finds the file a link path points at, preferring an exact or
+// relative path, then the best file-name match, then an alias.
+func (r *linkResolver) resolvePath(linkPath, sourcePath string) (string, bool) {
+	linkPath = strings.ToLower(filepath.ToSlash(linkPath))
+	sourceDir := strings.ToLower(path.Dir(filepath.ToSlash(sourcePath)))
+
+	if strings.HasPrefix(linkPath, "./") || strings.HasPrefix(linkPath, "../") {
+		return r.exact(path.Join(sourceDir, linkPath)), false
+	}
+	linkPath = strings.TrimPrefix(linkPath, "/")
+	if target := r.exact(linkPath); target != "" {
+		return target, false
+	}
+	// Markdown links with the "relative" link format omit the leading ./
+	if sourceDir != "." {
+		if target := r.exact(path.Join(sourceDir, linkPath)); target != "" {
+			return target, false
+		}
+	}
+
+	for _, candidate := range []string{linkPath + ".md", linkPath} {
+		var matches []string
+		for _, p := range r.byName[path.Base(candidate)] {
+			slash := strings.ToLower(filepath.ToSlash(p))
+			if slash == candidate || strings.HasSuffix(slash, "/"+candidate) {
+				matches = append(matches, p)
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		// Obsidian prefers a match next to the linking note.
+		for _, p := range matches {
+			if strings.ToLower(path.Dir(filepath.ToSlash(p))) == sourceDir {
+				return p, false
+			}
+		}
+		return matches[0], false
+	}
+
+	if notes := r.aliases[linkPath]; len(notes) > 0 {
+		return notes[0], true
+	}
+	return "", false
+}
+
+// exact looks up a full vault path, with or without the .md extension.
+func (r *linkResolver) exact(slashPath string) string {
+	if target, ok := r.paths[slashPath+".md"]; ok {
+		return target
+	}
+	return r.paths[slashPath]
+}
+
+// hasHeading reports whether the note at relPath has a matching heading.
+// Non-markdown targets (PDF pages and the like) are not checked.
+func (r *linkResolver) hasHeading(relPath, heading string) bool {
+	note, ok := r.notes[relPath]
+	if !ok {
+		return !strings.HasSuffix(relPath, ".md")
+	}
+	want := normalizeHeadingRef(heading)
+	for _, h := range note.Headings {
+		if normalizeHeadingRef(h.Text) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBlock reports whether the note at relPath defines a block id.
+func (r *linkResolver) hasBlock(relPath, id string) bool {
+	note, ok := r.notes[relPath]
+	if !ok {
+		return !strings.HasSuffix(relPath, ".md")
+	}
+	for _, blockID := range note.BlockIDs {
+		if strings.EqualFold(blockID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// headingRefReplacer mirrors the characters Obsidian drops from heading links.
+var headingRefReplacer = strings.NewReplacer("#", " ", "|", " ", "^", " ", ":", " ", "%%", " ", "[[", " ", "]]", " ")
+
+// normalizeHeadingRef makes a heading and a link subpath comparable.
+func normalizeHeadingRef(heading string) string {
+	return strings.ToLower(strings.Join(strings.Fields(headingRefReplacer.Replace(heading)), " "))
+}
@@ -0,0 +1,119 @@
+package vault
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func setupLinkVault(t *testing.T) (*Vault, string) {
+	t.Helper()
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "projects/Plan.md", "---\naliases: [Roadmap, \"Q3 Plan\"]\n---\n# Plan\n\n## Goals: 2026\n\nShip it. ^ship\n")
+	writeTestFile(t, dir, "old/archive/Plan.md", "# Old plan\n")
+	writeTestFile(t, dir, "projects/sub/Task.md", "See [[../Plan]] and [[./Notes]].\n")
+	writeTestFile(t, dir, "projects/sub/Notes.md", "# Notes\n")
+	writeTestFile(t, dir, "assets/diagram.png", "png")
+	writeTestFile(t, dir, "index.md", strings.Join([]string{
+		"[[Plan]]",
+		"[[archive/Plan|old]]",
+		"[[Plan#Goals 2026]]",
+		"[[Plan#Goals: 2026]]",
+		"[[Plan#^ship]]",
+		"[[Plan#Missing]]",
+		"[[Plan#^nope]]",
+		"[[roadmap]]",
+		"[[diagram.png]]",
+		"[[Nowhere]]",
+		"[[#Local]]",
+		"",
+		"# Local",
+	}, "\n"))
+	return v, dir
+}
+
+func TestLinkResolver(t *testing.T) {
+	v, _ := setupLinkVault(t)
+	r, err := v.newLinkResolver()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan := filepath.Join("projects", "Plan.md")
+	tests := []struct {
+		link    string
+		source  string
+		target  string
+		missing string
+	}{
+		{"Plan", "index.md", plan, ""},
+		{"Plan", filepath.Join("old", "archive", "x.md"), filepath.Join("old", "archive", "Plan.md"), ""},
+		{"archive/Plan", "index.md", filepath.Join("old", "archive", "Plan.md"), ""},
+		{"PROJECTS/plan.md", "index.md", plan, ""},
+		{"sub/Task", "index.md", filepath.Join("projects", "sub", "Task.md"), ""},
+		{"../Plan", filepath.Join("projects", "sub", "Task.md"), plan, ""},
+		{"./Notes", filepath.Join("projects", "sub", "Task.md"), filepath.Join("projects", "sub", "Notes.md"), ""},
+		{"Plan#Goals 2026", "index.md", plan, ""},
+		{"Plan#Plan#Goals: 2026", "index.md", plan, ""},
+		{"Plan#^ship", "index.md", plan, ""},
+		{"Plan#Missing", "index.md", plan, "heading"},
+		{"Plan#^nope", "index.md", plan, "block"},
+		{"q3 plan", "index.md", plan, ""},
+		{"diagram.png", "index.md", filepath.Join("assets", "diagram.png"), ""},
+		{"Nowhere", "index.md", "", "note"},
+		{"#Local", "index.md", "index.md", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			res := r.resolve(tt.link, tt.source)
+			if res.Target != tt.target || res.Missing != tt.missing {
+				t.Errorf("resolve(%q from %s) = %q missing=%q, want %q missing=%q", tt.link, tt.source, res.Target, res.Missing, tt.target, tt.missing)
+			}
+		})
+	}
+}
+
+func TestLinkHandlersUseResolver(t *testing.T) {
+	v, _ := setupLinkVault(t)
+	ctx := context.Background()
+
+	result, _, err := v.BrokenLinksHandler(ctx, nil, BrokenLinksArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"[[Plan#Missing]] (no heading", "[[Plan#^nope]] (no block ^nope", "[[Nowhere]]", "3 total in 1 files"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("broken links missing %q:\n%s", want, text)
+		}
+	}
+
+	result, _, err = v.BacklinksHandler(ctx, nil, GetBacklinksArgs{Path: "projects/Plan.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text = result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "Found 2 notes") || !strings.Contains(text, "## index.md (7 links)") || strings.Contains(text, "archive") {
+		t.Errorf("unexpected backlinks:\n%s", text)
+	}
+
+	result, _, err = v.ForwardLinksHandler(ctx, nil, ForwardLinksArgs{Path: "index.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text = result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "(8 existing, 3 broken)") || !strings.Contains(text, "[[roadmap]] → "+filepath.Join("projects", "Plan.md")+" (alias)") {
+		t.Errorf("unexpected forward links:\n%s", text)
+	}
+
+	graph, err := v.buildLinkGraph(v.GetPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := graph.notes[filepath.Join("old", "archive", "Plan")].incoming; n != 1 {
+		t.Errorf("archive/Plan incoming = %d, want 1", n)
+	}
+}
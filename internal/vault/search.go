@@ -3,7 +3,6 @@ package vault
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -37,39 +36,23 @@ func (v *Vault) SearchVaultHandler(ctx context.Context, req *mcp.CallToolRequest
 
 	queryLower := strings.ToLower(query)
 	var results []SearchResult
-	filesScanned := 0
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-		filesScanned++
-
-		lines := strings.Split(string(content), "\n")
-		relPath, _ := filepath.Rel(v.GetPath(), path)
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("search failed: %v", err)
+	}
+	filesScanned := len(notes)
 
-		for i, line := range lines {
+	for _, note := range notes {
+		for i, line := range note.Lines {
 			if strings.Contains(strings.ToLower(line), queryLower) {
 				results = append(results, SearchResult{
-					File:    relPath,
+					File:    note.RelPath,
 					Line:    i + 1,
 					Content: strings.TrimSpace(line),
 				})
 			}
 		}
-		return nil
-	})
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("search failed: %v", err)
 	}
 
 	if len(results) == 0 {
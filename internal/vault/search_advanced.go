@@ -67,30 +67,18 @@ func (v *Vault) SearchAdvancedHandler(ctx context.Context, req *mcp.CallToolRequ
 		return nil, nil, fmt.Errorf("empty search query")
 	}
 
-	var results []SearchResult
-
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		relPath, _ := filepath.Rel(v.GetPath(), path)
-		if matched, line, text := matchNoteByScope(searchIn, relPath, string(content), terms, operator); matched {
-			results = append(results, SearchResult{File: relPath, Line: line, Content: text})
-		}
-
-		return nil
-	})
-
+	notes, err := v.indexedNotes(searchPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("search failed: %v", err)
 	}
 
+	var results []SearchResult
+	for _, note := range notes {
+		if matched, line, text := matchNoteByScope(searchIn, note.RelPath, note.Content, terms, operator); matched {
+			results = append(results, SearchResult{File: note.RelPath, Line: line, Content: text})
+		}
+	}
+
 	if len(results) == 0 {
 		if !isDetailedMode(mode) {
 			return compactResult(
@@ -243,7 +231,7 @@ func (v *Vault) SearchDateHandler(ctx context.Context, req *mcp.CallToolRequest,
 
 	var results []dateResult
 
-	err = filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+	err = v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
 			return nil
 		}
@@ -288,36 +276,26 @@ func (v *Vault) SearchDateHandler(ctx context.Context, req *mcp.CallToolRequest,
 	}, nil, nil
 }
 
-// collectRegexMatches walks searchPath and returns all lines matching re.
+// collectRegexMatches returns all lines under searchPath matching re.
 func (v *Vault) collectRegexMatches(re *regexp.Regexp, searchPath string) ([]SearchResult, error) {
-	var results []SearchResult
-
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		lines := strings.Split(string(content), "\n")
-		relPath, _ := filepath.Rel(v.GetPath(), path)
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, err
+	}
 
-		for i, line := range lines {
+	var results []SearchResult
+	for _, note := range notes {
+		for i, line := range note.Lines {
 			if re.MatchString(line) {
 				results = append(results, SearchResult{
-					File:    relPath,
+					File:    note.RelPath,
 					Line:    i + 1,
 					Content: strings.TrimSpace(line),
 				})
 			}
 		}
-		return nil
-	})
-
-	return results, err
+	}
+	return results, nil
 }
 
 // SearchRegexHandler searches using regex
@@ -0,0 +1,110 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SearchQueryHandler evaluates an Obsidian search-syntax query against each note
+func (v *Vault) SearchQueryHandler(ctx context.Context, req *mcp.CallToolRequest, args SearchQueryArgs) (*mcp.CallToolResult, any, error) {
+	query := args.Query
+	dir := args.Directory
+	mode := normalizeMode(args.Mode)
+	limit := args.Limit
+	offset := args.Offset
+	if limit <= 0 {
+		limit = compactSearchResultLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	searchPath := v.GetPath()
+	if dir != "" {
+		searchPath = filepath.Join(v.GetPath(), dir)
+	}
+	if !v.isPathSafe(searchPath) {
+		return nil, nil, fmt.Errorf("search path must be within vault")
+	}
+
+	expr, err := parseObsidianQuery(query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid query: %v", err)
+	}
+
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("search failed: %v", err)
+	}
+
+	var results []SearchResult
+	for _, note := range notes {
+		matched, line := matchObsidianQuery(expr, note)
+		if !matched {
+			continue
+		}
+		r := SearchResult{File: note.RelPath, Line: line}
+		if line > 0 {
+			r.Content = strings.TrimSpace(note.Lines[line-1])
+		}
+		results = append(results, r)
+	}
+
+	totalMatches := len(results)
+	page := []SearchResult{}
+	if offset < totalMatches {
+		page = results[offset:]
+	}
+	truncated := false
+	if len(page) > limit {
+		page = page[:limit]
+		truncated = true
+	}
+
+	if !isDetailedMode(mode) {
+		next := map[string]any(nil)
+		if offset+len(page) < totalMatches {
+			next = map[string]any{
+				"tool": "search-vault",
+				"args": map[string]any{
+					"action":    "query",
+					"query":     query,
+					"directory": dir,
+					"offset":    offset + len(page),
+					"limit":     limit,
+					"mode":      modeCompact,
+				},
+			}
+		}
+		summary := fmt.Sprintf("Found %d notes matching %q", totalMatches, query)
+		if totalMatches == 0 {
+			summary = fmt.Sprintf("No matches found for: %s", query)
+		}
+		return compactResult(summary, truncated, map[string]any{
+			"query":         query,
+			"files_scanned": len(notes),
+			"total_matches": totalMatches,
+			"offset":        offset,
+			"returned":      len(page),
+			"matches":       page,
+		}, next)
+	}
+
+	if totalMatches == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("No matches found for: %s", query)},
+			},
+		}, nil, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatSearchResults(page, query)},
+		},
+	}, nil, nil
+}
@@ -0,0 +1,138 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RankedResult is a note scored by full-text relevance.
+type RankedResult struct {
+	File    string  `json:"file"`
+	Score   float64 `json:"score"`
+	Line    int     `json:"line,omitempty"`
+	Snippet string  `json:"snippet,omitempty"`
+}
+
+const (
+	defaultRankedLimit = 20
+	rankedSnippetLen   = 160
+)
+
+// SearchRankedHandler runs a BM25-ranked full-text search over the persisted index
+func (v *Vault) SearchRankedHandler(ctx context.Context, req *mcp.CallToolRequest, args SearchRankedArgs) (*mcp.CallToolResult, any, error) {
+	query := args.Query
+	dir := args.Directory
+	mode := normalizeMode(args.Mode)
+	limit := args.Limit
+	offset := args.Offset
+	if limit <= 0 {
+		limit = defaultRankedLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	searchPath := v.GetPath()
+	if dir != "" {
+		searchPath = filepath.Join(v.GetPath(), dir)
+	}
+	if !v.isPathSafe(searchPath) {
+		return nil, nil, fmt.Errorf("search path must be within vault")
+	}
+
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil, nil, fmt.Errorf("query has no searchable terms")
+	}
+
+	prefix, _ := filepath.Rel(v.GetPath(), searchPath)
+	hits, indexed, err := v.searchFullText(terms, prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("search failed: %v", err)
+	}
+
+	total := len(hits)
+	page := []ftsHit{}
+	if offset < total {
+		page = hits[offset:]
+	}
+	truncated := false
+	if len(page) > limit {
+		page = page[:limit]
+		truncated = true
+	}
+
+	termSet := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		termSet[t] = true
+	}
+	results := make([]RankedResult, 0, len(page))
+	for _, hit := range page {
+		r := RankedResult{File: hit.path, Score: math.Round(hit.score*1000) / 1000}
+		if note, err := v.indexedNote(hit.path); err == nil {
+			r.Line, r.Snippet = snippetFor(note, termSet, rankedSnippetLen)
+		}
+		results = append(results, r)
+	}
+
+	if !isDetailedMode(mode) {
+		next := map[string]any(nil)
+		if offset+len(results) < total {
+			next = map[string]any{
+				"tool": "search-vault",
+				"args": map[string]any{
+					"action":    "ranked",
+					"query":     query,
+					"directory": dir,
+					"offset":    offset + len(results),
+					"limit":     limit,
+					"mode":      modeCompact,
+				},
+			}
+		}
+		summary := fmt.Sprintf("Found %d notes ranked for %q", total, query)
+		if total == 0 {
+			summary = fmt.Sprintf("No matches found for: %s", query)
+		}
+		return compactResult(summary, truncated, map[string]any{
+			"query":         query,
+			"notes_indexed": indexed,
+			"total_matches": total,
+			"offset":        offset,
+			"returned":      len(results),
+			"results":       results,
+		}, next)
+	}
+
+	if total == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("No matches found for: %s", query)},
+			},
+		}, nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Found %d notes ranked for %q", total, query)
+	if offset > 0 || truncated {
+		fmt.Fprintf(&sb, " (showing %d-%d)", offset+1, offset+len(results))
+	}
+	sb.WriteString(":\n\n")
+	for i, r := range results {
+		fmt.Fprintf(&sb, "%d. %s (score %.3f)\n", offset+i+1, r.File, r.Score)
+		if r.Snippet != "" {
+			fmt.Fprintf(&sb, "   L%d: %s\n", r.Line, r.Snippet)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: sb.String()},
+		},
+	}, nil, nil
+}
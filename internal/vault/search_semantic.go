@@ -0,0 +1,182 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SemanticResult is a note scored by similarity of meaning, with the section
+// that matched best.
+type SemanticResult struct {
+	File    string  `json:"file"`
+	Score   float64 `json:"score"`
+	Heading string  `json:"heading,omitempty"`
+	Line    int     `json:"line,omitempty"`
+	Snippet string  `json:"snippet,omitempty"`
+}
+
+const defaultSimilarLimit = 10
+
+// semanticPrefix validates a directory filter and returns it relative to the
+// vault root.
+func (v *Vault) semanticPrefix(dir string) (string, error) {
+	searchPath := v.GetPath()
+	if dir != "" {
+		searchPath = filepath.Join(v.GetPath(), dir)
+	}
+	if !v.isPathSafe(searchPath) {
+		return "", fmt.Errorf("search path must be within vault")
+	}
+	prefix, _ := filepath.Rel(v.GetPath(), searchPath)
+	return prefix, nil
+}
+
+// semanticResults turns the top hits into results with snippets.
+func (v *Vault) semanticResults(hits []vectorHit, limit int) []SemanticResult {
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	results := make([]SemanticResult, 0, len(hits))
+	for _, hit := range hits {
+		r := SemanticResult{
+			File:    hit.path,
+			Score:   math.Round(hit.score*1000) / 1000,
+			Heading: hit.chunk.Heading,
+			Line:    hit.chunk.Line,
+		}
+		if note, err := v.indexedNote(hit.path); err == nil {
+			r.Line, r.Snippet = chunkSnippet(note, hit.chunk.Line, rankedSnippetLen)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// SemanticSearchHandler finds the notes whose content is closest in meaning
+// to a query, using the vector index
+func (v *Vault) SemanticSearchHandler(ctx context.Context, req *mcp.CallToolRequest, args SemanticSearchArgs) (*mcp.CallToolResult, any, error) {
+	query := strings.TrimSpace(args.Query)
+	if query == "" {
+		return nil, nil, fmt.Errorf("query is required")
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultRankedLimit
+	}
+	prefix, err := v.semanticPrefix(args.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var hits []vectorHit
+	var embedderName string
+	var indexed int
+	err = v.withVectorIndex(ctx, func(idx *vectorIndex, embedder Embedder) error {
+		vectors, err := embedder.Embed(ctx, []string{query})
+		if err != nil {
+			return fmt.Errorf("failed to embed query: %v", err)
+		}
+		hits = idx.rank(vectors[0], prefix, "", false)
+		embedderName, indexed = idx.Embedder, len(idx.Docs)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("semantic search failed: %v", err)
+	}
+
+	total := len(hits)
+	results := v.semanticResults(hits, limit)
+	summary := fmt.Sprintf("Found %d notes related to %q", total, query)
+	if total == 0 {
+		summary = fmt.Sprintf("No related notes found for: %s", query)
+	}
+
+	if !isDetailedMode(args.Mode) {
+		return compactResult(summary, total > len(results), map[string]any{
+			"query":         query,
+			"embedder":      embedderName,
+			"notes_indexed": indexed,
+			"total_matches": total,
+			"returned":      len(results),
+			"results":       results,
+		}, nil)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatSemanticResults(summary, results)},
+		},
+	}, nil, nil
+}
+
+// SimilarNotesHandler finds the notes whose content is closest in meaning to
+// a given note, using the vector index
+func (v *Vault) SimilarNotesHandler(ctx context.Context, req *mcp.CallToolRequest, args SimilarNotesArgs) (*mcp.CallToolResult, any, error) {
+	resolver, err := v.newLinkResolver()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve note: %v", err)
+	}
+	notePath, _ := resolver.resolvePath(args.Path, "")
+	if notePath == "" {
+		return nil, nil, fmt.Errorf("note not found: %s", args.Path)
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultSimilarLimit
+	}
+	prefix, err := v.semanticPrefix(args.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hits, err := v.relatedNotes(ctx, notePath, prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("similar notes failed: %v", err)
+	}
+
+	total := len(hits)
+	results := v.semanticResults(hits, limit)
+	summary := fmt.Sprintf("Found %d notes similar to %s", total, notePath)
+	if total == 0 {
+		summary = fmt.Sprintf("No similar notes found for: %s", notePath)
+	}
+
+	if !isDetailedMode(args.Mode) {
+		return compactResult(summary, total > len(results), map[string]any{
+			"path":          notePath,
+			"total_matches": total,
+			"returned":      len(results),
+			"results":       results,
+		}, nil)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatSemanticResults(summary, results)},
+		},
+	}, nil, nil
+}
+
+// formatSemanticResults formats semantic results as a numbered markdown list
+func formatSemanticResults(summary string, results []SemanticResult) string {
+	if len(results) == 0 {
+		return summary
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s:\n\n", summary)
+	for i, r := range results {
+		fmt.Fprintf(&sb, "%d. %s (similarity %.3f)\n", i+1, r.File, r.Score)
+		if r.Heading != "" {
+			fmt.Fprintf(&sb, "   Section: %s\n", r.Heading)
+		}
+		if r.Snippet != "" {
+			fmt.Fprintf(&sb, "   L%d: %s\n", r.Line, r.Snippet)
+		}
+	}
+	return sb.String()
+}
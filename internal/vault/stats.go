@@ -3,7 +3,6 @@ package vault
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -20,35 +19,40 @@ type vaultStats struct {
 	totalLines     int
 	totalTasks     int
 	completedTasks int
+	openTasks      int
+	statuses       taskStatuses
+	statusTypes    map[string]int // task count by status type
 	totalTags      map[string]int
 	totalLinks     int
 	folders        map[string]bool
 }
 
-// processNoteContent extracts statistics from a note's content
-func (s *vaultStats) processNoteContent(content string) {
-	lines := strings.Split(content, "\n")
-	s.totalLines += len(lines)
-	s.totalChars += utf8.RuneCountInString(content)
-	s.totalWords += len(strings.Fields(content))
+// processNote extracts statistics from an indexed note
+func (s *vaultStats) processNote(note *indexedNote) {
+	s.totalLines += len(note.Lines)
+	s.totalChars += utf8.RuneCountInString(note.Content)
+	s.totalWords += len(strings.Fields(note.Content))
 
 	// Count tasks
-	for _, line := range lines {
-		if task := ParseTask(line, 0); task != nil {
-			s.totalTasks++
-			if task.Completed {
-				s.completedTasks++
-			}
+	for _, task := range note.Tasks {
+		s.statuses.apply(&task)
+		s.totalTasks++
+		s.statusTypes[task.StatusType]++
+		if task.Completed {
+			s.completedTasks++
+		}
+		if task.isOpen() {
+			s.openTasks++
 		}
 	}
 
 	// Count tags
-	for _, tag := range ExtractTags(content) {
+	for _, tag := range note.Tags {
 		s.totalTags[tag]++
 	}
 
 	// Count wikilinks
-	s.totalLinks += len(ExtractWikilinks(content))
+	s.totalLinks += len(note.Links)
 }
 
 // formatStats builds the markdown output for vault statistics
@@ -83,9 +87,16 @@ func (s *vaultStats) formatTasks(sb *strings.Builder) {
 	sb.WriteString("\n## Tasks\n")
 	fmt.Fprintf(sb, "- **Total:** %d\n", s.totalTasks)
 	fmt.Fprintf(sb, "- **Completed:** %d\n", s.completedTasks)
-	fmt.Fprintf(sb, "- **Open:** %d\n", s.totalTasks-s.completedTasks)
+	fmt.Fprintf(sb, "- **Open:** %d\n", s.openTasks)
 	pct := float64(s.completedTasks) / float64(s.totalTasks) * 100
 	fmt.Fprintf(sb, "- **Completion:** %.1f%%\n", pct)
+
+	sb.WriteString("\n### By Status\n")
+	for _, st := range statusTypes {
+		if n := s.statusTypes[st.Type]; n > 0 {
+			fmt.Fprintf(sb, "- **%s:** %d\n", st.Name, n)
+		}
+	}
 }
 
 // formatTags writes tag statistics to the builder
@@ -140,42 +151,25 @@ func (v *Vault) VaultStatsHandler(ctx context.Context, req *mcp.CallToolRequest,
 	}
 
 	stats := &vaultStats{
-		totalTags: make(map[string]int),
-		folders:   make(map[string]bool),
+		totalTags:   make(map[string]int),
+		folders:     make(map[string]bool),
+		statuses:    v.taskStatuses(),
+		statusTypes: make(map[string]int),
 	}
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		if info.IsDir() {
-			if path != searchPath {
-				relPath, _ := filepath.Rel(v.GetPath(), path)
-				stats.folders[relPath] = true
-			}
-			return nil
-		}
-
-		if !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		stats.noteCount++
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		stats.processNoteContent(string(content))
-		return nil
-	})
-
+	scan, err := v.scanIndexed(searchPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to gather stats: %v", err)
 	}
 
+	for _, dir := range scan.dirs {
+		stats.folders[dir] = true
+	}
+	for _, note := range scan.notes {
+		stats.noteCount++
+		stats.processNote(note)
+	}
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: stats.formatStats(dir)},
@@ -3,7 +3,6 @@ package vault
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -102,25 +101,16 @@ func (v *Vault) SearchByTagsHandler(ctx context.Context, req *mcp.CallToolReques
 	}
 	var results []result
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		noteTags := ExtractTags(string(content))
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("search failed: %v", err)
+	}
 
+	for _, note := range notes {
 		// Check if note has all search tags (AND operation)
 		hasAll := true
 		noteTagsLower := make(map[string]bool)
-		for _, t := range noteTags {
+		for _, t := range note.Tags {
 			noteTagsLower[strings.ToLower(t)] = true
 		}
 		for _, searchTag := range searchTags {
@@ -131,14 +121,8 @@ func (v *Vault) SearchByTagsHandler(ctx context.Context, req *mcp.CallToolReques
 		}
 
 		if hasAll {
-			relPath, _ := filepath.Rel(v.GetPath(), path)
-			results = append(results, result{path: relPath, tags: noteTags})
+			results = append(results, result{path: note.RelPath, tags: note.Tags})
 		}
-		return nil
-	})
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("search failed: %v", err)
 	}
 
 	if len(results) == 0 {
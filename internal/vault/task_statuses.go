@@ -0,0 +1,149 @@
+package vault
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Task status types, as named by the Tasks plugin.
+const (
+	statusTypeTodo       = "TODO"
+	statusTypeInProgress = "IN_PROGRESS"
+	statusTypeDone       = "DONE"
+	statusTypeCancelled  = "CANCELLED"
+	statusTypeNonTask    = "NON_TASK"
+)
+
+// statusTypes lists the status types in the order the Tasks plugin sorts
+// them, with display names.
+var statusTypes = []struct {
+	Type string
+	Name string
+}{
+	{statusTypeInProgress, "In Progress"},
+	{statusTypeTodo, "Todo"},
+	{statusTypeDone, "Done"},
+	{statusTypeCancelled, "Cancelled"},
+	{statusTypeNonTask, "Non-Task"},
+}
+
+// TaskStatus is a checkbox status: the symbol between the brackets, its
+// name and type, and the symbol toggling moves it to. The JSON names match
+// the Tasks plugin's data.json.
+type TaskStatus struct {
+	Symbol     string `json:"symbol"`
+	Name       string `json:"name"`
+	NextSymbol string `json:"nextStatusSymbol"`
+	Type       string `json:"type"`
+}
+
+// taskStatuses is a set of statuses, looked up by symbol.
+type taskStatuses []TaskStatus
+
+// defaultTaskStatuses are the Tasks plugin's core and default custom
+// statuses.
+var defaultTaskStatuses = taskStatuses{
+	{Symbol: " ", Name: "Todo", NextSymbol: "x", Type: statusTypeTodo},
+	{Symbol: "x", Name: "Done", NextSymbol: " ", Type: statusTypeDone},
+	{Symbol: "/", Name: "In Progress", NextSymbol: "x", Type: statusTypeInProgress},
+	{Symbol: "-", Name: "Cancelled", NextSymbol: " ", Type: statusTypeCancelled},
+}
+
+// newTaskStatuses validates statuses loaded from settings: each needs a
+// single-character symbol, later duplicates are dropped, and unknown types
+// are treated as TODO. It returns nil when no status is usable.
+func newTaskStatuses(statuses []TaskStatus) taskStatuses {
+	var s taskStatuses
+	for _, st := range statuses {
+		if utf8.RuneCountInString(st.Symbol) != 1 {
+			continue
+		}
+		if _, ok := s.find(st.Symbol); ok {
+			continue
+		}
+		st.Type = normalizeStatusType(st.Type)
+		if st.Type == "" {
+			st.Type = statusTypeTodo
+		}
+		if st.NextSymbol == "" {
+			st.NextSymbol = st.Symbol
+		}
+		s = append(s, st)
+	}
+	return s
+}
+
+// normalizeStatusType returns the canonical form of a status type such as
+// "in-progress" or "Done", or "" if it is not one.
+func normalizeStatusType(t string) string {
+	t = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(t), "-", "_"))
+	for _, st := range statusTypes {
+		if st.Type == t {
+			return t
+		}
+	}
+	return ""
+}
+
+func (s taskStatuses) find(symbol string) (TaskStatus, bool) {
+	for _, st := range s {
+		if st.Symbol == symbol {
+			return st, true
+		}
+	}
+	return TaskStatus{}, false
+}
+
+// get returns the status for symbol. An unconfigured X is read as x, and
+// other unknown symbols are open tasks that toggle to done, as in the Tasks
+// plugin.
+func (s taskStatuses) get(symbol string) TaskStatus {
+	if st, ok := s.find(symbol); ok {
+		return st
+	}
+	if symbol == "X" {
+		if st, ok := s.find("x"); ok {
+			return st
+		}
+	}
+	return TaskStatus{Symbol: symbol, Name: "Unknown", NextSymbol: "x", Type: statusTypeTodo}
+}
+
+// done returns the status that completing a task sets.
+func (s taskStatuses) done() TaskStatus {
+	for _, st := range s {
+		if st.Type == statusTypeDone {
+			return st
+		}
+	}
+	return TaskStatus{Symbol: "x", Name: "Done", NextSymbol: " ", Type: statusTypeDone}
+}
+
+// apply sets a task's status name and type from its symbol. Only done
+// statuses count as completed; cancelled tasks keep their own type.
+func (s taskStatuses) apply(task *Task) {
+	st := s.get(task.Status)
+	task.StatusName, task.StatusType = st.Name, st.Type
+	task.Completed = st.Type == statusTypeDone
+}
+
+// isOpen reports whether a task still needs doing: its status is todo or in
+// progress. Everything else matches the Tasks plugin's "done" filter.
+func (t *Task) isOpen() bool {
+	return t.StatusType == statusTypeTodo || t.StatusType == statusTypeInProgress
+}
+
+// taskStatuses returns the vault's checkbox statuses, from the Tasks
+// plugin's settings if it has them.
+func (v *Vault) taskStatuses() taskStatuses {
+	return taskStatuses(v.obsidianSettings().TaskStatuses)
+}
+
+// setTaskStatus replaces the symbol in a task line's checkbox.
+func setTaskStatus(line, symbol string) string {
+	loc := taskRegex.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line
+	}
+	return line[:loc[4]] + symbol + line[loc[5]:]
+}
@@ -0,0 +1,184 @@
+package vault
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const testTasksPluginData = `{
+  "statusSettings": {
+    "coreStatuses": [
+      {"symbol": " ", "name": "Todo", "nextStatusSymbol": "/", "availableAsCommand": true, "type": "TODO"},
+      {"symbol": "x", "name": "Done", "nextStatusSymbol": " ", "availableAsCommand": true, "type": "DONE"}
+    ],
+    "customStatuses": [
+      {"symbol": "/", "name": "In Progress", "nextStatusSymbol": "x", "availableAsCommand": true, "type": "IN_PROGRESS"},
+      {"symbol": ">", "name": "Deferred", "nextStatusSymbol": " ", "availableAsCommand": true, "type": "TODO"},
+      {"symbol": "?", "name": "Question", "nextStatusSymbol": " ", "availableAsCommand": true, "type": "NON_TASK"},
+      {"symbol": "x", "name": "Duplicate", "nextStatusSymbol": " ", "availableAsCommand": true, "type": "TODO"},
+      {"symbol": "", "name": "Empty", "nextStatusSymbol": "", "availableAsCommand": false, "type": "EMPTY"}
+    ]
+  }
+}`
+
+func TestParseTaskStatuses(t *testing.T) {
+	tests := []struct {
+		line       string
+		status     string
+		name       string
+		statusType string
+		completed  bool
+	}{
+		{"- [ ] Open", " ", "Todo", statusTypeTodo, false},
+		{"- [x] Done", "x", "Done", statusTypeDone, true},
+		{"- [X] Done", "X", "Done", statusTypeDone, true},
+		{"- [/] Doing", "/", "In Progress", statusTypeInProgress, false},
+		{"- [-] Dropped", "-", "Cancelled", statusTypeCancelled, false},
+		{"- [>] Later", ">", "Unknown", statusTypeTodo, false},
+		{"- [?] Ask", "?", "Unknown", statusTypeTodo, false},
+	}
+	for _, tt := range tests {
+		task := ParseTask(tt.line, 1)
+		if task == nil {
+			t.Errorf("ParseTask(%q) = nil", tt.line)
+			continue
+		}
+		if task.Status != tt.status || task.StatusName != tt.name || task.StatusType != tt.statusType || task.Completed != tt.completed {
+			t.Errorf("ParseTask(%q) = %q %q %q %v, want %q %q %q %v", tt.line,
+				task.Status, task.StatusName, task.StatusType, task.Completed, tt.status, tt.name, tt.statusType, tt.completed)
+		}
+	}
+}
+
+func TestTaskStatusesFromTasksPlugin(t *testing.T) {
+	v, dir := setupTestVault(t)
+	if got := v.taskStatuses(); len(got) != len(defaultTaskStatuses) {
+		t.Fatalf("expected default statuses without plugin data, got %+v", got)
+	}
+
+	writeTestFile(t, dir, ".obsidian/plugins/obsidian-tasks-plugin/data.json", testTasksPluginData)
+	statuses := v.taskStatuses()
+	var symbols []string
+	for _, st := range statuses {
+		symbols = append(symbols, st.Symbol)
+	}
+	if got := strings.Join(symbols, ""); got != " x/>?" {
+		t.Errorf("symbols = %q, want duplicates and empty symbols dropped", got)
+	}
+	if st := statuses.get("?"); st.Name != "Question" || st.Type != statusTypeNonTask {
+		t.Errorf("get(?) = %+v", st)
+	}
+	if st := statuses.get("-"); st.Name != "Unknown" || st.NextSymbol != "x" {
+		t.Errorf("statuses missing from the plugin data should be unknown, got %+v", st)
+	}
+
+	writeTestFile(t, dir, ".obsidian/plugins/obsidian-tasks-plugin/data.json", `{"statusSettings": {}}`)
+	if got := v.taskStatuses(); len(got) != len(defaultTaskStatuses) {
+		t.Errorf("expected default statuses for empty plugin data, got %+v", got)
+	}
+}
+
+func TestToggleCyclesStatuses(t *testing.T) {
+	v, dir := setupTestVault(t)
+	today := time.Now().Format("2006-01-02")
+	writeTestFile(t, dir, "todo.md", "- [/] Doing\n- [-] Dropped ❌ 2024-01-01\n- [>] Later")
+
+	for line := 1; line <= 3; line++ {
+		if _, _, err := v.ToggleTaskHandler(context.Background(), nil, ToggleTaskArgs{Path: "todo.md", Line: line}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := "- [x] Doing ✅ " + today + "\n- [ ] Dropped\n- [x] Later ✅ " + today
+	if got := readTestFile(t, dir, "todo.md"); got != want {
+		t.Errorf("default statuses:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	writeTestFile(t, dir, ".obsidian/plugins/obsidian-tasks-plugin/data.json", testTasksPluginData)
+	writeTestFile(t, dir, "cycle.md", "- [ ] Write 📅 2024-01-01")
+	var results []string
+	for range 3 {
+		result, _, err := v.ToggleTaskHandler(context.Background(), nil, ToggleTaskArgs{Path: "cycle.md", Text: "Write"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result.Content[0].(*mcp.TextContent).Text)
+		results = append(results, readTestFile(t, dir, "cycle.md"))
+	}
+	want = strings.Join([]string{
+		"Toggled task on L1 from Todo [ ] to In Progress [/]: Write 📅 2024-01-01",
+		"- [/] Write 📅 2024-01-01",
+		"Toggled task on L1 from In Progress [/] to Done [x]: Write 📅 2024-01-01",
+		"- [x] Write 📅 2024-01-01 ✅ " + today,
+		"Toggled task on L1 from Done [x] to Todo [ ]: Write 📅 2024-01-01 ✅ " + today,
+		"- [ ] Write 📅 2024-01-01",
+	}, "\n")
+	if got := strings.Join(results, "\n"); got != want {
+		t.Errorf("custom cycle:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	writeTestFile(t, dir, "complete.md", "- [/] Ship\n- [?] Ask")
+	result, _, err := v.CompleteTasksHandler(context.Background(), nil, CompleteTasksArgs{Path: "complete.md", Texts: "Ship,Ask"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "Ask (already question)") {
+		t.Errorf("non-task statuses should not be completed again, got %q", text)
+	}
+	if got := readTestFile(t, dir, "complete.md"); got != "- [x] Ship ✅ "+today+"\n- [?] Ask" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestListAndCountTasksByStatusType(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "todo.md", "- [ ] Open\n- [/] Doing\n- [/] Also doing\n- [x] Done\n- [-] Dropped\n- [>] Later")
+	ctx := context.Background()
+
+	count := func(status string) int {
+		t.Helper()
+		result, _, err := v.ListTasksHandler(ctx, nil, ListTasksArgs{Status: status})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var data struct {
+			Total int `json:"total_tasks"`
+		}
+		compactData(t, result, &data)
+		return data.Total
+	}
+	for status, want := range map[string]int{"in_progress": 2, "In-Progress": 2, "todo": 2, "cancelled": 1, "done": 1, "open": 4, "completed": 1, "all": 6} {
+		if got := count(status); got != want {
+			t.Errorf("status %q: %d tasks, want %d", status, got, want)
+		}
+	}
+	if _, _, err := v.ListTasksHandler(ctx, nil, ListTasksArgs{Status: "someday"}); err == nil {
+		t.Error("expected error for unknown status")
+	}
+
+	result, _, err := v.VaultStatsHandler(ctx, nil, VaultStatsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"- **Completed:** 1\n- **Open:** 4\n", "### By Status\n- **In Progress:** 2\n- **Todo:** 2\n- **Done:** 1\n- **Cancelled:** 1\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("stats missing %q:\n%s", want, text)
+		}
+	}
+
+	q, err := parseTasksQuery("status.type is in_progress\ngroup by status.name", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := v.queryTasks(v.GetPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total, groups := q.execute(tasks); total != 2 || groups[0].Group[0] != "In Progress" {
+		t.Errorf("status.type query: %d tasks in %+v", total, groups)
+	}
+}
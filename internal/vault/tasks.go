@@ -6,68 +6,146 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// Task represents a parsed task from markdown
+// Task represents a parsed task from markdown, including the metadata of
+// the Obsidian Tasks plugin's emoji format
 type Task struct {
-	File      string   `json:"file"`
-	Line      int      `json:"line"`
-	Completed bool     `json:"completed"`
-	Text      string   `json:"text"`
-	DueDate   *string  `json:"dueDate,omitempty"`
-	Priority  *string  `json:"priority,omitempty"`
-	Tags      []string `json:"tags,omitempty"`
+	File          string   `json:"file"`
+	Line          int      `json:"line"`
+	Completed     bool     `json:"completed"`
+	Status        string   `json:"status"` // the checkbox symbol
+	StatusName    string   `json:"statusName,omitempty"`
+	StatusType    string   `json:"statusType,omitempty"`
+	Text          string   `json:"text"`
+	Description   string   `json:"description,omitempty"`
+	DueDate       *string  `json:"dueDate,omitempty"`
+	ScheduledDate *string  `json:"scheduledDate,omitempty"`
+	StartDate     *string  `json:"startDate,omitempty"`
+	CreatedDate   *string  `json:"createdDate,omitempty"`
+	DoneDate      *string  `json:"doneDate,omitempty"`
+	CancelledDate *string  `json:"cancelledDate,omitempty"`
+	Recurrence    *string  `json:"recurrence,omitempty"`
+	Priority      *string  `json:"priority,omitempty"`
+	ID            *string  `json:"id,omitempty"`
+	DependsOn     []string `json:"dependsOn,omitempty"`
+	OnCompletion  *string  `json:"onCompletion,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// taskSignifiers are the emoji that start a Tasks plugin field.
+const taskSignifiers = "📅📆🗓⏳⌛🛫➕✅❌🔺⏫🔼🔽⏬🔁🆔⛔🏁"
+
+// taskDateRegex matches a date field introduced by any of the given emoji,
+// optionally followed by a variation selector.
+func taskDateRegex(emoji string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:` + emoji + `)\x{FE0F}?\s*(\d{4}-\d{2}-\d{2})`)
 }
 
 var (
-	// Matches: - [ ] or - [x] or - [X]
-	taskRegex = regexp.MustCompile(`^(\s*)-\s*\[([ xX])\]\s*(.+)$`)
-	// Matches: 📅 2024-01-15
-	dueDateRegex = regexp.MustCompile(`📅\s*(\d{4}-\d{2}-\d{2})`)
-	// Matches: ⏫ (high), 🔼 (medium), 🔽 (low)
-	priorityRegex = regexp.MustCompile(`([⏫🔼🔽])`)
+	// Matches: - [ ], - [x], - [/] or any other single-character status
+	taskRegex = regexp.MustCompile(`^(\s*)-\s*\[(.)\]\s*(.+)$`)
+	// Matches: 📅 2024-01-15 (📆 and 🗓 are accepted too)
+	dueDateRegex       = taskDateRegex("📅|📆|🗓")
+	scheduledDateRegex = taskDateRegex("⏳|⌛")
+	startDateRegex     = taskDateRegex("🛫")
+	createdDateRegex   = taskDateRegex("➕")
+	doneDateRegex      = taskDateRegex("✅")
+	cancelledDateRegex = taskDateRegex("❌")
+	// Matches a done date with the spaces before it, for removal
+	doneStampRegex = regexp.MustCompile(`[ \t]*✅\x{FE0F}?\s*\d{4}-\d{2}-\d{2}`)
+	// Matches: 🔺 (highest), ⏫ (high), 🔼 (medium), 🔽 (low), ⏬ (lowest)
+	priorityRegex = regexp.MustCompile(`([🔺⏫🔼🔽⏬])\x{FE0F}?`)
+	// Matches: 🔁 every week on Monday, up to the next field or tag
+	recurrenceRegex = regexp.MustCompile(`🔁\x{FE0F}?\s*([^` + taskSignifiers + `#^]*)`)
+	// Matches: 🆔 abc123
+	taskIDRegex = regexp.MustCompile(`🆔\x{FE0F}?\s*([a-zA-Z0-9_-]+)`)
+	// Matches: ⛔ abc123,def456
+	dependsOnRegex = regexp.MustCompile(`⛔\x{FE0F}?\s*([a-zA-Z0-9_-]+(?:\s*,\s*[a-zA-Z0-9_-]+)*)`)
+	// Matches: 🏁 delete or 🏁 keep
+	onCompletionRegex = regexp.MustCompile(`(?i)🏁\x{FE0F}?\s*(delete|keep)`)
 	// Matches: #tag
 	tagRegex = regexp.MustCompile(`#([a-zA-Z0-9_\-]+)`)
 )
 
-// ParseTask parses a single line into a Task if it matches
+// taskFieldRegexes are the fields stripped from a task to get its description.
+var taskFieldRegexes = []*regexp.Regexp{
+	dueDateRegex, scheduledDateRegex, startDateRegex, createdDateRegex, doneDateRegex,
+	cancelledDateRegex, priorityRegex, recurrenceRegex, taskIDRegex, dependsOnRegex, onCompletionRegex,
+}
+
+// taskPriorities maps priority emoji to names.
+var taskPriorities = map[string]string{
+	"🔺": "highest",
+	"⏫": "high",
+	"🔼": "medium",
+	"🔽": "low",
+	"⏬": "lowest",
+}
+
+// ParseTask parses a single line into a Task if it matches. Its status is
+// resolved against the default statuses; handlers re-apply the vault's own.
 func ParseTask(line string, lineNum int) *Task {
 	match := taskRegex.FindStringSubmatch(line)
 	if match == nil {
 		return nil
 	}
 
-	status := match[2]
 	text := strings.TrimSpace(match[3])
 
 	task := &Task{
-		Line:      lineNum,
-		Completed: strings.EqualFold(status, "x"),
-		Text:      text,
-	}
-
-	// Extract due date
-	if dateMatch := dueDateRegex.FindStringSubmatch(text); dateMatch != nil {
-		task.DueDate = &dateMatch[1]
+		Line:   lineNum,
+		Status: match[2],
+		Text:   text,
+	}
+	defaultTaskStatuses.apply(task)
+
+	// Extract dates
+	for re, field := range map[*regexp.Regexp]**string{
+		dueDateRegex:       &task.DueDate,
+		scheduledDateRegex: &task.ScheduledDate,
+		startDateRegex:     &task.StartDate,
+		createdDateRegex:   &task.CreatedDate,
+		doneDateRegex:      &task.DoneDate,
+		cancelledDateRegex: &task.CancelledDate,
+	} {
+		if m := re.FindStringSubmatch(text); m != nil {
+			*field = &m[1]
+		}
 	}
 
 	// Extract priority
 	if prioMatch := priorityRegex.FindStringSubmatch(text); prioMatch != nil {
-		var prio string
-		switch prioMatch[1] {
-		case "⏫":
-			prio = "high"
-		case "🔼":
-			prio = "medium"
-		case "🔽":
-			prio = "low"
-		}
+		prio := taskPriorities[prioMatch[1]]
 		task.Priority = &prio
 	}
 
+	// Extract recurrence, dependencies and completion behaviour
+	if m := recurrenceRegex.FindStringSubmatch(text); m != nil {
+		if rule := strings.TrimSpace(m[1]); rule != "" {
+			task.Recurrence = &rule
+		}
+	}
+	if m := taskIDRegex.FindStringSubmatch(text); m != nil {
+		task.ID = &m[1]
+	}
+	if m := dependsOnRegex.FindStringSubmatch(text); m != nil {
+		for _, id := range strings.Split(m[1], ",") {
+			task.DependsOn = append(task.DependsOn, strings.TrimSpace(id))
+		}
+	}
+	if m := onCompletionRegex.FindStringSubmatch(text); m != nil {
+		action := strings.ToLower(m[1])
+		task.OnCompletion = &action
+	}
+
+	task.Description = taskDescription(text)
+
 	// Extract tags
 	tagMatches := tagRegex.FindAllStringSubmatch(text, -1)
 	for _, tm := range tagMatches {
@@ -77,40 +155,75 @@ func ParseTask(line string, lineNum int) *Task {
 	return task
 }
 
-// taskMatchesStatus returns whether a task should be included given the status filter.
+// taskDescription returns a task's text without its Tasks plugin fields or
+// trailing block ID.
+func taskDescription(text string) string {
+	text = blockIDRegex.ReplaceAllString(text, "")
+	for _, re := range taskFieldRegexes {
+		text = re.ReplaceAllString(text, " ")
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// setTaskDoneDate replaces a task line's ✅ done date with date, or removes
+// it when date is empty. A new date goes at the end of the line, before any
+// block ID, as the Tasks plugin writes it.
+func setTaskDoneDate(line, date string) string {
+	line = strings.TrimRight(doneStampRegex.ReplaceAllString(line, ""), " \t")
+	if date == "" {
+		return line
+	}
+	stamp := " ✅ " + date
+	if loc := blockIDRegex.FindStringIndex(line); loc != nil {
+		return line[:loc[0]] + stamp + line[loc[0]:]
+	}
+	return line + stamp
+}
+
+// taskMatchesStatus returns whether a task should be included given the
+// status filter: all, open, completed, or a status type such as in_progress.
 func taskMatchesStatus(task *Task, status string) bool {
 	switch status {
 	case "open":
-		return !task.Completed
+		return task.isOpen()
 	case "completed":
 		return task.Completed
-	default: // "all"
+	case "all":
+		return true
+	default:
+		return task.StatusType == normalizeStatusType(status)
+	}
+}
+
+// validTaskStatusFilter reports whether status is a filter taskMatchesStatus
+// understands.
+func validTaskStatusFilter(status string) bool {
+	switch status {
+	case "all", "open", "completed":
 		return true
 	}
+	return normalizeStatusType(status) != ""
 }
 
-// collectTasks walks a directory and collects tasks matching the given status filter.
+// collectTasks collects tasks matching the given status filter, with the
+// vault's statuses applied.
 func (v *Vault) collectTasks(searchPath, status string) ([]Task, error) {
-	var tasks []Task
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, err
+	}
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-		relPath, _ := filepath.Rel(v.GetPath(), path)
-		for i, line := range strings.Split(string(content), "\n") {
-			if task := ParseTask(line, i+1); task != nil && taskMatchesStatus(task, status) {
-				task.File = relPath
-				tasks = append(tasks, *task)
+	statuses := v.taskStatuses()
+	var tasks []Task
+	for _, note := range notes {
+		for _, task := range note.Tasks {
+			statuses.apply(&task)
+			if taskMatchesStatus(&task, status) {
+				tasks = append(tasks, task)
 			}
 		}
-		return nil
-	})
-	return tasks, err
+	}
+	return tasks, nil
 }
 
 // formatTasks formats a slice of tasks grouped by file.
@@ -128,18 +241,25 @@ func formatTasks(tasks []Task) string {
 			currentFile = t.File
 		}
 
-		checkbox := "[ ]"
-		if t.Completed {
-			checkbox = "[x]"
-		}
-
-		sb.WriteString(fmt.Sprintf("  L%d: - %s %s", t.Line, checkbox, t.Text))
+		sb.WriteString(fmt.Sprintf("  L%d: - [%s] %s", t.Line, t.Status, t.Text))
 		if t.Priority != nil {
 			sb.WriteString(fmt.Sprintf(" [%s]", *t.Priority))
 		}
 		if t.DueDate != nil {
 			sb.WriteString(fmt.Sprintf(" (due: %s)", *t.DueDate))
 		}
+		if t.ScheduledDate != nil {
+			sb.WriteString(fmt.Sprintf(" (scheduled: %s)", *t.ScheduledDate))
+		}
+		if t.StartDate != nil {
+			sb.WriteString(fmt.Sprintf(" (starts: %s)", *t.StartDate))
+		}
+		if t.DoneDate != nil {
+			sb.WriteString(fmt.Sprintf(" (done: %s)", *t.DoneDate))
+		}
+		if t.Recurrence != nil {
+			sb.WriteString(fmt.Sprintf(" (repeats %s)", *t.Recurrence))
+		}
 		sb.WriteString("\n")
 	}
 	return sb.String()
@@ -153,6 +273,9 @@ func (v *Vault) ListTasksHandler(ctx context.Context, req *mcp.CallToolRequest,
 	if status == "" {
 		status = "all"
 	}
+	if !validTaskStatusFilter(status) {
+		return nil, nil, fmt.Errorf("invalid status %q: use all, open, completed, or a status type (todo, in_progress, done, cancelled, non_task)", status)
+	}
 
 	searchPath := v.GetPath()
 	if args.Directory != "" {
@@ -257,16 +380,47 @@ func findTaskByText(lines []string, text string) (int, *Task, error) {
 	}
 }
 
-// toggleLine toggles a task checkbox on a single line and returns the new line.
-func toggleLine(line string, task *Task) string {
-	if task.Completed {
-		newLine := strings.Replace(line, "[x]", "[ ]", 1)
-		return strings.Replace(newLine, "[X]", "[ ]", 1)
+// reopenLine sets a done task's status to symbol and removes its done date.
+func reopenLine(line, symbol string) string {
+	return setTaskDoneDate(setTaskStatus(line, symbol), "")
+}
+
+// completeLine sets an open task's status to the done symbol and stamps
+// today's done date.
+func completeLine(line, symbol string) string {
+	return setTaskDoneDate(setTaskStatus(line, symbol), time.Now().Format("2006-01-02"))
+}
+
+// completeTask marks the open task on lineNum complete with the done symbol,
+// as the Tasks plugin does: a recurring task gets its next occurrence
+// inserted above it, and a task marked 🏁 delete is removed instead of
+// checked. It returns the new lines and a note on the next occurrence, if any.
+func completeTask(lines []string, lineNum int, task *Task, done string) ([]string, string) {
+	line := lines[lineNum-1]
+	next, err := nextOccurrence(line, task, time.Now())
+	if err != nil {
+		lines[lineNum-1] = completeLine(line, done)
+		return lines, fmt.Sprintf("no next occurrence: %v", err)
+	}
+
+	var replacement []string
+	if next != "" {
+		replacement = append(replacement, next)
+	}
+	if task.OnCompletion == nil || *task.OnCompletion != "delete" {
+		replacement = append(replacement, completeLine(line, done))
+	}
+	lines = slices.Replace(lines, lineNum-1, lineNum, replacement...)
+
+	if next == "" {
+		return lines, ""
 	}
-	return strings.Replace(line, "[ ]", "[x]", 1)
+	return lines, fmt.Sprintf("next occurrence on L%d: %s", lineNum, ParseTask(next, lineNum).Text)
 }
 
-// ToggleTaskHandler toggles a task's completion status by line number or text match.
+// ToggleTaskHandler moves a task, found by line number or text match, to its
+// status's next status: with the default statuses, todo and in progress go
+// to done and done goes back to todo.
 func (v *Vault) ToggleTaskHandler(ctx context.Context, req *mcp.CallToolRequest, args ToggleTaskArgs) (*mcp.CallToolResult, any, error) {
 	path := args.Path
 	if !strings.HasSuffix(path, ".md") {
@@ -314,20 +468,37 @@ func (v *Vault) ToggleTaskHandler(ctx context.Context, req *mcp.CallToolRequest,
 		return nil, nil, fmt.Errorf("either 'line' or 'text' must be provided")
 	}
 
-	lines[lineNum-1] = toggleLine(lines[lineNum-1], task)
+	statuses := v.taskStatuses()
+	current := statuses.get(task.Status)
+	next := statuses.get(current.NextSymbol)
+
+	// Leaving the cancelled status drops its ❌ date
+	if current.Type == statusTypeCancelled && next.Type != statusTypeCancelled {
+		lines[lineNum-1] = strings.TrimRight(cancelledStampRegex.ReplaceAllString(lines[lineNum-1], ""), " \t")
+	}
+
+	var note string
+	switch {
+	case next.Type == statusTypeDone && current.Type != statusTypeDone:
+		lines, note = completeTask(lines, lineNum, task, next.Symbol)
+	case current.Type == statusTypeDone:
+		lines[lineNum-1] = reopenLine(lines[lineNum-1], next.Symbol)
+	default:
+		lines[lineNum-1] = setTaskStatus(lines[lineNum-1], next.Symbol)
+	}
 
 	if err := os.WriteFile(fullPath, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
 		return nil, nil, fmt.Errorf("failed to write note: %v", err)
 	}
 
-	newStatus := "completed"
-	if task.Completed {
-		newStatus = "open"
+	text := fmt.Sprintf("Toggled task on L%d from %s [%s] to %s [%s]: %s", lineNum, current.Name, current.Symbol, next.Name, next.Symbol, task.Text)
+	if note != "" {
+		text += fmt.Sprintf(" (%s)", note)
 	}
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: fmt.Sprintf("Toggled task on L%d to %s: %s", lineNum, newStatus, task.Text)},
+			&mcp.TextContent{Text: text},
 		},
 	}, nil, nil
 }
@@ -361,6 +532,8 @@ func (v *Vault) CompleteTasksHandler(ctx context.Context, req *mcp.CallToolReque
 	}
 
 	lines := strings.Split(string(content), "\n")
+	statuses := v.taskStatuses()
+	done := statuses.done()
 
 	var completed []string
 	var errors []string
@@ -371,11 +544,17 @@ func (v *Vault) CompleteTasksHandler(ctx context.Context, req *mcp.CallToolReque
 			errors = append(errors, fmt.Sprintf("%q: %v", text, err))
 			continue
 		}
-		if task.Completed {
-			completed = append(completed, fmt.Sprintf("L%d: %s (already complete)", lineNum, task.Text))
+		statuses.apply(task)
+		if !task.isOpen() {
+			completed = append(completed, fmt.Sprintf("L%d: %s (already %s)", lineNum, task.Text, strings.ToLower(task.StatusName)))
+			continue
+		}
+		var note string
+		lines, note = completeTask(lines, lineNum, task, done.Symbol)
+		if note != "" {
+			completed = append(completed, fmt.Sprintf("L%d: %s (%s)", lineNum, task.Text, note))
 			continue
 		}
-		lines[lineNum-1] = strings.Replace(lines[lineNum-1], "[ ]", "[x]", 1)
 		completed = append(completed, fmt.Sprintf("L%d: %s", lineNum, task.Text))
 	}
 
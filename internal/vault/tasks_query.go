@@ -0,0 +1,865 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// queryTask is a task with the note context the Tasks query language can
+// filter, sort and group on.
+type queryTask struct {
+	*Task
+	heading string
+	blocked bool
+}
+
+type (
+	taskFilter  func(*queryTask) bool
+	taskCompare func(a, b *queryTask) int
+	// taskGrouper returns the groups a task belongs to, each with a sort key
+	// and display name.
+	taskGrouper func(*queryTask) []taskGroupKey
+)
+
+type taskGroupKey struct {
+	sort string
+	name string
+}
+
+// noGroupKey sorts tasks missing a grouped or sorted property last.
+const noGroupKey = "\uffff"
+
+// tasksQuery is a parsed Obsidian Tasks query block: filters that must all
+// match, then sorting, grouping and limits.
+type tasksQuery struct {
+	filters    []taskFilter
+	sorts      []taskCompare
+	groups     []taskGrouper
+	limit      int
+	groupLimit int
+}
+
+// TaskGroup is a group of query results. Group holds one heading per
+// "group by" instruction and is empty when the query is not grouped.
+type TaskGroup struct {
+	Group []string `json:"group,omitempty"`
+	Tasks []Task   `json:"tasks"`
+}
+
+// taskDateFields maps the date names used in queries to task fields.
+var taskDateFields = map[string]func(*Task) *string{
+	"due":       func(t *Task) *string { return t.DueDate },
+	"scheduled": func(t *Task) *string { return t.ScheduledDate },
+	"start":     func(t *Task) *string { return t.StartDate },
+	"created":   func(t *Task) *string { return t.CreatedDate },
+	"done":      func(t *Task) *string { return t.DoneDate },
+	"cancelled": func(t *Task) *string { return t.CancelledDate },
+}
+
+// taskPriorityRanks orders priorities from most to least urgent; tasks
+// without one rank as "none", between medium and low.
+var taskPriorityRanks = map[string]int{
+	"highest": 0, "high": 1, "medium": 2, "none": 3, "normal": 3, "low": 4, "lowest": 5,
+}
+
+var taskPriorityNames = []string{"Highest", "High", "Medium", "Normal", "Low", "Lowest"}
+
+func taskPriorityRank(t *Task) int {
+	if t.Priority == nil {
+		return 3
+	}
+	return taskPriorityRanks[*t.Priority]
+}
+
+var (
+	tasksDateFilterRegex  = regexp.MustCompile(`^(due|scheduled|start|created|done|cancelled|happens)\s+(?:(on or before|on or after|before|after|on|in)\s+)?(.+)$`)
+	tasksHasDateRegex     = regexp.MustCompile(`^(has|no)\s+(due|scheduled|start|created|done|cancelled|happens)\s+dates?$`)
+	tasksPriorityRegex    = regexp.MustCompile(`^priority\s+is\s+(?:(above|below|not)\s+)?(\w+)$`)
+	tasksTextFilterRegex  = regexp.MustCompile(`^(path|description|heading|filename|folder)\s+(includes|does not include)\s+(.+)$`)
+	tasksStatusRegex      = regexp.MustCompile(`^status\.(type|name)\s+(is not|is|includes|does not include)\s+(.+)$`)
+	tasksTagFilterRegex   = regexp.MustCompile(`^tags?\s+(includes?|do not include|does not include)\s+(.+)$`)
+	tasksLimitRegex       = regexp.MustCompile(`^limit\s+(?:to\s+)?(groups\s+)?(?:to\s+)?(\d+)(?:\s+tasks?)?$`)
+	tasksRelativeDayRegex = regexp.MustCompile(`^(?:in\s+(\d+)\s+(day|week|month|year)s?|(\d+)\s+(day|week|month|year)s?\s+ago)$`)
+	tasksDateRangeRegex   = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\s+(\d{4}-\d{2}-\d{2})$`)
+)
+
+// parseTasksQuery parses a Tasks query block, resolving relative dates
+// against now. Layout instructions such as "hide" and "short mode" only
+// affect Obsidian's rendering and are ignored.
+func parseTasksQuery(query string, now time.Time) (*tasksQuery, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	q := &tasksQuery{}
+	for _, raw := range strings.Split(query, "\n") {
+		line := strings.TrimSpace(raw)
+		lower := strings.ToLower(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(lower, "hide ") || strings.HasPrefix(lower, "show ") ||
+			lower == "short mode" || lower == "full mode" || lower == "explain" || lower == "ignore global query":
+		case strings.HasPrefix(lower, "sort by "):
+			cmp, err := parseTaskSort(strings.TrimSpace(lower[len("sort by "):]))
+			if err != nil {
+				return nil, err
+			}
+			q.sorts = append(q.sorts, cmp)
+		case strings.HasPrefix(lower, "group by "):
+			grouper, err := parseTaskGroup(strings.TrimSpace(lower[len("group by "):]))
+			if err != nil {
+				return nil, err
+			}
+			q.groups = append(q.groups, grouper)
+		case strings.HasPrefix(lower, "limit"):
+			m := tasksLimitRegex.FindStringSubmatch(lower)
+			if m == nil {
+				return nil, fmt.Errorf("invalid limit: %s", line)
+			}
+			n, _ := strconv.Atoi(m[2])
+			if m[1] != "" {
+				q.groupLimit = n
+			} else {
+				q.limit = n
+			}
+		default:
+			filter, err := parseTaskFilter(line, today)
+			if err != nil {
+				return nil, err
+			}
+			q.filters = append(q.filters, filter)
+		}
+	}
+	return q, nil
+}
+
+// parseTaskFilter parses a filter line, which may combine parenthesized
+// filters with the upper-case operators AND, OR and NOT.
+func parseTaskFilter(line string, today time.Time) (taskFilter, error) {
+	p := &taskBoolParser{s: line, today: today}
+	if !strings.HasPrefix(line, "(") && !strings.HasPrefix(line, "NOT (") {
+		return parseSimpleTaskFilter(line, today)
+	}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.s) {
+		return nil, fmt.Errorf("unexpected text in tasks filter: %s", p.s[p.pos:])
+	}
+	return f, nil
+}
+
+// taskBoolParser parses boolean combinations such as
+// (due today) OR (NOT (done) AND (priority is high)).
+type taskBoolParser struct {
+	s     string
+	pos   int
+	today time.Time
+}
+
+func (p *taskBoolParser) skipSpace() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *taskBoolParser) keyword(word string) bool {
+	p.skipSpace()
+	if len(p.s)-p.pos > len(word) && p.s[p.pos:p.pos+len(word)] == word && p.s[p.pos+len(word)] == ' ' {
+		p.pos += len(word)
+		return true
+	}
+	return false
+}
+
+func (p *taskBoolParser) parseOr() (taskFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(t *queryTask) bool { return l(t) || right(t) }
+	}
+	return left, nil
+}
+
+func (p *taskBoolParser) parseAnd() (taskFilter, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(t *queryTask) bool { return l(t) && right(t) }
+	}
+	return left, nil
+}
+
+func (p *taskBoolParser) parseNot() (taskFilter, error) {
+	if p.keyword("NOT") {
+		f, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(t *queryTask) bool { return !f(t) }, nil
+	}
+	p.skipSpace()
+	if p.pos >= len(p.s) || p.s[p.pos] != '(' {
+		return nil, fmt.Errorf("expected '(' in tasks filter: %s", p.s)
+	}
+	depth, start := 0, p.pos+1
+	for i := p.pos; i < len(p.s); i++ {
+		switch p.s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 {
+			inner := strings.TrimSpace(p.s[start:i])
+			p.pos = i + 1
+			return parseTaskFilter(inner, p.today)
+		}
+	}
+	return nil, fmt.Errorf("unbalanced parentheses in tasks filter: %s", p.s)
+}
+
+// parseSimpleTaskFilter parses a single filter instruction.
+func parseSimpleTaskFilter(line string, today time.Time) (taskFilter, error) {
+	lower := strings.ToLower(line)
+	switch lower {
+	case "done":
+		return func(t *queryTask) bool { return !t.isOpen() }, nil
+	case "not done":
+		return func(t *queryTask) bool { return t.isOpen() }, nil
+	case "is recurring":
+		return func(t *queryTask) bool { return t.Recurrence != nil }, nil
+	case "is not recurring":
+		return func(t *queryTask) bool { return t.Recurrence == nil }, nil
+	case "is blocked":
+		return func(t *queryTask) bool { return t.blocked }, nil
+	case "is not blocked":
+		return func(t *queryTask) bool { return !t.blocked }, nil
+	case "has tags":
+		return func(t *queryTask) bool { return len(t.Tags) > 0 }, nil
+	case "no tags":
+		return func(t *queryTask) bool { return len(t.Tags) == 0 }, nil
+	}
+
+	if m := tasksHasDateRegex.FindStringSubmatch(lower); m != nil {
+		want := m[1] == "has"
+		dates := taskDatesFor(m[2])
+		return func(t *queryTask) bool {
+			for _, date := range dates {
+				if date(t.Task) != nil {
+					return want
+				}
+			}
+			return !want
+		}, nil
+	}
+	if m := tasksDateFilterRegex.FindStringSubmatch(lower); m != nil {
+		from, to, err := parseTaskDateRange(m[3], today)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date in %q: %v", line, err)
+		}
+		return taskDateFilter(taskDatesFor(m[1]), m[2], from, to), nil
+	}
+	if m := tasksPriorityRegex.FindStringSubmatch(lower); m != nil {
+		rank, ok := taskPriorityRanks[m[2]]
+		if !ok {
+			return nil, fmt.Errorf("unknown priority: %s", m[2])
+		}
+		switch m[1] {
+		case "above":
+			return func(t *queryTask) bool { return taskPriorityRank(t.Task) < rank }, nil
+		case "below":
+			return func(t *queryTask) bool { return taskPriorityRank(t.Task) > rank }, nil
+		case "not":
+			return func(t *queryTask) bool { return taskPriorityRank(t.Task) != rank }, nil
+		}
+		return func(t *queryTask) bool { return taskPriorityRank(t.Task) == rank }, nil
+	}
+	if m := tasksTextFilterRegex.FindStringSubmatch(lower); m != nil {
+		field, needle := m[1], strings.Trim(strings.TrimSpace(m[3]), `"`)
+		want := m[2] == "includes"
+		return func(t *queryTask) bool {
+			return strings.Contains(strings.ToLower(taskTextField(t, field)), needle) == want
+		}, nil
+	}
+	if m := tasksStatusRegex.FindStringSubmatch(lower); m != nil {
+		return parseTaskStatusFilter(m[1], m[2], strings.TrimSpace(m[3]))
+	}
+	if m := tasksTagFilterRegex.FindStringSubmatch(lower); m != nil {
+		needle := strings.TrimPrefix(strings.TrimSpace(m[2]), "#")
+		want := !strings.Contains(m[1], "not")
+		return func(t *queryTask) bool {
+			for _, tag := range t.Tags {
+				if strings.Contains(strings.ToLower(tag), needle) {
+					return want
+				}
+			}
+			return !want
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported tasks instruction: %s", line)
+}
+
+// parseTaskStatusFilter parses "status.type is in_progress" and
+// "status.name includes waiting".
+func parseTaskStatusFilter(field, op, value string) (taskFilter, error) {
+	if field == "type" {
+		statusType := normalizeStatusType(value)
+		if statusType == "" || (op != "is" && op != "is not") {
+			return nil, fmt.Errorf("invalid status.type filter: %s %s", op, value)
+		}
+		want := op == "is"
+		return func(t *queryTask) bool { return (t.StatusType == statusType) == want }, nil
+	}
+	if op != "includes" && op != "does not include" {
+		return nil, fmt.Errorf("invalid status.name filter: %s %s", op, value)
+	}
+	want := op == "includes"
+	return func(t *queryTask) bool {
+		return strings.Contains(strings.ToLower(t.StatusName), value) == want
+	}, nil
+}
+
+// statusTypeRank orders status types as the Tasks plugin sorts them.
+func statusTypeRank(statusType string) int {
+	for i, st := range statusTypes {
+		if st.Type == statusType {
+			return i
+		}
+	}
+	return len(statusTypes)
+}
+
+// taskDatesFor returns the date fields a query name refers to; "happens"
+// means any of due, scheduled and start.
+func taskDatesFor(name string) []func(*Task) *string {
+	if name == "happens" {
+		return []func(*Task) *string{taskDateFields["due"], taskDateFields["scheduled"], taskDateFields["start"]}
+	}
+	return []func(*Task) *string{taskDateFields[name]}
+}
+
+// taskDateFilter matches tasks with any of dates falling relative to the
+// inclusive range from-to. Tasks without the date never match.
+func taskDateFilter(dates []func(*Task) *string, op string, from, to time.Time) taskFilter {
+	return func(t *queryTask) bool {
+		for _, date := range dates {
+			s := date(t.Task)
+			if s == nil {
+				continue
+			}
+			d, err := time.ParseInLocation("2006-01-02", *s, time.Local)
+			if err != nil {
+				continue
+			}
+			var ok bool
+			switch op {
+			case "before":
+				ok = d.Before(from)
+			case "after":
+				ok = d.After(to)
+			case "on or before":
+				ok = !d.After(to)
+			case "on or after":
+				ok = !d.Before(from)
+			default: // on, in
+				ok = !d.Before(from) && !d.After(to)
+			}
+			if ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// parseTaskDateRange resolves a date expression to an inclusive range of
+// days: a date, two dates, today, tomorrow, yesterday, "in 3 days",
+// "2 weeks ago", or this, next or last week, month or year.
+func parseTaskDateRange(expr string, today time.Time) (from, to time.Time, err error) {
+	expr = strings.TrimSpace(expr)
+	day := func(t time.Time) (time.Time, time.Time, error) { return t, t, nil }
+	switch expr {
+	case "today":
+		return day(today)
+	case "tomorrow":
+		return day(today.AddDate(0, 0, 1))
+	case "yesterday":
+		return day(today.AddDate(0, 0, -1))
+	}
+	if t, err := time.ParseInLocation("2006-01-02", expr, time.Local); err == nil {
+		return day(t)
+	}
+	if m := tasksDateRangeRegex.FindStringSubmatch(expr); m != nil {
+		from, err1 := time.ParseInLocation("2006-01-02", m[1], time.Local)
+		to, err2 := time.ParseInLocation("2006-01-02", m[2], time.Local)
+		if err1 != nil || err2 != nil {
+			return from, to, fmt.Errorf("invalid date range: %s", expr)
+		}
+		return from, to, nil
+	}
+	if m := tasksRelativeDayRegex.FindStringSubmatch(expr); m != nil {
+		n, unit := m[1], m[2]
+		sign := 1
+		if n == "" {
+			n, unit, sign = m[3], m[4], -1
+		}
+		count, _ := strconv.Atoi(n)
+		return day(addDateUnit(today, unit, sign*count))
+	}
+
+	rel, unit, ok := strings.Cut(expr, " ")
+	offset := map[string]int{"this": 0, "next": 1, "last": -1}
+	if n, known := offset[rel]; ok && known {
+		switch unit {
+		case "week":
+			start := today.AddDate(0, 0, -((int(today.Weekday())+6)%7)+7*n)
+			return start, start.AddDate(0, 0, 6), nil
+		case "month":
+			start := time.Date(today.Year(), today.Month()+time.Month(n), 1, 0, 0, 0, 0, time.Local)
+			return start, start.AddDate(0, 1, -1), nil
+		case "year":
+			start := time.Date(today.Year()+n, time.January, 1, 0, 0, 0, 0, time.Local)
+			return start, start.AddDate(1, 0, -1), nil
+		}
+	}
+	return from, to, fmt.Errorf("unrecognized date: %s", expr)
+}
+
+func addDateUnit(t time.Time, unit string, n int) time.Time {
+	switch unit {
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "month":
+		return t.AddDate(0, n, 0)
+	case "year":
+		return t.AddDate(n, 0, 0)
+	}
+	return t.AddDate(0, 0, n)
+}
+
+// taskTextField returns a text property of a task for filtering and sorting.
+func taskTextField(t *queryTask, field string) string {
+	path := filepath.ToSlash(t.File)
+	switch field {
+	case "path":
+		return strings.TrimSuffix(path, ".md")
+	case "filename":
+		return strings.TrimSuffix(filepath.Base(path), ".md")
+	case "folder":
+		if dir := filepath.ToSlash(filepath.Dir(path)); dir != "." {
+			return dir + "/"
+		}
+		return "/"
+	case "heading":
+		return t.heading
+	}
+	return t.Description
+}
+
+// parseTaskSort parses the field of a "sort by" instruction.
+func parseTaskSort(spec string) (taskCompare, error) {
+	field, reverse := strings.CutSuffix(spec, " reverse")
+	var cmp taskCompare
+	switch field {
+	case "status":
+		cmp = func(a, b *queryTask) int { return boolOrder(!a.isOpen(), !b.isOpen()) }
+	case "status.type":
+		cmp = func(a, b *queryTask) int { return statusTypeRank(a.StatusType) - statusTypeRank(b.StatusType) }
+	case "status.name":
+		cmp = func(a, b *queryTask) int {
+			return strings.Compare(strings.ToLower(a.StatusName), strings.ToLower(b.StatusName))
+		}
+	case "priority":
+		cmp = func(a, b *queryTask) int { return taskPriorityRank(a.Task) - taskPriorityRank(b.Task) }
+	case "path", "filename", "folder", "heading", "description":
+		cmp = func(a, b *queryTask) int {
+			return strings.Compare(strings.ToLower(taskTextField(a, field)), strings.ToLower(taskTextField(b, field)))
+		}
+	case "recurring":
+		cmp = func(a, b *queryTask) int { return boolOrder(a.Recurrence == nil, b.Recurrence == nil) }
+	case "tag":
+		cmp = func(a, b *queryTask) int { return strings.Compare(firstTag(a.Task), firstTag(b.Task)) }
+	default:
+		date, ok := taskDateFields[field]
+		if !ok {
+			return nil, fmt.Errorf("unsupported sort field: %s", field)
+		}
+		// Tasks without the date sort last.
+		cmp = func(a, b *queryTask) int {
+			da, db := date(a.Task), date(b.Task)
+			switch {
+			case da == nil && db == nil:
+				return 0
+			case da == nil:
+				return 1
+			case db == nil:
+				return -1
+			}
+			return strings.Compare(*da, *db)
+		}
+	}
+	if reverse {
+		return func(a, b *queryTask) int { return cmp(b, a) }, nil
+	}
+	return cmp, nil
+}
+
+func boolOrder(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+func firstTag(t *Task) string {
+	if len(t.Tags) == 0 {
+		return noGroupKey
+	}
+	return strings.ToLower(t.Tags[0])
+}
+
+// parseTaskGroup parses the field of a "group by" instruction.
+func parseTaskGroup(spec string) (taskGrouper, error) {
+	field, reverse := strings.CutSuffix(spec, " reverse")
+	var grouper taskGrouper
+	switch field {
+	case "status":
+		grouper = func(t *queryTask) []taskGroupKey {
+			if !t.isOpen() {
+				return []taskGroupKey{{"2", "Done"}}
+			}
+			return []taskGroupKey{{"1", "Todo"}}
+		}
+	case "status.type":
+		grouper = func(t *queryTask) []taskGroupKey {
+			return []taskGroupKey{{strconv.Itoa(statusTypeRank(t.StatusType)), t.StatusType}}
+		}
+	case "status.name":
+		grouper = func(t *queryTask) []taskGroupKey {
+			return []taskGroupKey{{strings.ToLower(t.StatusName), t.StatusName}}
+		}
+	case "priority":
+		grouper = func(t *queryTask) []taskGroupKey {
+			rank := taskPriorityRank(t.Task)
+			return []taskGroupKey{{strconv.Itoa(rank), taskPriorityNames[rank] + " priority"}}
+		}
+	case "path", "filename", "folder", "heading":
+		grouper = func(t *queryTask) []taskGroupKey {
+			name := taskTextField(t, field)
+			if name == "" {
+				return []taskGroupKey{{noGroupKey, "(No heading)"}}
+			}
+			return []taskGroupKey{{strings.ToLower(name), name}}
+		}
+	case "recurring", "recurrence":
+		grouper = func(t *queryTask) []taskGroupKey {
+			if t.Recurrence != nil {
+				return []taskGroupKey{{"1", "Recurring"}}
+			}
+			return []taskGroupKey{{"2", "Not Recurring"}}
+		}
+	case "tags", "tag":
+		grouper = func(t *queryTask) []taskGroupKey {
+			if len(t.Tags) == 0 {
+				return []taskGroupKey{{noGroupKey, "(No tags)"}}
+			}
+			keys := make([]taskGroupKey, len(t.Tags))
+			for i, tag := range t.Tags {
+				keys[i] = taskGroupKey{strings.ToLower(tag), "#" + tag}
+			}
+			return keys
+		}
+	default:
+		date, ok := taskDateFields[field]
+		if !ok {
+			return nil, fmt.Errorf("unsupported group field: %s", field)
+		}
+		grouper = func(t *queryTask) []taskGroupKey {
+			s := date(t.Task)
+			if s == nil {
+				return []taskGroupKey{{noGroupKey, "No " + field + " date"}}
+			}
+			name := *s
+			if d, err := time.Parse("2006-01-02", *s); err == nil {
+				name += " " + d.Weekday().String()
+			}
+			return []taskGroupKey{{*s, name}}
+		}
+	}
+	if reverse {
+		return func(t *queryTask) []taskGroupKey {
+			keys := grouper(t)
+			for i := range keys {
+				keys[i].sort = reverseKey(keys[i].sort)
+			}
+			return keys
+		}, nil
+	}
+	return grouper, nil
+}
+
+// reverseKey maps a sort key to one that sorts in the opposite order.
+func reverseKey(key string) string {
+	runes := []rune(key)
+	for i, r := range runes {
+		runes[i] = 0x10FFFF - r
+	}
+	return string(runes)
+}
+
+// execute runs the query over tasks, returning the total number of
+// matching tasks and the groups of tasks to show.
+func (q *tasksQuery) execute(tasks []*queryTask) (int, []TaskGroup) {
+	var matched []*queryTask
+	for _, t := range tasks {
+		ok := true
+		for _, f := range q.filters {
+			if !f(t) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, t)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		for _, cmp := range q.sorts {
+			if c := cmp(matched[i], matched[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	total := len(matched)
+	if q.limit > 0 && len(matched) > q.limit {
+		matched = matched[:q.limit]
+	}
+
+	if len(q.groups) == 0 {
+		group := TaskGroup{Tasks: make([]Task, 0, len(matched))}
+		for _, t := range matched {
+			group.Tasks = append(group.Tasks, *t.Task)
+		}
+		return total, []TaskGroup{group}
+	}
+
+	type keyedGroup struct {
+		sortKey []string
+		group   TaskGroup
+	}
+	byKey := make(map[string]*keyedGroup)
+	var order []*keyedGroup
+	for _, t := range matched {
+		for _, path := range taskGroupPaths(q.groups, t) {
+			var sortKey, names []string
+			for _, k := range path {
+				sortKey = append(sortKey, k.sort)
+				names = append(names, k.name)
+			}
+			id := strings.Join(sortKey, "\x00") + "\x01" + strings.Join(names, "\x00")
+			g, ok := byKey[id]
+			if !ok {
+				g = &keyedGroup{sortKey: sortKey, group: TaskGroup{Group: names}}
+				byKey[id] = g
+				order = append(order, g)
+			}
+			if q.groupLimit == 0 || len(g.group.Tasks) < q.groupLimit {
+				g.group.Tasks = append(g.group.Tasks, *t.Task)
+			}
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		for k := range order[i].sortKey {
+			if order[i].sortKey[k] != order[j].sortKey[k] {
+				return order[i].sortKey[k] < order[j].sortKey[k]
+			}
+		}
+		return false
+	})
+	groups := make([]TaskGroup, len(order))
+	for i, g := range order {
+		groups[i] = g.group
+	}
+	return total, groups
+}
+
+// taskGroupPaths returns every combination of groups a task falls in, one
+// key per grouper.
+func taskGroupPaths(groupers []taskGrouper, t *queryTask) [][]taskGroupKey {
+	paths := [][]taskGroupKey{nil}
+	for _, grouper := range groupers {
+		var next [][]taskGroupKey
+		for _, path := range paths {
+			for _, key := range grouper(t) {
+				next = append(next, append(append([]taskGroupKey(nil), path...), key))
+			}
+		}
+		paths = next
+	}
+	return paths
+}
+
+// queryTasks collects the tasks of every note under searchPath with the
+// vault's statuses, their headings and blocked state.
+func (v *Vault) queryTasks(searchPath string) ([]*queryTask, error) {
+	notes, err := v.indexedNotes(searchPath)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := v.taskStatuses()
+	openIDs := make(map[string]bool)
+	var tasks []*queryTask
+	for _, note := range notes {
+		headings := lineHeadings(note.Lines)
+		for i := range note.Tasks {
+			// Index entries are shared, so statuses are applied to a copy.
+			task := new(Task)
+			*task = note.Tasks[i]
+			statuses.apply(task)
+			if task.ID != nil && task.isOpen() {
+				openIDs[*task.ID] = true
+			}
+			tasks = append(tasks, &queryTask{Task: task, heading: headings[task.Line-1]})
+		}
+	}
+	for _, t := range tasks {
+		for _, id := range t.DependsOn {
+			if openIDs[id] {
+				t.blocked = true
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// lineHeadings returns the heading each line falls under, ignoring
+// headings in code blocks.
+func lineHeadings(lines []string) []string {
+	headings := make([]string, len(lines))
+	current := ""
+	for i := 0; i < len(lines); i++ {
+		if fence, _ := codeFence(lines[i]); fence != "" {
+			end := closingFence(lines, i+1, fence)
+			if end < 0 {
+				end = len(lines) - 1
+			}
+			for ; i <= end; i++ {
+				headings[i] = current
+			}
+			i = end
+			continue
+		}
+		if m := headingRegex.FindStringSubmatch(strings.TrimSpace(lines[i])); m != nil {
+			current = strings.TrimSpace(m[2])
+		}
+		headings[i] = current
+	}
+	return headings
+}
+
+// runTasksQuery parses and evaluates a Tasks query over the notes under
+// searchPath.
+func (v *Vault) runTasksQuery(query, searchPath string) (int, []TaskGroup, error) {
+	q, err := parseTasksQuery(query, time.Now())
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid tasks query: %v", err)
+	}
+	tasks, err := v.queryTasks(searchPath)
+	if err != nil {
+		return 0, nil, fmt.Errorf("tasks query failed: %v", err)
+	}
+	total, groups := q.execute(tasks)
+	return total, groups, nil
+}
+
+// TasksQueryHandler runs an Obsidian Tasks query block over the vault's tasks
+func (v *Vault) TasksQueryHandler(ctx context.Context, req *mcp.CallToolRequest, args TasksQueryArgs) (*mcp.CallToolResult, any, error) {
+	searchPath := v.GetPath()
+	if args.Directory != "" {
+		searchPath = filepath.Join(v.GetPath(), args.Directory)
+	}
+	if !v.isPathSafe(searchPath) {
+		return nil, nil, fmt.Errorf("search path must be within vault")
+	}
+
+	total, groups, err := v.runTasksQuery(args.Query, searchPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !isDetailedMode(args.Mode) {
+		returned := 0
+		for _, g := range groups {
+			returned += len(g.Tasks)
+		}
+		return compactResult(fmt.Sprintf("Tasks query matched %d tasks", total), returned < total, map[string]any{
+			"query":       args.Query,
+			"total_tasks": total,
+			"returned":    returned,
+			"groups":      groups,
+		}, nil)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatTaskGroups(total, groups)},
+		},
+	}, nil, nil
+}
+
+// formatTaskGroups renders query results as markdown, the way the Tasks
+// plugin displays them: a heading per group and a task list with a link to
+// each task's note.
+func formatTaskGroups(total int, groups []TaskGroup) string {
+	var sb strings.Builder
+	var previous []string
+	for _, g := range groups {
+		for level, name := range g.Group {
+			if level < len(previous) && previous[level] == name && slices.Equal(previous[:level], g.Group[:level]) {
+				continue
+			}
+			fmt.Fprintf(&sb, "%s %s\n\n", strings.Repeat("#", min(4+level, 6)), name)
+		}
+		previous = g.Group
+		for _, t := range g.Tasks {
+			fmt.Fprintf(&sb, "- [%s] %s ([[%s]])\n", t.Status, t.Text, strings.TrimSuffix(filepath.ToSlash(t.File), ".md"))
+		}
+		if len(g.Group) > 0 {
+			sb.WriteString("\n")
+		}
+	}
+	noun := "tasks"
+	if total == 1 {
+		noun = "task"
+	}
+	fmt.Fprintf(&sb, "%d %s\n", total, noun)
+	return sb.String()
+}
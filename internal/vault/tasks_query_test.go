@@ -0,0 +1,229 @@
+package vault
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// tasksQueryNow is a Wednesday; "this week" runs from Monday 2024-01-08 to
+// Sunday 2024-01-14.
+var tasksQueryNow = time.Date(2024, 1, 10, 9, 0, 0, 0, time.Local)
+
+func setupTasksQueryVault(t *testing.T) *Vault {
+	t.Helper()
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "projects/alpha.md", strings.Join([]string{
+		"# Alpha",
+		"## Sprint",
+		"- [ ] Ship release 📅 2024-01-10 ⏫ #work",
+		"- [ ] Write docs ⏳ 2024-01-12 🔼 #work/docs",
+		"- [x] Plan 📅 2024-01-05 ✅ 2024-01-05",
+		"- [ ] Deploy ⛔ rel 📅 2024-01-20",
+		"- [ ] Release 🆔 rel 🔁 every week 🔺 📅 2024-01-15",
+	}, "\n"))
+	writeTestFile(t, dir, "home.md", "- [ ] Groceries 📅 2024-01-09 #errand\n- [ ] Someday 🔽\n\n```\n# not a heading\n```")
+	return v
+}
+
+func runTestTasksQuery(t *testing.T, v *Vault, query string) (int, []TaskGroup) {
+	t.Helper()
+	q, err := parseTasksQuery(query, tasksQueryNow)
+	if err != nil {
+		t.Fatalf("parseTasksQuery(%q): %v", query, err)
+	}
+	tasks, err := v.queryTasks(v.GetPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q.execute(tasks)
+}
+
+func taskDescriptions(tasks []Task) string {
+	names := make([]string, len(tasks))
+	for i, task := range tasks {
+		names[i] = task.Description
+	}
+	return strings.Join(names, ", ")
+}
+
+func TestTasksQueryFilters(t *testing.T) {
+	v := setupTasksQueryVault(t)
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"not done\ndue before tomorrow", "Groceries #errand, Ship release #work"},
+		{"done", "Plan"},
+		{"scheduled this week", "Write docs #work/docs"},
+		{"due next week", "Deploy, Release"},
+		{"due in 2024-01-10 2024-01-15", "Release, Ship release #work"},
+		{"due on or after in 5 days", "Deploy, Release"},
+		{"happens today", "Ship release #work"},
+		{"no due date", "Someday, Write docs #work/docs"},
+		{"priority is high", "Ship release #work"},
+		{"priority is above medium", "Release, Ship release #work"},
+		{"priority is none", "Deploy, Groceries #errand, Plan"},
+		{"path includes projects\nnot done\ntags include #work", "Ship release #work, Write docs #work/docs"},
+		{"tags do not include work\nnot done", "Deploy, Groceries #errand, Release, Someday"},
+		{"heading includes sprint\ndescription does not include e", "Plan"},
+		{"filename includes home", "Groceries #errand, Someday"},
+		{"is blocked", "Deploy"},
+		{"is recurring", "Release"},
+		{"(due before 2024-01-10) OR (priority is low)", "Groceries #errand, Plan, Someday"},
+		{"NOT (done) AND (has due date)\n# a comment\nhide edit button", "Deploy, Groceries #errand, Release, Ship release #work"},
+	}
+	for _, tt := range tests {
+		_, groups := runTestTasksQuery(t, v, tt.query+"\nsort by description")
+		if got := taskDescriptions(groups[0].Tasks); got != tt.want {
+			t.Errorf("%q = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"frobnicate", "due whenever", "priority is urgent", "(done", "(done) XOR (not done)", "sort by mood", "group by mood", "limit lots"} {
+		if _, err := parseTasksQuery(bad, tasksQueryNow); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestTasksQueryDateRanges(t *testing.T) {
+	tests := []struct {
+		expr     string
+		from, to string
+	}{
+		{"today", "2024-01-10", "2024-01-10"},
+		{"yesterday", "2024-01-09", "2024-01-09"},
+		{"this week", "2024-01-08", "2024-01-14"},
+		{"last week", "2024-01-01", "2024-01-07"},
+		{"last month", "2023-12-01", "2023-12-31"},
+		{"next year", "2025-01-01", "2025-12-31"},
+		{"in 2 weeks", "2024-01-24", "2024-01-24"},
+		{"3 days ago", "2024-01-07", "2024-01-07"},
+	}
+	today := time.Date(2024, 1, 10, 0, 0, 0, 0, time.Local)
+	for _, tt := range tests {
+		from, to, err := parseTaskDateRange(tt.expr, today)
+		if err != nil {
+			t.Errorf("%q: %v", tt.expr, err)
+			continue
+		}
+		if got := from.Format("2006-01-02") + " " + to.Format("2006-01-02"); got != tt.from+" "+tt.to {
+			t.Errorf("%q = %s, want %s %s", tt.expr, got, tt.from, tt.to)
+		}
+	}
+}
+
+func TestTasksQuerySortGroupLimit(t *testing.T) {
+	v := setupTasksQueryVault(t)
+
+	total, groups := runTestTasksQuery(t, v, "not done\nsort by due\nlimit 2")
+	if total != 6 || taskDescriptions(groups[0].Tasks) != "Groceries #errand, Ship release #work" {
+		t.Errorf("limit: total %d, tasks %s", total, taskDescriptions(groups[0].Tasks))
+	}
+
+	_, groups = runTestTasksQuery(t, v, "sort by priority reverse\nsort by description\nlimit to 3 tasks")
+	if got := taskDescriptions(groups[0].Tasks); got != "Someday, Deploy, Groceries #errand" {
+		t.Errorf("reverse priority = %s", got)
+	}
+
+	_, groups = runTestTasksQuery(t, v, "not done\ngroup by priority\ngroup by filename\nsort by description")
+	var got []string
+	for _, g := range groups {
+		got = append(got, strings.Join(g.Group, "/")+": "+taskDescriptions(g.Tasks))
+	}
+	want := []string{
+		"Highest priority/alpha: Release",
+		"High priority/alpha: Ship release #work",
+		"Medium priority/alpha: Write docs #work/docs",
+		"Normal priority/alpha: Deploy",
+		"Normal priority/home: Groceries #errand",
+		"Low priority/home: Someday",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("groups:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	_, groups = runTestTasksQuery(t, v, "group by due reverse\nlimit groups 1\nsort by description")
+	if len(groups) != 6 || groups[0].Group[0] != "No due date" || groups[1].Group[0] != "2024-01-20 Saturday" {
+		t.Errorf("unexpected due groups: %+v", groups)
+	}
+	if len(groups[0].Tasks) != 1 {
+		t.Errorf("limit groups should cap each group, got %d tasks", len(groups[0].Tasks))
+	}
+
+	_, groups = runTestTasksQuery(t, v, "group by tags\nnot done\nsort by description")
+	if groups[0].Group[0] != "#errand" || taskDescriptions(groups[len(groups)-1].Tasks) != "Deploy, Release, Someday" {
+		t.Errorf("unexpected tag groups: %+v", groups)
+	}
+
+	_, groups = runTestTasksQuery(t, v, "group by heading\nfilename includes home")
+	if len(groups) != 1 || groups[0].Group[0] != "(No heading)" {
+		t.Errorf("code block headings should be ignored: %+v", groups)
+	}
+}
+
+func TestTasksQueryHandler(t *testing.T) {
+	v := setupTasksQueryVault(t)
+	ctx := context.Background()
+
+	result, _, err := v.ManageTasksMultiplexHandler(ctx, nil, ManageTasksMultiplexArgs{
+		Action: "query",
+		Query:  "not done\npath includes projects\ngroup by filename\nsort by description\nlimit 2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data struct {
+		Total    int         `json:"total_tasks"`
+		Returned int         `json:"returned"`
+		Groups   []TaskGroup `json:"groups"`
+	}
+	compactData(t, result, &data)
+	if data.Total != 4 || data.Returned != 2 || len(data.Groups) != 1 || data.Groups[0].Group[0] != "alpha" {
+		t.Errorf("unexpected compact result: %+v", data)
+	}
+
+	result, _, err = v.TasksQueryHandler(ctx, nil, TasksQueryArgs{Query: "not done\ngroup by filename\nsort by due\nlimit 3", Mode: "detailed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "#### alpha\n\n- [ ] Ship release 📅 2024-01-10 ⏫ #work ([[projects/alpha]])\n- [ ] Release 🆔 rel 🔁 every week 🔺 📅 2024-01-15 ([[projects/alpha]])\n\n" +
+		"#### home\n\n- [ ] Groceries 📅 2024-01-09 #errand ([[home]])\n\n6 tasks\n"
+	if text := result.Content[0].(*mcp.TextContent).Text; text != want {
+		t.Errorf("got:\n%s\nwant:\n%s", text, want)
+	}
+
+	if _, _, err := v.TasksQueryHandler(ctx, nil, TasksQueryArgs{Query: "due someday"}); err == nil {
+		t.Error("expected error for invalid query")
+	}
+	if _, _, err := v.TasksQueryHandler(ctx, nil, TasksQueryArgs{Query: "done", Directory: "../outside"}); err == nil {
+		t.Error("expected error for directory outside the vault")
+	}
+}
+
+func TestReadNoteRendersTasksBlocks(t *testing.T) {
+	v := setupTasksQueryVault(t)
+	writeTestFile(t, v.GetPath(), "dashboard.md", "# Dashboard\n\n```tasks\ndone\n```\n\n```tasks\nsometimes\n```\n")
+
+	result, _, err := v.ReadNoteHandler(context.Background(), nil, ReadNoteArgs{Path: "dashboard.md", RenderQueries: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{
+		"<!-- obx:generated tasks: done -->",
+		"- [x] Plan 📅 2024-01-05 ✅ 2024-01-05 ([[projects/alpha]])\n1 task",
+		"**Tasks query error:**",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("rendered note missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "```tasks") {
+		t.Errorf("tasks blocks should be substituted:\n%s", text)
+	}
+}
@@ -1,7 +1,9 @@
 package vault
 
 import (
+	"context"
 	"testing"
+	"time"
 )
 
 func TestParseTask(t *testing.T) {
@@ -233,3 +235,80 @@ func TestParseTask_TagFormats(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTask_TasksPluginFields(t *testing.T) {
+	line := "- [ ] Water plants #home 🔺 🔁 every week on Sunday ➕ 2024-01-01 🛫 2024-01-05 ⏳ 2024-01-06 📅 2024-01-07 🆔 water ⛔ buy, fetch 🏁 delete ^blk"
+	task := ParseTask(line, 3)
+	if task == nil {
+		t.Fatal("ParseTask() = nil, want non-nil")
+	}
+	checks := map[string]*string{
+		"2024-01-01":           task.CreatedDate,
+		"2024-01-05":           task.StartDate,
+		"2024-01-06":           task.ScheduledDate,
+		"2024-01-07":           task.DueDate,
+		"highest":              task.Priority,
+		"every week on Sunday": task.Recurrence,
+		"water":                task.ID,
+		"delete":               task.OnCompletion,
+	}
+	for want, got := range checks {
+		if got == nil || *got != want {
+			t.Errorf("expected %q, got %v", want, got)
+		}
+	}
+	if len(task.DependsOn) != 2 || task.DependsOn[0] != "buy" || task.DependsOn[1] != "fetch" {
+		t.Errorf("DependsOn = %v, want [buy fetch]", task.DependsOn)
+	}
+	if task.Description != "Water plants #home" {
+		t.Errorf("Description = %q, want %q", task.Description, "Water plants #home")
+	}
+	if len(task.Tags) != 1 || task.Tags[0] != "home" {
+		t.Errorf("Tags = %v, want [home]", task.Tags)
+	}
+
+	done := ParseTask("- [x] Ship it ⏬ ✅ 2024-02-01 ❌ 2024-02-02", 1)
+	if done.DoneDate == nil || *done.DoneDate != "2024-02-01" || done.CancelledDate == nil || *done.CancelledDate != "2024-02-02" {
+		t.Errorf("unexpected done/cancelled dates: %v %v", done.DoneDate, done.CancelledDate)
+	}
+	if done.Priority == nil || *done.Priority != "lowest" {
+		t.Errorf("Priority = %v, want lowest", done.Priority)
+	}
+}
+
+func TestSetTaskDoneDate(t *testing.T) {
+	tests := []struct {
+		line, date, want string
+	}{
+		{"  - [x] Task 📅 2024-01-07", "2024-01-08", "  - [x] Task 📅 2024-01-07 ✅ 2024-01-08"},
+		{"- [x] Task ✅ 2024-01-01", "2024-01-08", "- [x] Task ✅ 2024-01-08"},
+		{"- [x] Task ^blk", "2024-01-08", "- [x] Task ✅ 2024-01-08 ^blk"},
+		{"\t- [ ] Task ✅ 2024-01-01 #tag", "", "\t- [ ] Task #tag"},
+	}
+	for _, tt := range tests {
+		if got := setTaskDoneDate(tt.line, tt.date); got != tt.want {
+			t.Errorf("setTaskDoneDate(%q, %q) = %q, want %q", tt.line, tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestCompletingTasksStampsDoneDate(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "todo.md", "- [ ] First 📅 2024-01-07\n- [ ] Second\n- [x] Third ✅ 2024-01-01")
+	today := time.Now().Format("2006-01-02")
+
+	if _, _, err := v.ToggleTaskHandler(context.Background(), nil, ToggleTaskArgs{Path: "todo.md", Line: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := v.ToggleTaskHandler(context.Background(), nil, ToggleTaskArgs{Path: "todo.md", Text: "Third"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := v.CompleteTasksHandler(context.Background(), nil, CompleteTasksArgs{Path: "todo.md", Texts: "Second"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- [x] First 📅 2024-01-07 ✅ " + today + "\n- [x] Second ✅ " + today + "\n- [ ] Third"
+	if got := readTestFile(t, dir, "todo.md"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
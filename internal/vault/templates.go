@@ -21,7 +21,7 @@ var (
 func (v *Vault) ListTemplatesHandler(ctx context.Context, req *mcp.CallToolRequest, args ListTemplatesArgs) (*mcp.CallToolResult, any, error) {
 	folder := args.Folder
 	if folder == "" {
-		folder = "templates"
+		folder = v.obsidianSettings().TemplatesFolder
 	}
 
 	searchPath := filepath.Join(v.GetPath(), folder)
@@ -39,7 +39,7 @@ func (v *Vault) ListTemplatesHandler(ctx context.Context, req *mcp.CallToolReque
 
 	var templates []string
 
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+	err := v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
@@ -80,7 +80,7 @@ func (v *Vault) GetTemplateHandler(ctx context.Context, req *mcp.CallToolRequest
 	name := args.Name
 	folder := args.Folder
 	if folder == "" {
-		folder = "templates"
+		folder = v.obsidianSettings().TemplatesFolder
 	}
 
 	if !strings.HasSuffix(name, ".md") {
@@ -144,8 +144,9 @@ func (v *Vault) ApplyTemplateHandler(ctx context.Context, req *mcp.CallToolReque
 	templateName := args.Template
 	targetPath := args.Path
 	templateFolder := args.TemplateFolder
+	settings := v.obsidianSettings()
 	if templateFolder == "" {
-		templateFolder = "templates"
+		templateFolder = settings.TemplatesFolder
 	}
 	varsStr := args.Variables
 
@@ -155,6 +156,7 @@ func (v *Vault) ApplyTemplateHandler(ctx context.Context, req *mcp.CallToolReque
 	if !strings.HasSuffix(targetPath, ".md") {
 		targetPath += ".md"
 	}
+	targetPath = settings.newNotePath(targetPath, "")
 
 	// Read template
 	templatePath := filepath.Join(v.GetPath(), templateFolder, templateName)
@@ -194,8 +196,8 @@ func (v *Vault) ApplyTemplateHandler(ctx context.Context, req *mcp.CallToolReque
 	// Add built-in variables
 	now := time.Now()
 	builtinVars := map[string]string{
-		"date":      now.Format("2006-01-02"),
-		"time":      now.Format("15:04"),
+		"date":      formatMoment(now, settings.TemplateDateFormat),
+		"time":      formatMoment(now, settings.TemplateTimeFormat),
 		"datetime":  now.Format("2006-01-02 15:04"),
 		"year":      now.Format("2006"),
 		"month":     now.Format("01"),
@@ -27,7 +27,10 @@ type WriteNoteArgs struct {
 
 // ReadNoteArgs arguments for read-note
 type ReadNoteArgs struct {
-	Path string `json:"path" jsonschema:"Path to the note relative to vault root"`
+	Path          string `json:"path" jsonschema:"Path to the note relative to vault root"`
+	RenderQueries bool   `json:"render_queries,omitempty" jsonschema:"Replace dataview and tasks code blocks with their rendered results"`
+	ResolveEmbeds bool   `json:"resolve_embeds,omitempty" jsonschema:"Inline embedded notes, sections and blocks, and list embedded attachments"`
+	EmbedDepth    int    `json:"embed_depth,omitempty" jsonschema:"How many levels of nested embeds to inline (default 3, max 10)"`
 }
 
 // DeleteNoteArgs arguments for delete-note
@@ -41,6 +44,7 @@ type DeleteNoteArgs struct {
 type ReadNotesArgs struct {
 	Paths              string `json:"paths" jsonschema:"Comma-separated list or JSON array of paths"`
 	IncludeFrontmatter bool   `json:"include_frontmatter,omitempty" jsonschema:"Include frontmatter in output (default true)"`
+	RenderQueries      bool   `json:"render_queries,omitempty" jsonschema:"Replace dataview and tasks code blocks with their rendered results"`
 }
 
 // GetNoteSummaryArgs arguments for get-note-summary
@@ -55,6 +59,12 @@ type GetSectionArgs struct {
 	Heading string `json:"heading" jsonschema:"Heading to extract"`
 }
 
+// GetBlockArgs arguments for get-block
+type GetBlockArgs struct {
+	Path    string `json:"path" jsonschema:"Path to the note"`
+	BlockID string `json:"block_id" jsonschema:"Block ID, with or without the leading ^"`
+}
+
 // GetHeadingsArgs arguments for get-headings
 type GetHeadingsArgs struct {
 	Path string `json:"path" jsonschema:"Path to the note"`
@@ -79,6 +89,23 @@ type EditNoteArgs struct {
 	ExpectedMtime string `json:"expected_mtime,omitempty" jsonschema:"Expected file modification time (RFC3339Nano) for optimistic concurrency"`
 }
 
+// AddBlockIDArgs arguments for add-block-id
+type AddBlockIDArgs struct {
+	Path          string `json:"path" jsonschema:"Path to the note"`
+	Text          string `json:"text" jsonschema:"Text on the line to mark; must match one line"`
+	BlockID       string `json:"block_id,omitempty" jsonschema:"Block ID to use (default: a generated six-character ID)"`
+	ExpectedMtime string `json:"expected_mtime,omitempty" jsonschema:"Expected file modification time (RFC3339Nano) for optimistic concurrency"`
+}
+
+// RenameHeadingArgs arguments for rename-heading
+type RenameHeadingArgs struct {
+	Path          string `json:"path" jsonschema:"Path to the note"`
+	Heading       string `json:"heading" jsonschema:"Heading to rename"`
+	NewHeading    string `json:"new_heading" jsonschema:"New heading text"`
+	DryRun        bool   `json:"dry_run,omitempty" jsonschema:"Preview the rename and list the files whose links would change"`
+	ExpectedMtime string `json:"expected_mtime,omitempty" jsonschema:"Expected file modification time (RFC3339Nano) for optimistic concurrency"`
+}
+
 // ReplaceSectionArgs arguments for replace-section
 type ReplaceSectionArgs struct {
 	Path          string `json:"path" jsonschema:"Path to the note"`
@@ -151,11 +178,51 @@ type SearchRegexArgs struct {
 	Mode            string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
 }
 
+// SearchRankedArgs arguments for ranked full-text search
+type SearchRankedArgs struct {
+	Query     string `json:"query" jsonschema:"Search query (words are stemmed; results ranked by BM25)"`
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit search to"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum results to return (default 20)"`
+	Offset    int    `json:"offset,omitempty" jsonschema:"Number of ranked results to skip (for pagination)"`
+	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
+}
+
+// SemanticSearchArgs arguments for semantic search
+type SemanticSearchArgs struct {
+	Query     string `json:"query" jsonschema:"What to look for, in your own words (matched by meaning, not keywords)"`
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit search to"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum results to return (default 20)"`
+	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
+}
+
+// SimilarNotesArgs arguments for similar-notes
+type SimilarNotesArgs struct {
+	Path      string `json:"path" jsonschema:"Note to find similar notes for"`
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit results to"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum results to return (default 10)"`
+	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
+}
+
+// SearchQueryArgs arguments for Obsidian search-syntax queries
+type SearchQueryArgs struct {
+	Query     string `json:"query" jsonschema:"Obsidian search query, e.g. tag:#project path:work/ \"exact phrase\" -draft (foo OR bar) line:(a b) section:(x) task-todo:(review) [status:done]"`
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit search to"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum notes to return (default 50)"`
+	Offset    int    `json:"offset,omitempty" jsonschema:"Number of matching notes to skip (for pagination)"`
+	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
+}
+
+// DataviewArgs arguments for Dataview (DQL) queries
+type DataviewArgs struct {
+	Query string `json:"query" jsonschema:"DQL query, e.g. TABLE status, due FROM #project WHERE !completed SORT due ASC LIMIT 10"`
+	Mode  string `json:"mode,omitempty" jsonschema:"Response mode: compact (default, structured rows) or detailed (rendered markdown)"`
+}
+
 // --- Tasks ---
 
 // ListTasksArgs arguments for list-tasks
 type ListTasksArgs struct {
-	Status    string `json:"status,omitempty" jsonschema:"Filter by status: 'all' (default), 'open', 'completed'"`
+	Status    string `json:"status,omitempty" jsonschema:"Filter by status: 'all' (default), 'open', 'completed', or a status type: 'todo', 'in_progress', 'done', 'cancelled', 'non_task'"`
 	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit search to"`
 	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum tasks to return (default: all in detailed mode, 100 in compact mode)"`
 	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
@@ -176,6 +243,13 @@ type CompleteTasksArgs struct {
 	ExpectedMtime string `json:"expected_mtime,omitempty" jsonschema:"Expected file modification time (RFC3339Nano) for optimistic concurrency"`
 }
 
+// TasksQueryArgs arguments for querying tasks with the Tasks plugin query language
+type TasksQueryArgs struct {
+	Query     string `json:"query" jsonschema:"Tasks query block, one instruction per line, e.g. not done / due before tomorrow / group by due / limit 20"`
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit search to"`
+	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default, grouped tasks) or detailed (rendered markdown)"`
+}
+
 // --- Tags ---
 
 // SearchTagsArgs arguments for search-by-tags
@@ -206,7 +280,7 @@ type BulkMoveArgs struct {
 type BulkSetFrontmatterArgs struct {
 	Paths  string `json:"paths" jsonschema:"Comma-separated list or JSON array of paths"`
 	Key    string `json:"key" jsonschema:"Frontmatter key"`
-	Value  string `json:"value" jsonschema:"Value to set"`
+	Value  string `json:"value" jsonschema:"Value to set; JSON arrays and objects are stored as YAML lists and maps"`
 	DryRun bool   `json:"dry_run,omitempty" jsonschema:"Preview changes without modifying files"`
 }
 
@@ -243,38 +317,38 @@ type DeleteDirArgs struct {
 // DailyNoteArgs arguments for daily-note
 type DailyNoteArgs struct {
 	Date            string `json:"date,omitempty" jsonschema:"Date string (default: today)"`
-	Folder          string `json:"folder,omitempty" jsonschema:"Folder for daily notes (default: 'daily')"`
-	Format          string `json:"format,omitempty" jsonschema:"Date format (default: '2006-01-02')"`
+	Folder          string `json:"folder,omitempty" jsonschema:"Folder for daily notes (default: from Obsidian settings, else 'daily')"`
+	Format          string `json:"format,omitempty" jsonschema:"Go date layout (default: the Obsidian format setting, else '2006-01-02')"`
 	CreateIfMissing bool   `json:"create,omitempty" jsonschema:"Create if missing (default: true)"`
 }
 
 // WeeklyNoteArgs arguments for weekly-note
 type WeeklyNoteArgs struct {
 	Date            string `json:"date,omitempty" jsonschema:"Date string (default: today)"`
-	Folder          string `json:"folder,omitempty" jsonschema:"Folder for weekly notes (default: 'weekly')"`
-	Format          string `json:"format,omitempty" jsonschema:"Date format (default: '2006-W02')"`
+	Folder          string `json:"folder,omitempty" jsonschema:"Folder for weekly notes (default: from Obsidian settings, else 'weekly')"`
+	Format          string `json:"format,omitempty" jsonschema:"Go date layout (default: the Obsidian format setting, else '2006-W02')"`
 	CreateIfMissing bool   `json:"create,omitempty" jsonschema:"Create if missing (default: true)"`
 }
 
 // MonthlyNoteArgs arguments for monthly-note
 type MonthlyNoteArgs struct {
 	Date            string `json:"date,omitempty" jsonschema:"Date string (default: today)"`
-	Folder          string `json:"folder,omitempty" jsonschema:"Folder for monthly notes (default: 'monthly')"`
-	Format          string `json:"format,omitempty" jsonschema:"Date format (default: '2006-01')"`
+	Folder          string `json:"folder,omitempty" jsonschema:"Folder for monthly notes (default: from Obsidian settings, else 'monthly')"`
+	Format          string `json:"format,omitempty" jsonschema:"Go date layout (default: the Obsidian format setting, else '2006-01')"`
 	CreateIfMissing bool   `json:"create,omitempty" jsonschema:"Create if missing (default: true)"`
 }
 
 // QuarterlyNoteArgs arguments for quarterly-note
 type QuarterlyNoteArgs struct {
 	Date            string `json:"date,omitempty" jsonschema:"Date string (default: today)"`
-	Folder          string `json:"folder,omitempty" jsonschema:"Folder for quarterly notes (default: 'quarterly')"`
+	Folder          string `json:"folder,omitempty" jsonschema:"Folder for quarterly notes (default: from Obsidian settings, else 'quarterly')"`
 	CreateIfMissing bool   `json:"create,omitempty" jsonschema:"Create if missing (default: true)"`
 }
 
 // YearlyNoteArgs arguments for yearly-note
 type YearlyNoteArgs struct {
 	Date            string `json:"date,omitempty" jsonschema:"Date string (default: today)"`
-	Folder          string `json:"folder,omitempty" jsonschema:"Folder for yearly notes (default: 'yearly')"`
+	Folder          string `json:"folder,omitempty" jsonschema:"Folder for yearly notes (default: from Obsidian settings, else 'yearly')"`
 	CreateIfMissing bool   `json:"create,omitempty" jsonschema:"Create if missing (default: true)"`
 }
 
@@ -289,20 +363,20 @@ type ListPeriodicArgs struct {
 
 // ListTemplatesArgs arguments for list-templates
 type ListTemplatesArgs struct {
-	Folder string `json:"folder,omitempty" jsonschema:"Templates folder (default: 'templates')"`
+	Folder string `json:"folder,omitempty" jsonschema:"Templates folder (default: from .obsidian/templates.json, else 'templates')"`
 }
 
 // GetTemplateArgs arguments for get-template
 type GetTemplateArgs struct {
 	Name   string `json:"name" jsonschema:"Template name"`
-	Folder string `json:"folder,omitempty" jsonschema:"Templates folder (default: 'templates')"`
+	Folder string `json:"folder,omitempty" jsonschema:"Templates folder (default: from .obsidian/templates.json, else 'templates')"`
 }
 
 // ApplyTemplateArgs arguments for apply-template
 type ApplyTemplateArgs struct {
 	Template       string `json:"template" jsonschema:"Template name"`
 	Path           string `json:"path" jsonschema:"Target note path"`
-	TemplateFolder string `json:"template_folder,omitempty" jsonschema:"Templates folder (default: 'templates')"`
+	TemplateFolder string `json:"template_folder,omitempty" jsonschema:"Templates folder (default: from .obsidian/templates.json, else 'templates')"`
 	Variables      string `json:"variables,omitempty" jsonschema:"JSON string or key=value pairs of variables"`
 }
 
@@ -310,8 +384,15 @@ type ApplyTemplateArgs struct {
 
 // QueryFrontmatterArgs arguments for query-frontmatter
 type QueryFrontmatterArgs struct {
-	Query     string `json:"query" jsonschema:"Query string (key=value or key:value)"`
+	Query     string `json:"query" jsonschema:"Query such as priority>=2 AND status!=done AND tags contains \"client\"; supports =, !=, <, <=, >, >=, : (substring), contains, exists, missing, AND, OR, NOT and parentheses"`
 	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit search to"`
+	Sort      string `json:"sort,omitempty" jsonschema:"Frontmatter key to sort by; append ' desc' or prefix '-' for descending"`
+}
+
+// ListPropertiesArgs arguments for list-properties
+type ListPropertiesArgs struct {
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit the scan to"`
+	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
 }
 
 // GetFrontmatterArgs arguments for get-frontmatter
@@ -323,7 +404,7 @@ type GetFrontmatterArgs struct {
 type SetFrontmatterArgs struct {
 	Path          string `json:"path" jsonschema:"Path to the note"`
 	Key           string `json:"key" jsonschema:"Frontmatter key"`
-	Value         string `json:"value" jsonschema:"Value to set"`
+	Value         string `json:"value" jsonschema:"Value to set; JSON arrays and objects are stored as YAML lists and maps"`
 	ExpectedMtime string `json:"expected_mtime,omitempty" jsonschema:"Expected file modification time (RFC3339Nano) for optimistic concurrency"`
 }
 
@@ -367,6 +448,46 @@ type OrphanNotesArgs struct {
 	IncludeDeadEnds bool   `json:"include_no_outgoing,omitempty" jsonschema:"Include notes with no outgoing links (dead ends)"`
 }
 
+// ExportGraphArgs arguments for export-graph
+type ExportGraphArgs struct {
+	Format    string `json:"format,omitempty" jsonschema:"Output format: 'json' (node-link, default), 'graphml', 'gexf' or 'dot'"`
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit the graph to"`
+	Output    string `json:"output,omitempty" jsonschema:"Vault path to write the graph to (default: return it)"`
+}
+
+// GraphCentralityArgs arguments for centrality
+type GraphCentralityArgs struct {
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit the graph to"`
+	Metric    string `json:"metric,omitempty" jsonschema:"Centrality measure: 'pagerank' (default) or 'betweenness'"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum notes to return (default 20)"`
+	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
+}
+
+// GraphClustersArgs arguments for components and communities
+type GraphClustersArgs struct {
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit the graph to"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum groups to return (default 20)"`
+	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
+}
+
+// ShortestPathArgs arguments for shortest-path
+type ShortestPathArgs struct {
+	Source    string `json:"source" jsonschema:"Note to start from"`
+	Target    string `json:"target" jsonschema:"Note to reach"`
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit the graph to"`
+	Directed  bool   `json:"directed,omitempty" jsonschema:"Only follow links forward (default: either direction)"`
+	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
+}
+
+// NeighborhoodArgs arguments for neighborhood
+type NeighborhoodArgs struct {
+	Path      string `json:"path" jsonschema:"Note at the center"`
+	Depth     int    `json:"depth,omitempty" jsonschema:"Maximum hops from the note (default 1)"`
+	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit the graph to"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum notes to return (default 50)"`
+	Mode      string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
+}
+
 // BrokenLinksArgs arguments for broken-links
 type BrokenLinksArgs struct {
 	Directory string `json:"directory,omitempty" jsonschema:"Directory to limit search to"`
@@ -402,6 +523,24 @@ type GetBacklinksArgs struct {
 	Path string `json:"path" jsonschema:"Path to the note"`
 }
 
+// LocalGraphArgs arguments for local-graph
+type LocalGraphArgs struct {
+	Path          string `json:"path" jsonschema:"Note at the center of the graph"`
+	Depth         int    `json:"depth,omitempty" jsonschema:"Maximum hops from the note, in either direction (default 1)"`
+	IncludeEmbeds bool   `json:"include_embeds,omitempty" jsonschema:"Follow embeds as well as links"`
+	ExcludeTags   bool   `json:"exclude_tags,omitempty" jsonschema:"Leave out tag nodes and the notes reached through them"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"Maximum nodes to return (default 100)"`
+	Canvas        string `json:"canvas,omitempty" jsonschema:"Vault path of a .canvas file to render the graph to"`
+	Overwrite     bool   `json:"overwrite,omitempty" jsonschema:"Replace the canvas file if it already exists"`
+	Mode          string `json:"mode,omitempty" jsonschema:"Response mode: compact (default) or detailed"`
+}
+
+// BlockReferencesArgs arguments for block-refs
+type BlockReferencesArgs struct {
+	Path    string `json:"path" jsonschema:"Path to the note containing the block"`
+	BlockID string `json:"block_id,omitempty" jsonschema:"Block ID to find references to (default: every block in the note)"`
+}
+
 // RenameNoteArgs arguments for rename-note
 type RenameNoteArgs struct {
 	OldPath string `json:"old_path" jsonschema:"Old note path"`
@@ -512,8 +651,9 @@ type UnlinkedMentionsArgs struct {
 
 // SuggestLinksArgs arguments for suggest-links
 type SuggestLinksArgs struct {
-	Path  string `json:"path" jsonschema:"Path to the note"`
-	Limit int    `json:"limit,omitempty" jsonschema:"Maximum results (default 10)"`
+	Path     string `json:"path" jsonschema:"Path to the note"`
+	Limit    int    `json:"limit,omitempty" jsonschema:"Maximum results (default 10)"`
+	Semantic bool   `json:"semantic,omitempty" jsonschema:"Also suggest notes with related content, building the semantic index if needed (default: only when the index already exists)"`
 }
 
 // --- Canvas ---
@@ -543,3 +683,25 @@ type ConnectNodesArgs struct {
 	To     string `json:"to" jsonschema:"Target node ID"`
 	Label  string `json:"label,omitempty" jsonschema:"Edge label"`
 }
+
+// --- Attachments ---
+
+// ListAttachmentsArgs arguments for list-attachments, unused-attachments and broken-attachments
+type ListAttachmentsArgs struct {
+	Directory string `json:"directory,omitempty" jsonschema:"Folder to limit to (optional)"`
+	Extension string `json:"extension,omitempty" jsonschema:"Comma-separated extensions to filter by (e.g., 'png,pdf')"`
+}
+
+// MoveAttachmentArgs arguments for move-attachment
+type MoveAttachmentArgs struct {
+	Source      string `json:"source" jsonschema:"Attachment path relative to vault root"`
+	Destination string `json:"destination" jsonschema:"New path, or a folder to move into"`
+	DryRun      bool   `json:"dry_run,omitempty" jsonschema:"Preview move without modifying files"`
+}
+
+// ImportAttachmentArgs arguments for import-attachment
+type ImportAttachmentArgs struct {
+	Source string `json:"source" jsonschema:"Absolute path of the file to import, inside an allowed import path"`
+	Note   string `json:"note,omitempty" jsonschema:"Note the file is for (optional)"`
+	Name   string `json:"name,omitempty" jsonschema:"File name in the vault (default: source file name)"`
+}
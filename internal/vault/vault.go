@@ -16,6 +16,14 @@ type Vault struct {
 	mu            sync.RWMutex
 	activePath    string
 	allowedVaults map[string]string
+	index         *noteIndex
+	fts           ftsStore
+	vectors       vectorStore
+	pathChanged   chan struct{}
+
+	ignorePatterns []string
+	importPaths    []string
+	embedder       Embedder
 }
 
 // New creates a new Vault instance
@@ -24,6 +32,8 @@ func New(path string) *Vault {
 	return &Vault{
 		activePath:    cleanPath,
 		allowedVaults: make(map[string]string),
+		index:         newNoteIndex(cleanPath),
+		pathChanged:   make(chan struct{}, 1),
 	}
 }
 
@@ -60,6 +70,15 @@ func (v *Vault) SetPath(path string) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.activePath = cleanPath
+	v.index.reset(cleanPath)
+	v.fts.reset()
+	v.vectors.reset()
+
+	// Wake a running watcher so it restarts on the new root.
+	select {
+	case v.pathChanged <- struct{}{}:
+	default:
+	}
 }
 
 // isPathSafe checks if the given path is within the vault (prevents path traversal)
@@ -136,7 +155,7 @@ func (v *Vault) ListNotesHandler(ctx context.Context, req *mcp.CallToolRequest,
 	}
 
 	var notes []string
-	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+	err := v.walkVault(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip errors
 		}
@@ -267,9 +286,25 @@ func (v *Vault) ReadNoteHandler(ctx context.Context, req *mcp.CallToolRequest, a
 		return nil, nil, fmt.Errorf("failed to read note: %v", err)
 	}
 
+	text := string(content)
+	var attachments []linkResolution
+	if args.ResolveEmbeds {
+		relPath, _ := filepath.Rel(v.GetPath(), fullPath)
+		text, attachments, err = v.resolveEmbeds(text, relPath, args.EmbedDepth)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve embeds: %v", err)
+		}
+	}
+	if args.RenderQueries {
+		text = v.renderQueryBlocks(text)
+	}
+	if len(attachments) > 0 {
+		text = strings.TrimRight(text, "\n") + "\n\n" + formatAttachments(attachments) + "\n"
+	}
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: string(content)},
+			&mcp.TextContent{Text: text},
 		},
 	}, nil, nil
 }
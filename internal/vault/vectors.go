@@ -0,0 +1,420 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// vectorIndexVersion is bumped whenever chunking or the on-disk layout
+	// changes, forcing a full rebuild of persisted indexes.
+	vectorIndexVersion = 1
+	vectorIndexFile    = "vectors.gob"
+
+	// maxChunkChars is the size above which a section is split at blank lines.
+	maxChunkChars = 2000
+
+	// lsaRefitRatio is the share of chunks that may be embedded with a stale
+	// LSA model before it is refitted to the whole vault.
+	lsaRefitRatio = 0.2
+)
+
+// noteChunk is a section of a note, embedded separately so a long note can
+// match on any one of its parts.
+type noteChunk struct {
+	Heading string
+	Line    int // 1-based line in the note where the chunk starts
+	Text    string
+}
+
+// chunkNote splits a note into one chunk per heading section, splitting long
+// sections at blank lines. Each chunk's text starts with the note title and
+// heading so they count towards its meaning.
+func chunkNote(note *indexedNote) []noteChunk {
+	title := strings.TrimSuffix(filepath.Base(note.RelPath), ".md")
+	lines := note.Lines
+	var chunks []noteChunk
+
+	heading := ""
+	start := frontmatterLineCount(lines)
+	flush := func(end int) {
+		for _, part := range splitSection(lines, start, end) {
+			text := strings.TrimSpace(strings.Join(lines[part[0]:part[1]], "\n"))
+			if text == "" {
+				continue
+			}
+			chunks = append(chunks, noteChunk{
+				Heading: heading,
+				Line:    part[0] + 1,
+				Text:    title + "\n" + text,
+			})
+		}
+	}
+
+	for i := start; i < len(lines); i++ {
+		if fence, _ := codeFence(lines[i]); fence != "" {
+			if end := closingFence(lines, i+1, fence); end >= 0 {
+				i = end
+			}
+			continue
+		}
+		if m := headingRegex.FindStringSubmatch(strings.TrimSpace(lines[i])); m != nil {
+			flush(i)
+			heading = strings.TrimSpace(m[2])
+			start = i
+		}
+	}
+	flush(len(lines))
+
+	if len(chunks) == 0 {
+		chunks = append(chunks, noteChunk{Line: 1, Text: title})
+	}
+	return chunks
+}
+
+// splitSection returns [start, end) line ranges covering lines[start:end],
+// breaking at blank lines so each range stays under maxChunkChars where
+// possible. A single paragraph longer than that is kept whole.
+func splitSection(lines []string, start, end int) [][2]int {
+	var parts [][2]int
+	partStart, size := start, 0
+	for i := start; i < end; i++ {
+		if strings.TrimSpace(lines[i]) == "" && size > maxChunkChars {
+			parts = append(parts, [2]int{partStart, i})
+			partStart, size = i+1, 0
+			continue
+		}
+		size += len(lines[i]) + 1
+	}
+	if partStart < end {
+		parts = append(parts, [2]int{partStart, end})
+	}
+	return parts
+}
+
+// vectorChunk is an embedded chunk.
+type vectorChunk struct {
+	Heading string
+	Line    int
+	Vector  []float32
+}
+
+// vectorDoc is the per-note state of the vector index.
+type vectorDoc struct {
+	ModTime time.Time
+	Size    int64
+	Chunks  []vectorChunk
+}
+
+// vector returns the normalized mean of a note's chunk vectors, standing for
+// the note as a whole.
+func (doc *vectorDoc) vector() []float32 {
+	if len(doc.Chunks) == 0 {
+		return nil
+	}
+	mean := make([]float32, len(doc.Chunks[0].Vector))
+	for _, c := range doc.Chunks {
+		for i, x := range c.Vector {
+			if i < len(mean) {
+				mean[i] += x
+			}
+		}
+	}
+	return normalizeVector(mean)
+}
+
+// vectorIndex holds chunk embeddings for every note in a vault, along with
+// the LSA model that produced them when the built-in embedder is in use.
+type vectorIndex struct {
+	Version  int
+	Embedder string
+	LSA      *lsaEmbedder
+	Docs     map[string]*vectorDoc // keyed by vault-relative path
+	Drift    int                   // chunks embedded since the LSA model was fitted
+}
+
+func newVectorIndex(embedder string) *vectorIndex {
+	return &vectorIndex{
+		Version:  vectorIndexVersion,
+		Embedder: embedder,
+		Docs:     make(map[string]*vectorDoc),
+	}
+}
+
+// vectorStore owns the vector index of the active vault, loading it from
+// disk on first use and persisting it after updates.
+type vectorStore struct {
+	mu      sync.Mutex
+	root    string
+	idx     *vectorIndex
+	unsaved bool // the last save failed, so retry on the next use
+}
+
+// reset forgets the loaded index so the next use reloads it for the new root.
+func (s *vectorStore) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.root = ""
+	s.idx = nil
+}
+
+func vectorIndexPath(root string) string {
+	return filepath.Join(root, filepath.FromSlash(ftsIndexDir), vectorIndexFile)
+}
+
+// hasVectorIndex reports whether the vault's vector index has been built.
+func (v *Vault) hasVectorIndex() bool {
+	_, err := os.Stat(vectorIndexPath(v.GetPath()))
+	return err == nil
+}
+
+// loadVectorIndex reads a persisted index, returning an empty one when it is
+// missing, unreadable, from an older version or built by another embedder.
+func loadVectorIndex(root, embedder string) *vectorIndex {
+	data, err := os.ReadFile(vectorIndexPath(root))
+	if err != nil {
+		return newVectorIndex(embedder)
+	}
+
+	idx := newVectorIndex(embedder)
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(idx); err != nil || idx.Version != vectorIndexVersion {
+		return newVectorIndex(embedder)
+	}
+	if idx.Docs == nil || idx.Embedder != embedder {
+		return newVectorIndex(embedder)
+	}
+	return idx
+}
+
+// saveVectorIndex persists the index, writing a temp file and renaming it so
+// a crash never leaves a truncated index behind.
+func saveVectorIndex(root string, idx *vectorIndex) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(idx); err != nil {
+		return err
+	}
+
+	path := vectorIndexPath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// sync brings the index up to date with notes, embedding only notes whose
+// mtime or size changed. With the built-in embedder (embedder == nil) the
+// LSA model is refitted to the whole vault once enough chunks have changed
+// since it was last fitted. It reports whether anything changed.
+func (idx *vectorIndex) sync(ctx context.Context, notes []*indexedNote, embedder Embedder) (bool, error) {
+	seen := make(map[string]bool, len(notes))
+	isStale := make(map[string]bool)
+	var stale []*indexedNote
+	for _, note := range notes {
+		seen[note.RelPath] = true
+		if doc, ok := idx.Docs[note.RelPath]; ok && doc.ModTime.Equal(note.ModTime) && doc.Size == note.Size {
+			continue
+		}
+		isStale[note.RelPath] = true
+		stale = append(stale, note)
+	}
+	changed := false
+	for relPath := range idx.Docs {
+		if !seen[relPath] {
+			delete(idx.Docs, relPath)
+			changed = true
+		}
+	}
+	if len(stale) == 0 {
+		return changed, nil
+	}
+
+	staleChunks := make([][]noteChunk, len(stale))
+	staleCount := 0
+	for i, note := range stale {
+		staleChunks[i] = chunkNote(note)
+		staleCount += len(staleChunks[i])
+	}
+
+	if embedder == nil {
+		total := staleCount
+		for relPath, doc := range idx.Docs {
+			if !isStale[relPath] {
+				total += len(doc.Chunks)
+			}
+		}
+		if idx.LSA == nil || float64(idx.Drift+staleCount) > lsaRefitRatio*float64(total) {
+			return true, idx.refitLSA(ctx, notes)
+		}
+		idx.Drift += staleCount
+		embedder = idx.LSA
+	}
+
+	if err := idx.embedNotes(ctx, stale, staleChunks, embedder); err != nil {
+		return changed, err
+	}
+	return true, nil
+}
+
+// refitLSA fits a new LSA model to every note and re-embeds them all.
+func (idx *vectorIndex) refitLSA(ctx context.Context, notes []*indexedNote) error {
+	chunks := make([][]noteChunk, len(notes))
+	var texts []string
+	for i, note := range notes {
+		chunks[i] = chunkNote(note)
+		for _, c := range chunks[i] {
+			texts = append(texts, c.Text)
+		}
+	}
+	idx.LSA = fitLSA(texts)
+	idx.Docs = make(map[string]*vectorDoc, len(notes))
+	idx.Drift = 0
+	return idx.embedNotes(ctx, notes, chunks, idx.LSA)
+}
+
+// embedNotes embeds the chunks of notes in one batch and stores them.
+func (idx *vectorIndex) embedNotes(ctx context.Context, notes []*indexedNote, chunks [][]noteChunk, embedder Embedder) error {
+	var texts []string
+	for _, cs := range chunks {
+		for _, c := range cs {
+			texts = append(texts, c.Text)
+		}
+	}
+	vectors, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(texts) {
+		return fmt.Errorf("embedder returned %d vectors for %d chunks", len(vectors), len(texts))
+	}
+
+	n := 0
+	for i, note := range notes {
+		doc := &vectorDoc{ModTime: note.ModTime, Size: note.Size, Chunks: make([]vectorChunk, len(chunks[i]))}
+		for j, c := range chunks[i] {
+			doc.Chunks[j] = vectorChunk{Heading: c.Heading, Line: c.Line, Vector: vectors[n]}
+			n++
+		}
+		idx.Docs[note.RelPath] = doc
+	}
+	return nil
+}
+
+// vectorHit is a note scored by similarity, with the chunk that matched best.
+type vectorHit struct {
+	path  string
+	score float64
+	chunk vectorChunk
+}
+
+// rank scores notes under prefix against query, best first, leaving out
+// exclude. Notes are scored by their best chunk, or by the note as a whole
+// when wholeNote is set.
+func (idx *vectorIndex) rank(query []float32, prefix, exclude string, wholeNote bool) []vectorHit {
+	var hits []vectorHit
+	for path, doc := range idx.Docs {
+		if path == exclude || !pathHasPrefix(path, prefix) || len(doc.Chunks) == 0 {
+			continue
+		}
+		hit := vectorHit{path: path, score: -1}
+		for _, c := range doc.Chunks {
+			if score := cosine(query, c.Vector); score > hit.score {
+				hit.score, hit.chunk = score, c
+			}
+		}
+		if wholeNote {
+			hit.score = cosine(query, doc.vector())
+		}
+		if hit.score > 0 {
+			hits = append(hits, hit)
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score == hits[j].score {
+			return hits[i].path < hits[j].path
+		}
+		return hits[i].score > hits[j].score
+	})
+	return hits
+}
+
+// withVectorIndex syncs the vault's vector index with the note index,
+// persisting it when anything changed, and calls fn with it and the embedder
+// for queries. The index is locked while fn runs. Saving is best-effort, as
+// the in-memory index is already up to date.
+func (v *Vault) withVectorIndex(ctx context.Context, fn func(idx *vectorIndex, embedder Embedder) error) error {
+	root := v.GetPath()
+	notes, err := v.indexedNotes(root)
+	if err != nil {
+		return err
+	}
+	embedder := v.getEmbedder()
+	name := "lsa"
+	if embedder != nil {
+		name = embedder.Name()
+	}
+
+	v.vectors.mu.Lock()
+	defer v.vectors.mu.Unlock()
+	if v.vectors.idx == nil || v.vectors.root != root || v.vectors.idx.Embedder != name {
+		v.vectors.root = root
+		v.vectors.idx = loadVectorIndex(root, name)
+	}
+	idx := v.vectors.idx
+	changed, err := idx.sync(ctx, notes, embedder)
+	if err != nil {
+		// Notes that failed to embed stay stale and are retried next time.
+		return fmt.Errorf("failed to embed notes: %v", err)
+	}
+	if changed || v.vectors.unsaved {
+		v.vectors.unsaved = saveVectorIndex(root, idx) != nil
+	}
+	if embedder == nil {
+		embedder = idx.LSA
+	}
+	return fn(idx, embedder)
+}
+
+// relatedNotes returns the notes whose content is most similar to relPath.
+func (v *Vault) relatedNotes(ctx context.Context, relPath, prefix string) ([]vectorHit, error) {
+	var hits []vectorHit
+	err := v.withVectorIndex(ctx, func(idx *vectorIndex, _ Embedder) error {
+		doc, ok := idx.Docs[relPath]
+		if !ok {
+			return fmt.Errorf("note not indexed: %s", relPath)
+		}
+		hits = idx.rank(doc.vector(), prefix, relPath, true)
+		return nil
+	})
+	return hits, err
+}
+
+// chunkSnippet returns the first line of text in the chunk starting at line,
+// skipping its heading.
+func chunkSnippet(note *indexedNote, line, maxLen int) (int, string) {
+	for i := line - 1; i >= 0 && i < len(note.Lines); i++ {
+		text := strings.TrimSpace(note.Lines[i])
+		if text == "" || text == "---" {
+			continue
+		}
+		if headingRegex.MatchString(text) {
+			if i > line-1 {
+				break
+			}
+			continue
+		}
+		return i + 1, truncate(text, maxLen)
+	}
+	return line, ""
+}
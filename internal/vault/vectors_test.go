@@ -0,0 +1,372 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// writeSemanticVault writes notes on two topics where some notes share no
+// words with others on the same topic.
+func writeSemanticVault(t *testing.T, dir string) {
+	t.Helper()
+	writeTestFile(t, dir, "pets/dogs.md", "# Dogs\n\nMy dog loves the puppy park. The dog pulls the leash on every walk.")
+	writeTestFile(t, dir, "pets/training.md", "# Training\n\nThe puppy learns to heel on the leash during each walk.")
+	writeTestFile(t, dir, "pets/canine.md", "# Canine care\n\nA puppy needs a leash, treats and patience.")
+	writeTestFile(t, dir, "sea/sailing.md", "# Sailing\n\nOur boat crossed the harbor as waves rolled in from the ocean.")
+	writeTestFile(t, dir, "sea/harbor.md", "# Harbor\n\nFishing boats wait in the harbor for calm waves.")
+	writeTestFile(t, dir, "sea/voyage.md", "# Voyage\n\nA long ocean voyage by boat, riding the waves.")
+}
+
+func semanticResults(t *testing.T, result *mcp.CallToolResult) []SemanticResult {
+	t.Helper()
+	var data struct {
+		Results []SemanticResult `json:"results"`
+	}
+	compactData(t, result, &data)
+	return data.Results
+}
+
+func TestChunkNote(t *testing.T) {
+	content := "---\ntags: [x]\n---\nIntro line\n\n## First\n\nAlpha text\n\n```\n# not a heading\n```\n\n## Second\nBeta text"
+	note := &indexedNote{RelPath: "folder/Note.md", Lines: strings.Split(content, "\n")}
+	chunks := chunkNote(note)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d: %+v", len(chunks), chunks)
+	}
+	if chunks[0].Heading != "" || chunks[0].Line != 4 || !strings.HasPrefix(chunks[0].Text, "Note\nIntro line") {
+		t.Errorf("unexpected intro chunk: %+v", chunks[0])
+	}
+	if chunks[1].Heading != "First" || chunks[1].Line != 6 || !strings.Contains(chunks[1].Text, "# not a heading") {
+		t.Errorf("unexpected first chunk: %+v", chunks[1])
+	}
+	if chunks[2].Heading != "Second" || chunks[2].Line != 14 {
+		t.Errorf("unexpected second chunk: %+v", chunks[2])
+	}
+}
+
+func TestChunkNoteSplitsLongSections(t *testing.T) {
+	para := strings.Repeat("word ", 300)
+	note := &indexedNote{RelPath: "long.md", Lines: strings.Split("## Long\n"+para+"\n\n"+para+"\n\n"+para, "\n")}
+	chunks := chunkNote(note)
+	if len(chunks) < 2 {
+		t.Fatalf("expected long section to be split, got %d chunks", len(chunks))
+	}
+	for _, c := range chunks {
+		if c.Heading != "Long" {
+			t.Errorf("expected every part to keep its heading, got %q", c.Heading)
+		}
+	}
+}
+
+func TestJacobiEigen(t *testing.T) {
+	values, vectors := jacobiEigen([][]float64{{2, 1}, {1, 2}})
+	if math.Abs(values[0]+values[1]-4) > 1e-9 || math.Abs(values[0]*values[1]-3) > 1e-9 {
+		t.Fatalf("expected eigenvalues 1 and 3, got %v", values)
+	}
+	for c := range 2 {
+		// A v = λ v
+		x, y := vectors[0][c], vectors[1][c]
+		if math.Abs(2*x+y-values[c]*x) > 1e-9 || math.Abs(x+2*y-values[c]*y) > 1e-9 {
+			t.Errorf("column %d is not an eigenvector: (%v, %v)", c, x, y)
+		}
+	}
+}
+
+func TestSemanticSearchFindsRelatedWords(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeSemanticVault(t, dir)
+
+	result, _, err := v.SemanticSearchHandler(context.Background(), nil, SemanticSearchArgs{Query: "dog"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := semanticResults(t, result)
+	if len(results) < 3 {
+		t.Fatalf("expected the dog notes, got %+v", results)
+	}
+	top := map[string]bool{}
+	for _, r := range results[:3] {
+		top[r.File] = true
+	}
+	// canine.md never says "dog" but shares puppy and leash with notes that do.
+	for _, want := range []string{"pets/dogs.md", "pets/training.md", "pets/canine.md"} {
+		if !top[want] {
+			t.Errorf("expected %s in the top results, got %+v", want, results)
+		}
+	}
+	if results[0].Snippet == "" || results[0].Heading == "" {
+		t.Errorf("expected heading and snippet, got %+v", results[0])
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".obx", "index", "vectors.gob")); err != nil {
+		t.Errorf("expected persisted vector index: %v", err)
+	}
+
+	result, _, err = v.SemanticSearchHandler(context.Background(), nil, SemanticSearchArgs{Query: "dog", Directory: "sea"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range semanticResults(t, result) {
+		if !strings.HasPrefix(r.File, "sea/") {
+			t.Errorf("expected results under sea/, got %s", r.File)
+		}
+	}
+}
+
+func TestSimilarNotes(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeSemanticVault(t, dir)
+
+	result, _, err := v.SimilarNotesHandler(context.Background(), nil, SimilarNotesArgs{Path: "voyage", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := semanticResults(t, result)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+	for _, r := range results {
+		if !strings.HasPrefix(r.File, "sea/") || r.File == "sea/voyage.md" {
+			t.Errorf("expected the other sea notes, got %+v", results)
+		}
+	}
+
+	if _, _, err := v.SimilarNotesHandler(context.Background(), nil, SimilarNotesArgs{Path: "missing"}); err == nil {
+		t.Error("expected error for missing note")
+	}
+}
+
+func TestVectorIndexUpdatesIncrementally(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeSemanticVault(t, dir)
+	ctx := context.Background()
+
+	// Adding many notes refits the model to the whole vault.
+	for i := range 30 {
+		writeTestFile(t, dir, fmt.Sprintf("misc/note%d.md", i), "Puppy and boat notes.")
+	}
+	var fitted *lsaEmbedder
+	if err := v.withVectorIndex(ctx, func(idx *vectorIndex, _ Embedder) error {
+		fitted = idx.LSA
+		if idx.Drift != 0 || len(idx.Docs) != 36 {
+			t.Errorf("expected a fresh fit over 36 notes, got drift %d and %d notes", idx.Drift, len(idx.Docs))
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// One changed chunk out of many keeps the fitted model.
+	writeTestFile(t, dir, "pets/dogs.md", "# Dogs\n\nThe dog sleeps.")
+	if err := v.withVectorIndex(ctx, func(idx *vectorIndex, _ Embedder) error {
+		if idx.LSA != fitted {
+			t.Error("expected a small change to reuse the LSA model")
+		}
+		if idx.Drift != 1 {
+			t.Errorf("expected drift of 1 chunk, got %d", idx.Drift)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// keywordEmbedder embeds text by counting topic words, or fails with err.
+type keywordEmbedder struct {
+	calls int
+	err   error
+}
+
+func (e *keywordEmbedder) Name() string { return "keywords" }
+
+func (e *keywordEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		text = strings.ToLower(text)
+		vectors[i] = normalizeVector([]float32{
+			float32(strings.Count(text, "puppy") + strings.Count(text, "dog")),
+			float32(strings.Count(text, "boat") + strings.Count(text, "ocean")),
+			0.01,
+		})
+	}
+	return vectors, nil
+}
+
+func TestSemanticSearchUsesEmbedder(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeSemanticVault(t, dir)
+
+	if _, _, err := v.SemanticSearchHandler(context.Background(), nil, SemanticSearchArgs{Query: "dog"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Switching embedders rebuilds the index with the new one.
+	embedder := &keywordEmbedder{}
+	v.SetEmbedder(embedder)
+	result, _, err := v.SemanticSearchHandler(context.Background(), nil, SemanticSearchArgs{Query: "a boat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data struct {
+		Embedder string           `json:"embedder"`
+		Results  []SemanticResult `json:"results"`
+	}
+	compactData(t, result, &data)
+	if data.Embedder != "keywords" {
+		t.Errorf("expected keywords embedder, got %q", data.Embedder)
+	}
+	if len(data.Results) == 0 || !strings.HasPrefix(data.Results[0].File, "sea/") {
+		t.Errorf("expected a sea note first, got %+v", data.Results)
+	}
+
+	// Unchanged notes are not embedded again; only the query is.
+	calls := embedder.calls
+	if _, _, err := v.SemanticSearchHandler(context.Background(), nil, SemanticSearchArgs{Query: "puppy"}); err != nil {
+		t.Fatal(err)
+	}
+	if embedder.calls != calls+1 {
+		t.Errorf("expected only the query to be embedded, got %d calls", embedder.calls-calls)
+	}
+}
+
+func TestSuggestLinksIncludesRelatedContent(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeSemanticVault(t, dir)
+	writeTestFile(t, dir, "pets/training.md", "# Training\n\nThe puppy learns to heel on the leash during each walk. See [[dogs]].")
+
+	ctx := context.Background()
+
+	// Without an index, suggestions stay lexical and nothing is embedded
+	result, _, err := v.SuggestLinksHandler(ctx, nil, SuggestLinksArgs{Path: "pets/training"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; strings.Contains(text, "related content") {
+		t.Errorf("expected no semantic suggestions without an index, got:\n%s", text)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".obx", "index", "vectors.gob")); !os.IsNotExist(err) {
+		t.Errorf("suggest should not build the vector index unless asked, got %v", err)
+	}
+
+	result, _, err = v.SuggestLinksHandler(ctx, nil, SuggestLinksArgs{Path: "pets/training", Semantic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "[[pets/canine]] - related content") {
+		t.Errorf("expected canine suggested by content, got:\n%s", text)
+	}
+	if strings.Contains(text, "[[pets/dogs]]") {
+		t.Errorf("expected already linked note to be skipped, got:\n%s", text)
+	}
+	if strings.Contains(text, "sea/") {
+		t.Errorf("expected unrelated notes to be left out, got:\n%s", text)
+	}
+	// Once the index exists it is used by default
+	result, _, err = v.SuggestLinksHandler(ctx, nil, SuggestLinksArgs{Path: "pets/training"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "related content") {
+		t.Errorf("expected the existing index to be used, got:\n%s", text)
+	}
+}
+
+func TestSuggestLinksReportsEmbedderErrors(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeSemanticVault(t, dir)
+	ctx := context.Background()
+	if _, _, err := v.SuggestLinksHandler(ctx, nil, SuggestLinksArgs{Path: "pets/training", Semantic: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	v.SetEmbedder(&keywordEmbedder{err: errors.New("connection refused")})
+	if _, _, err := v.SuggestLinksHandler(ctx, nil, SuggestLinksArgs{Path: "pets/training", Semantic: true}); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected the embedder error when semantic is requested, got %v", err)
+	}
+	if _, _, err := v.SuggestLinksHandler(ctx, nil, SuggestLinksArgs{Path: "pets/training"}); err != nil {
+		t.Errorf("an existing index that cannot be updated should fall back to mentions: %v", err)
+	}
+}
+
+func TestSemanticSearchWithUnwritableIndex(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeSemanticVault(t, dir)
+	// A directory in place of the temp file stops the save even as root
+	if err := os.MkdirAll(vectorIndexPath(dir)+".tmp", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	result, _, err := v.SemanticSearchHandler(context.Background(), nil, SemanticSearchArgs{Query: "dog"})
+	if err != nil {
+		t.Fatalf("search should not fail when the index cannot be saved: %v", err)
+	}
+	if len(semanticResults(t, result)) == 0 {
+		t.Error("expected results from the in-memory index")
+	}
+	if _, err := os.Stat(vectorIndexPath(dir)); !os.IsNotExist(err) {
+		t.Errorf("expected no saved index, got %v", err)
+	}
+}
+
+func TestHTTPEmbedder(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization")
+		var body struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Model != "test-model" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		type item struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+		var data []item
+		// Answer out of order; the embedder must sort by index.
+		for i := len(body.Input) - 1; i >= 0; i-- {
+			data = append(data, item{Index: i, Embedding: []float32{float32(len(body.Input[i])), 0}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	defer server.Close()
+
+	e := NewHTTPEmbedder(server.URL+"/v1/", "test-model", "secret")
+	vectors, err := e.Embed(context.Background(), []string{"a", "bb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("expected bearer auth, got %q", gotAuth)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][0] != 1 {
+		t.Errorf("expected normalized vectors in input order, got %v", vectors)
+	}
+
+	bad := NewHTTPEmbedder(server.URL+"/v1", "other-model", "")
+	if _, err := bad.Embed(context.Background(), []string{"a"}); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected error for failed request, got %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// obxIgnoreFile is the vault-root file holding extra gitignore-style patterns.
+const obxIgnoreFile = ".obxignore"
+
+// defaultIgnorePatterns are skipped in every vault. A .obxignore or --ignore
+// pattern can re-include one with a "!" rule.
+var defaultIgnorePatterns = []string{
+	".obsidian/",
+	".trash/",
+	".git/",
+	".obx/",
+	"node_modules/",
+}
+
+// ignoreRule is one compiled gitignore-style pattern.
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// vaultIgnore decides which vault paths are skipped by walks. It combines the
+// built-in patterns, Obsidian's "Excluded files" (userIgnoreFilters in
+// app.json), .obxignore and patterns passed on the command line.
+type vaultIgnore struct {
+	rules   []ignoreRule
+	filters []string         // Obsidian path prefixes
+	regexps []*regexp.Regexp // Obsidian /regex/ filters
+}
+
+// SetIgnorePatterns adds gitignore-style patterns that every vault walk skips,
+// on top of .obxignore and Obsidian's excluded files.
+func (v *Vault) SetIgnorePatterns(patterns []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.ignorePatterns = patterns
+}
+
+// vaultIgnore loads the ignore rules for the active vault. Files are re-read
+// on every walk so edits in Obsidian or to .obxignore apply immediately.
+func (v *Vault) vaultIgnore() *vaultIgnore {
+	root := v.GetPath()
+	v.mu.RLock()
+	extra := v.ignorePatterns
+	v.mu.RUnlock()
+
+	m := &vaultIgnore{}
+	m.addPatterns(defaultIgnorePatterns)
+
+	var app struct {
+		UserIgnoreFilters []string `json:"userIgnoreFilters"`
+	}
+	if readSettingsFile(filepath.Join(root, ".obsidian", "app.json"), &app) {
+		for _, filter := range app.UserIgnoreFilters {
+			m.addObsidianFilter(filter)
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(root, obxIgnoreFile)); err == nil {
+		m.addPatterns(strings.Split(string(data), "\n"))
+	}
+	m.addPatterns(extra)
+	return m
+}
+
+// addObsidianFilter adds one "Excluded files" entry. Obsidian treats entries
+// wrapped in slashes as regular expressions and everything else as a path
+// prefix.
+func (m *vaultIgnore) addObsidianFilter(filter string) {
+	if filter == "" {
+		return
+	}
+	if len(filter) > 2 && strings.HasPrefix(filter, "/") && strings.HasSuffix(filter, "/") {
+		if re, err := regexp.Compile(filter[1 : len(filter)-1]); err == nil {
+			m.regexps = append(m.regexps, re)
+		}
+		return
+	}
+	m.filters = append(m.filters, filter)
+}
+
+// addPatterns compiles gitignore-style lines, skipping blanks and comments.
+func (m *vaultIgnore) addPatterns(lines []string) {
+	for _, line := range lines {
+		if rule, ok := compileIgnorePattern(line); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+}
+
+// compileIgnorePattern turns a gitignore line into a rule. Patterns without a
+// slash (other than a trailing one) match at any depth; the rest are anchored
+// to the vault root.
+func compileIgnorePattern(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(strings.TrimSuffix(line, "\r"), " ")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	var sb strings.Builder
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case strings.HasPrefix(line[i:], "**/") && (i == 0 || line[i-1] == '/'):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(line[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := line[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(line):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(line[i])))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// match reports whether a vault-relative, slash-separated path is ignored.
+// Parents are not checked: walks skip an ignored directory as a whole, so
+// its contents are never reached, as in git.
+func (m *vaultIgnore) match(relPath string, isDir bool) bool {
+	probe := relPath
+	if isDir {
+		probe += "/"
+	}
+	for _, filter := range m.filters {
+		if strings.HasPrefix(probe, filter) {
+			return true
+		}
+	}
+	for _, re := range m.regexps {
+		if re.MatchString(relPath) {
+			return true
+		}
+	}
+
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(relPath) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// ignored reports whether a vault-relative, slash-separated file path is
+// skipped by walks, either itself or because a folder above it is.
+func (m *vaultIgnore) ignored(relPath string) bool {
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.match(relPath, false)
+}
+
+// walkVault walks root like filepath.Walk, but never visits ignored files and
+// skips ignored directories entirely. root itself is always visited.
+func (v *Vault) walkVault(root string, fn filepath.WalkFunc) error {
+	vaultRoot := v.GetPath()
+	ignore := v.vaultIgnore()
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && path != root {
+			if relPath, relErr := filepath.Rel(vaultRoot, path); relErr == nil && ignore.match(filepath.ToSlash(relPath), info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		return fn(path, info, err)
+	})
+}
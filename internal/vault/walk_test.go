@@ -0,0 +1,92 @@
+package vault
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCompileIgnorePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"*.log", "a/b/debug.log", false, true},
+		{"*.log", "debug.md", false, false},
+		{"drafts/", "notes/drafts", true, true},
+		{"drafts/", "notes/drafts", false, false},
+		{"/top.md", "top.md", false, true},
+		{"/top.md", "sub/top.md", false, false},
+		{"docs/*.md", "docs/a.md", false, true},
+		{"docs/*.md", "docs/sub/a.md", false, false},
+		{"docs/**/*.md", "docs/sub/deep/a.md", false, true},
+		{"**/private", "x/y/private", true, true},
+		{"note?.md", "note1.md", false, true},
+		{"note[!0-9].md", "note1.md", false, false},
+	}
+	for _, tt := range tests {
+		m := &vaultIgnore{}
+		m.addPatterns([]string{tt.pattern})
+		if got := m.match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("%q match(%q, dir=%v) = %v, want %v", tt.pattern, tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	m := &vaultIgnore{}
+	m.addPatterns([]string{"# comment", "", "*.md", "!keep.md"})
+	if m.match("keep.md", false) || !m.match("drop.md", false) {
+		t.Error("negated pattern should re-include keep.md only")
+	}
+}
+
+func TestWalkVaultHonorsIgnores(t *testing.T) {
+	v, dir := setupTestVault(t)
+	for _, name := range []string{
+		"keep.md",
+		"Archive/old.md",
+		"Archives.md",
+		"drawing.excalidraw.md",
+		"scratch/tmp.md",
+		"private/secret.md",
+		"private/shared.md",
+		".trash/deleted.md",
+		".obsidian/snippets/x.md",
+		"node_modules/pkg/README.md",
+	} {
+		writeTestFile(t, dir, name, "# "+name)
+	}
+	writeTestFile(t, dir, ".obsidian/app.json", `{"userIgnoreFilters": ["Archive/", "/\\.excalidraw\\.md$/"]}`)
+	writeTestFile(t, dir, ".obxignore", "# local rules\nscratch/\nprivate/*\n!private/shared.md\n")
+	v.SetIgnorePatterns([]string{"Archives.md"})
+
+	var got []string
+	err := v.walkVault(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && strings.HasSuffix(path, ".md") {
+			rel, _ := filepath.Rel(dir, path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	if want := []string{"keep.md", "private/shared.md"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("walked %v, want %v", got, want)
+	}
+
+	result, _, err := v.ListNotesHandler(context.Background(), nil, ListNotesArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; strings.Contains(text, "old.md") || strings.Contains(text, "secret.md") {
+		t.Errorf("list-notes included ignored notes:\n%s", text)
+	}
+}
@@ -0,0 +1,207 @@
+package vault
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval is the polling interval used when none is configured.
+const DefaultWatchInterval = 2 * time.Second
+
+// WatchOp is the kind of change detected by the watcher.
+type WatchOp string
+
+const (
+	WatchCreate WatchOp = "create"
+	WatchModify WatchOp = "modify"
+	WatchRename WatchOp = "rename"
+	WatchDelete WatchOp = "delete"
+)
+
+// WatchEvent describes a single change under the active vault.
+type WatchEvent struct {
+	Op      WatchOp
+	Path    string // vault-relative path
+	OldPath string // previous path, set for renames
+}
+
+// fileStamp is the per-file state compared between polls.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func (s fileStamp) equal(other fileStamp) bool {
+	return s.modTime.Equal(other.modTime) && s.size == other.size
+}
+
+// Watcher polls the active vault for note changes and applies them to the
+// in-memory index. Polling works on every filesystem and needs no extra
+// dependencies; the vault is re-scanned with stat calls only, and files are
+// read again only when their mtime or size changed.
+type Watcher struct {
+	v        *Vault
+	interval time.Duration
+
+	root     string
+	snapshot map[string]fileStamp
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// StartWatcher starts polling the active vault in the background. Switching
+// vaults via SetPath restarts the watcher on the new root. Call Stop to end it.
+func (v *Vault) StartWatcher(ctx context.Context, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Watcher{
+		v:        v,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w
+}
+
+// Stop ends the watcher and waits for the polling goroutine to exit.
+func (w *Watcher) Stop() {
+	w.once.Do(w.cancel)
+	<-w.done
+}
+
+func (w *Watcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.poll()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll()
+		case <-w.v.pathChanged:
+			w.poll()
+		}
+	}
+}
+
+// poll compares the vault against the previous snapshot, updates the index and
+// returns the detected changes. The first poll after start or after a vault
+// switch primes the index and reports no events.
+func (w *Watcher) poll() []WatchEvent {
+	root := w.v.GetPath()
+	current := w.v.snapshotVault(root)
+
+	if root != w.root || w.snapshot == nil {
+		w.root = root
+		w.snapshot = current
+		for relPath := range current {
+			_, _ = w.v.indexedNote(relPath)
+		}
+		return nil
+	}
+
+	events := diffSnapshots(w.snapshot, current)
+	w.snapshot = current
+	for _, ev := range events {
+		w.v.applyWatchEvent(ev)
+	}
+	return events
+}
+
+// applyWatchEvent updates cached parse state for a single change.
+func (v *Vault) applyWatchEvent(ev WatchEvent) {
+	switch ev.Op {
+	case WatchDelete:
+		v.index.remove(ev.Path)
+	case WatchRename:
+		v.index.remove(ev.OldPath)
+		_, _ = v.indexedNote(ev.Path)
+	default:
+		_, _ = v.indexedNote(ev.Path)
+	}
+}
+
+// snapshotVault records mtime and size for every note under root that is not
+// ignored.
+func (v *Vault) snapshotVault(root string) map[string]fileStamp {
+	snapshot := make(map[string]fileStamp)
+	_ = v.walkVault(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		relPath, _ := filepath.Rel(root, path)
+		snapshot[relPath] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return snapshot
+}
+
+// diffSnapshots turns two snapshots into events. A delete and a create with an
+// identical mtime and size are reported as a rename, since os.Rename preserves
+// both.
+func diffSnapshots(prev, current map[string]fileStamp) []WatchEvent {
+	var created, deleted []string
+	var events []WatchEvent
+
+	for relPath, stamp := range current {
+		old, ok := prev[relPath]
+		switch {
+		case !ok:
+			created = append(created, relPath)
+		case !old.equal(stamp):
+			events = append(events, WatchEvent{Op: WatchModify, Path: relPath})
+		}
+	}
+	for relPath := range prev {
+		if _, ok := current[relPath]; !ok {
+			deleted = append(deleted, relPath)
+		}
+	}
+	sort.Strings(created)
+	sort.Strings(deleted)
+
+	renamed := make(map[string]bool)
+	for _, oldPath := range deleted {
+		match := ""
+		for _, newPath := range created {
+			if renamed[newPath] || !prev[oldPath].equal(current[newPath]) {
+				continue
+			}
+			if match != "" {
+				match = "" // ambiguous, fall back to delete + create
+				break
+			}
+			match = newPath
+		}
+		if match != "" {
+			renamed[match] = true
+			events = append(events, WatchEvent{Op: WatchRename, Path: match, OldPath: oldPath})
+			continue
+		}
+		events = append(events, WatchEvent{Op: WatchDelete, Path: oldPath})
+	}
+	for _, newPath := range created {
+		if !renamed[newPath] {
+			events = append(events, WatchEvent{Op: WatchCreate, Path: newPath})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Path < events[j].Path
+	})
+	return events
+}
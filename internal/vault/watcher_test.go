@@ -0,0 +1,103 @@
+package vault
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcherPollDetectsChanges(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "keep.md", "keep")
+	writeTestFile(t, dir, "edit.md", "before")
+	writeTestFile(t, dir, "gone.md", "gone")
+	writeTestFile(t, dir, "old.md", "moving")
+
+	w := &Watcher{v: v}
+	if events := w.poll(); events != nil {
+		t.Fatalf("expected priming poll to report nothing, got %+v", events)
+	}
+	if got := v.index.len(); got != 4 {
+		t.Fatalf("expected priming poll to index 4 notes, got %d", got)
+	}
+
+	writeTestFile(t, dir, "edit.md", "after the edit")
+	if err := os.Remove(filepath.Join(dir, "gone.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "old.md"), filepath.Join(dir, "sub-new.md")); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, dir, "fresh.md", "brand new note")
+
+	events := w.poll()
+	want := map[string]WatchEvent{
+		"edit.md":    {Op: WatchModify, Path: "edit.md"},
+		"fresh.md":   {Op: WatchCreate, Path: "fresh.md"},
+		"gone.md":    {Op: WatchDelete, Path: "gone.md"},
+		"sub-new.md": {Op: WatchRename, Path: "sub-new.md", OldPath: "old.md"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for _, ev := range events {
+		if want[ev.Path] != ev {
+			t.Errorf("unexpected event %+v", ev)
+		}
+	}
+
+	note, err := v.indexedNote("edit.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Content != "after the edit" {
+		t.Errorf("expected index to hold updated content, got %q", note.Content)
+	}
+	if got := v.index.len(); got != 4 {
+		t.Errorf("expected 4 indexed notes after changes, got %d", got)
+	}
+}
+
+func TestWatcherRestartsOnVaultSwitch(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "a.md", "a")
+
+	w := &Watcher{v: v}
+	w.poll()
+
+	other := t.TempDir()
+	writeTestFile(t, other, "b.md", "b")
+	writeTestFile(t, other, "c.md", "c")
+	v.SetPath(other)
+
+	if events := w.poll(); events != nil {
+		t.Fatalf("expected switch to re-prime without events, got %+v", events)
+	}
+	if w.root != v.GetPath() {
+		t.Errorf("expected watcher root %q, got %q", v.GetPath(), w.root)
+	}
+	if got := v.index.len(); got != 2 {
+		t.Errorf("expected index to hold the new vault's 2 notes, got %d", got)
+	}
+}
+
+func TestWatcherStartStop(t *testing.T) {
+	v, dir := setupTestVault(t)
+	writeTestFile(t, dir, "a.md", "a")
+
+	w := v.StartWatcher(context.Background(), 10*time.Millisecond)
+	writeTestFile(t, dir, "b.md", "b")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for v.index.len() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	w.Stop()
+	w.Stop() // idempotent
+
+	if got := v.index.len(); got != 2 {
+		t.Errorf("expected running watcher to index new note, got %d entries", got)
+	}
+}